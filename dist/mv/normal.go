@@ -17,7 +17,13 @@
 
 package mv
 
-import "errors"
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
 
 // Normal is a multivariate normal distribution with full rank covariance.
 type Normal struct {
@@ -46,3 +52,37 @@ func NewNormal(loc, cov []float64) (n *Normal, err error) {
 func (n *Normal) Dim() int {
 	return len(n.Location)
 }
+
+// Sample draws k samples from the distribution. The covariance is factorised
+// by its eigen decomposition, so semi-definite covariances can be sampled.
+func (n *Normal) Sample(rnd *rand.Rand, k int) (x [][]float64, err error) {
+	d := n.Dim()
+	var eig mat.EigenSym
+	if !eig.Factorize(mat.NewSymDense(d, n.Covariance), true) {
+		err := errors.New("Covariance could not be factorised")
+		return nil, err
+	}
+	var f mat.Dense
+	eig.VectorsTo(&f)
+	for j, v := range eig.Values(nil) {
+		sd := math.Sqrt(math.Max(v, 0))
+		for i := 0; i < d; i++ {
+			f.Set(i, j, f.At(i, j)*sd)
+		}
+	}
+
+	x = make([][]float64, k)
+	z := make([]float64, d)
+	for i := range x {
+		for j := range z {
+			z[j] = rnd.NormFloat64()
+		}
+		x[i] = make([]float64, d)
+		v := mat.NewVecDense(d, x[i])
+		v.MulVec(&f, mat.NewVecDense(d, z))
+		for j := range x[i] {
+			x[i][j] += n.Location[j]
+		}
+	}
+	return x, nil
+}
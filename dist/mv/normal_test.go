@@ -18,6 +18,8 @@
 package mv_test
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/cshenton/seer/dist/mv"
@@ -89,3 +91,51 @@ func TestDim(t *testing.T) {
 		})
 	}
 }
+
+func TestSample(t *testing.T) {
+	tt := []struct {
+		name string
+		loc  []float64
+		cov  []float64
+	}{
+		{"1x1", []float64{1}, []float64{4}},
+		{"2x2 correlated", []float64{1, -1}, []float64{2, 1, 1, 2}},
+		{"2x2 singular", []float64{0, 0}, []float64{1, 1, 1, 1}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := mv.NewNormal(tc.loc, tc.cov)
+			if err != nil {
+				t.Fatal(err)
+			}
+			k := 20000
+			x, err := n.Sample(rand.New(rand.NewSource(1)), k)
+			if err != nil {
+				t.Fatal("unexpected error in Sample:", err)
+			}
+			if len(x) != k {
+				t.Fatalf("expected %v samples, but got %v", k, len(x))
+			}
+
+			d := n.Dim()
+			for i := 0; i < d; i++ {
+				var mean float64
+				for _, s := range x {
+					mean += s[i] / float64(k)
+				}
+				if math.Abs(mean-tc.loc[i]) > 0.05 {
+					t.Errorf("expected mean %v, but got %v", tc.loc[i], mean)
+				}
+				for j := 0; j < d; j++ {
+					var cov float64
+					for _, s := range x {
+						cov += (s[i] - tc.loc[i]) * (s[j] - tc.loc[j]) / float64(k)
+					}
+					if math.Abs(cov-tc.cov[i*d+j]) > 0.1 {
+						t.Errorf("expected covariance %v at (%v, %v), but got %v", tc.cov[i*d+j], i, j, cov)
+					}
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ package uv
 import (
 	"errors"
 	"math"
+	"math/rand"
 )
 
 // InverseGamma is the inverse gamma distribution.
@@ -50,3 +51,27 @@ func (i *InverseGamma) Mean() float64 {
 func (i *InverseGamma) Variance() float64 {
 	return math.Pow(i.Scale, 2) / (math.Pow(i.Shape-1, 2) * (i.Shape - 2))
 }
+
+// Sample draws a single sample from the distribution, as the reciprocal of a
+// gamma variate generated by the method of Marsaglia and Tsang.
+func (i *InverseGamma) Sample(rnd *rand.Rand) float64 {
+	shape := i.Shape
+	boost := 1.0
+	if shape < 1 {
+		boost = math.Pow(rnd.Float64(), 1/shape)
+		shape++
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		z := rnd.NormFloat64()
+		v := math.Pow(1+c*z, 3)
+		if v <= 0 {
+			continue
+		}
+		u := rnd.Float64()
+		if math.Log(u) < z*z/2+d-d*v+d*math.Log(v) {
+			return i.Scale / (d * v * boost)
+		}
+	}
+}
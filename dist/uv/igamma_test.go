@@ -18,6 +18,8 @@
 package uv_test
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/cshenton/seer/dist/uv"
@@ -113,3 +115,34 @@ func TestInverseGammaVariance(t *testing.T) {
 		})
 	}
 }
+
+func TestInverseGammaSample(t *testing.T) {
+	tt := []struct {
+		name  string
+		shape float64
+		scale float64
+	}{
+		{"Small", 0.5, 0.6},
+		{"Large", 30, 25},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			i, _ := uv.NewInverseGamma(tc.shape, tc.scale)
+			rnd := rand.New(rand.NewSource(1))
+
+			// The mean need not exist, so compare the mean of the reciprocal.
+			k := 20000
+			var mean float64
+			for j := 0; j < k; j++ {
+				x := i.Sample(rnd)
+				if x <= 0 {
+					t.Fatalf("expected positive sample, but got %v", x)
+				}
+				mean += 1 / x / float64(k)
+			}
+			if math.Abs(mean-tc.shape/tc.scale)/(tc.shape/tc.scale) > 0.05 {
+				t.Errorf("expected reciprocal mean %v, but got %v", tc.shape/tc.scale, mean)
+			}
+		})
+	}
+}
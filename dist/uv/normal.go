@@ -54,6 +54,11 @@ func (n *Normal) Variance() float64 {
 	return math.Pow(n.Scale, 2)
 }
 
+// CDF is the cumulative distribution function.
+func (n *Normal) CDF(x float64) float64 {
+	return math.Erfc(-(x-n.Location)/(n.Scale*math.Sqrt2)) / 2
+}
+
 // Quantile is the inverse function of the CDF.
 func (n *Normal) Quantile(p float64) (q float64, err error) {
 	if p < 0 || p > 1 {
@@ -113,6 +113,26 @@ func TestNormalQuantile(t *testing.T) {
 	}
 }
 
+func TestNormalCDF(t *testing.T) {
+	tt := []struct {
+		name string
+		x    float64
+		p    float64
+	}{
+		{"median", 1, 0.5},
+		{"upper", 1 + 2*1.959963984540054, 0.975},
+		{"lower", 1 - 2*1.959963984540054, 0.025},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			n, _ := uv.NewNormal(1, 2)
+			if math.Abs(n.CDF(tc.x)-tc.p) > 1e-8 {
+				t.Errorf("expected cdf %v, but got %v", tc.p, n.CDF(tc.x))
+			}
+		})
+	}
+}
+
 func TestNormalQuantileErrs(t *testing.T) {
 	loc := 0.0
 	scale := 1.0
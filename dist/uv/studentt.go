@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package uv
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+// StudentT is the location scale Student's t distribution.
+type StudentT struct {
+	Location float64
+	Scale    float64
+	Dof      float64
+}
+
+// NewStudentT checks the input parameters and returns a StudentT constructed
+// using them, if they are valid.
+func NewStudentT(location, scale, dof float64) (st *StudentT, err error) {
+	if scale <= 0 {
+		err := errors.New("scale must be strictly greater than zero")
+		return nil, err
+	}
+	if dof <= 0 {
+		err := errors.New("degrees of freedom must be strictly greater than zero")
+		return nil, err
+	}
+	st = &StudentT{
+		Location: location,
+		Scale:    scale,
+		Dof:      dof,
+	}
+	return st, nil
+}
+
+// Mean returns the first moment of the distribution, for dof > 1.
+func (st *StudentT) Mean() float64 {
+	return st.Location
+}
+
+// Variance returns the second central moment of the distribution, for dof > 2.
+func (st *StudentT) Variance() float64 {
+	return math.Pow(st.Scale, 2) * st.Dof / (st.Dof - 2)
+}
+
+// Quantile is the inverse function of the CDF.
+func (st *StudentT) Quantile(p float64) (q float64, err error) {
+	if p < 0 || p > 1 {
+		err := errors.New("probabilities must be between 0 and 1")
+		return q, err
+	}
+	if p == 0.5 {
+		return st.Location, nil
+	}
+	// The tail probability of t is an incomplete beta function of dof/(dof+t^2).
+	tail := math.Min(p, 1-p)
+	x := mathext.InvRegIncBeta(st.Dof/2, 0.5, 2*tail)
+	t := math.Sqrt(st.Dof * (1 - x) / x)
+	if p < 0.5 {
+		t = -t
+	}
+	q = st.Location + st.Scale*t
+	return q, nil
+}
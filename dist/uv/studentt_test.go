@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package uv_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cshenton/seer/dist/uv"
+)
+
+func TestNewStudentT(t *testing.T) {
+	tt := []struct {
+		name  string
+		loc   float64
+		scale float64
+		dof   float64
+	}{
+		{"standard", 0, 1, 1},
+		{"negative loc", -10, 2, 5},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			st, err := uv.NewStudentT(tc.loc, tc.scale, tc.dof)
+			if err != nil {
+				t.Error("unexpected error in NewStudentT,", err)
+			}
+			if st.Location != tc.loc {
+				t.Errorf("expected location %v, but got %v", tc.loc, st.Location)
+			}
+			if st.Scale != tc.scale {
+				t.Errorf("expected Scale %v, but got %v", tc.scale, st.Scale)
+			}
+			if st.Dof != tc.dof {
+				t.Errorf("expected Dof %v, but got %v", tc.dof, st.Dof)
+			}
+		})
+	}
+}
+
+func TestNewStudentTErrs(t *testing.T) {
+	tt := []struct {
+		name  string
+		loc   float64
+		scale float64
+		dof   float64
+	}{
+		{"zero scale", 10, 0, 1},
+		{"negative scale", 10, -2, 1},
+		{"zero dof", 10, 1, 0},
+		{"negative dof", 10, 1, -1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			st, err := uv.NewStudentT(tc.loc, tc.scale, tc.dof)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if st != nil {
+				t.Error("expected nil dist but it was", st)
+			}
+		})
+	}
+}
+
+func TestStudentTMeanAndVariance(t *testing.T) {
+	st, err := uv.NewStudentT(3, 2, 4)
+	if err != nil {
+		t.Error("unexpected error in NewStudentT,", err)
+	}
+	if st.Mean() != 3 {
+		t.Errorf("expected mean %v, but got %v", 3, st.Mean())
+	}
+	if st.Variance() != 8 {
+		t.Errorf("expected variance %v, but got %v", 8, st.Variance())
+	}
+}
+
+func TestStudentTQuantile(t *testing.T) {
+	tt := []struct {
+		name  string
+		dof   float64
+		p     float64
+		quant float64
+	}{
+		{"median", 3, 0.5, 0},
+		{"cauchy upper", 1, 0.75, 1},
+		{"cauchy lower", 1, 0.25, -1},
+		{"two dof", 2, 0.975, 4.302652729911275},
+		{"ten dof", 10, 0.025, -2.228138851986274},
+		{"many dof", 1e6, 0.975, 1.959966},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			st, err := uv.NewStudentT(0, 1, tc.dof)
+			if err != nil {
+				t.Fatal("unexpected error in NewStudentT,", err)
+			}
+			q, err := st.Quantile(tc.p)
+			if err != nil {
+				t.Error("unexpected error in Quantile,", err)
+			}
+			if math.Abs(q-tc.quant) > 1e-5 {
+				t.Errorf("expected quantile %v, but got %v", tc.quant, q)
+			}
+		})
+	}
+}
+
+func TestStudentTQuantileErrs(t *testing.T) {
+	st, err := uv.NewStudentT(0, 1, 1)
+	if err != nil {
+		t.Error("unexpected error in NewStudentT,", err)
+	}
+	_, err = st.Quantile(2)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package export encodes stream configurations, model summaries and retained
+// events as newline delimited JSON or CSV, for moving streams between
+// environments and for inspecting them in other tools.
+//
+// Exports contain the encoded model state, so imported streams resume where
+// the exported streams left off. Streams written by hand, without a model, are
+// fit to their events, if any.
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// Stream is the exported form of a stream.
+type Stream struct {
+	Name          string            `json:"name"`
+	Period        float64           `json:"period"`
+	Min           float64           `json:"min"`
+	Max           float64           `json:"max"`
+	Domain        string            `json:"domain"`
+	Retention     *Retention        `json:"retention,omitempty"`
+	Snapshots     *Snapshots        `json:"snapshots,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	TTL           Duration          `json:"ttl,omitempty"`
+	LastEventTime *time.Time        `json:"last_event_time,omitempty"`
+	Revision      uint64            `json:"revision"`
+	Summary       *Summary          `json:"summary,omitempty"`
+	Model         []byte            `json:"model,omitempty"`
+	Events        []*Event          `json:"events,omitempty"`
+}
+
+// Retention is an exported event retention policy.
+type Retention struct {
+	Count int      `json:"count"`
+	Age   Duration `json:"age"`
+}
+
+// Snapshots is an exported snapshot policy.
+type Snapshots struct {
+	Count    int      `json:"count"`
+	Interval Duration `json:"interval"`
+}
+
+// Summary describes the current state of a stream's model. It is exported
+// for inspection only, and ignored on import.
+type Summary struct {
+	Level float64 `json:"level"`
+	Trend float64 `json:"trend"`
+	Noise float64 `json:"noise"`
+	Walk  float64 `json:"walk"`
+}
+
+// Event is an exported retained event.
+type Event struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Duration is a time.Duration encoded as text, such as "24h0m0s".
+type Duration time.Duration
+
+// MarshalText encodes the duration as text.
+func (d Duration) MarshalText() (b []byte, err error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText decodes a duration from text.
+func (d *Duration) UnmarshalText(b []byte) (err error) {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// domains are the exported names of the stream domains.
+var domains = []string{
+	stream.Continuous:         "continuous",
+	stream.ContinuousRight:    "continuous_right",
+	stream.ContinuousInterval: "continuous_interval",
+	stream.DiscreteRight:      "discrete_right",
+	stream.DiscreteInterval:   "discrete_interval",
+}
+
+// parseDomain returns the domain with the exported name, or continuous if it
+// is empty.
+func parseDomain(name string) (d stream.Domain, err error) {
+	if name == "" {
+		return stream.Continuous, nil
+	}
+	for i := range domains {
+		if domains[i] == name {
+			return stream.Domain(i), nil
+		}
+	}
+	err = fmt.Errorf("domain must be one of %v, but was %q", domains, name)
+	return 0, err
+}
+
+// New returns the exported form of the stream, with the provided events.
+func New(st *stream.Stream, events []*stream.Event) (s *Stream) {
+	s = &Stream{
+		Name:     st.Config.Name,
+		Period:   st.Config.Period,
+		Min:      st.Config.Min,
+		Max:      st.Config.Max,
+		Labels:   st.Config.Labels,
+		TTL:      Duration(st.Config.TTL),
+		Revision: st.Revision,
+	}
+	if int(st.Config.Domain) < len(domains) {
+		s.Domain = domains[st.Config.Domain]
+	}
+	if r := st.Config.Retention; r != nil {
+		s.Retention = &Retention{Count: r.Count, Age: Duration(r.Age)}
+	}
+	if p := st.Config.Snapshots; p != nil {
+		s.Snapshots = &Snapshots{Count: p.Count, Interval: Duration(p.Interval)}
+	}
+	if !st.Time.IsZero() {
+		t := st.Time
+		s.LastEventTime = &t
+	}
+	if m := st.Model; m != nil && m.Deterministic != nil && m.RCE != nil {
+		s.Summary = &Summary{
+			Level: m.Deterministic.Location[0],
+			Trend: m.Deterministic.Location[1],
+			Noise: m.RCE.Noise(),
+			Walk:  m.RCE.Walk(),
+		}
+		// Unfit streams' models are just their period's prior.
+		if s.LastEventTime != nil {
+			s.Model, _ = schema.MarshalModel(m)
+		}
+	}
+	for _, e := range events {
+		s.Events = append(s.Events, &Event{Time: e.Time, Value: e.Value})
+	}
+	return s
+}
+
+// Stream returns a new stream with the exported configuration and model, and
+// the exported events for the stream's history. A stream without a model is
+// fit to its events instead, and one with a last event time but neither a
+// model nor events is rejected, as its fitted state would be lost.
+func (s *Stream) Stream() (st *stream.Stream, events []*stream.Event, err error) {
+	domain, err := parseDomain(s.Domain)
+	if err != nil {
+		return nil, nil, err
+	}
+	conf, err := stream.NewConfig(s.Name, s.Period, s.Min, s.Max, int(domain))
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.Retention != nil {
+		conf.Retention, err = stream.NewRetention(s.Retention.Count, time.Duration(s.Retention.Age))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if s.Snapshots != nil {
+		conf.Snapshots, err = stream.NewSnapshotPolicy(s.Snapshots.Count, time.Duration(s.Snapshots.Interval))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	err = label.Validate(s.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	conf.Labels = s.Labels
+	err = stream.ValidateTTL(time.Duration(s.TTL))
+	if err != nil {
+		return nil, nil, err
+	}
+	conf.TTL = time.Duration(s.TTL)
+
+	st = stream.NewWithConfig(conf)
+	vals := make([]float64, len(s.Events))
+	times := make([]time.Time, len(s.Events))
+	for i, e := range s.Events {
+		vals[i] = e.Value
+		times[i] = e.Time
+	}
+
+	if s.Model != nil {
+		err = s.restoreModel(st)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(s.Events) == 0 {
+			return st, nil, nil
+		}
+		events, err = stream.Events(vals, times)
+		return st, events, err
+	}
+
+	if len(s.Events) == 0 {
+		if s.LastEventTime != nil {
+			err = fmt.Errorf("stream %v has a last event time, but no model or events to restore its state from", s.Name)
+			return nil, nil, err
+		}
+		return st, nil, nil
+	}
+	err = st.Update(vals, times)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err = stream.Events(vals, times)
+	return st, events, err
+}
+
+// restoreModel sets the stream's model and time to the exported ones.
+func (s *Stream) restoreModel(st *stream.Stream) (err error) {
+	if s.LastEventTime == nil {
+		err = fmt.Errorf("stream %v has a model, but no last event time", s.Name)
+		return err
+	}
+	m, _, err := schema.UnmarshalModel(s.Model)
+	if err != nil {
+		err = fmt.Errorf("stream %v has invalid model: %v", s.Name, err)
+		return err
+	}
+	if m.Deterministic.Dim() != st.Model.Deterministic.Dim() {
+		err = fmt.Errorf("stream %v has a model for another period than %v", s.Name, s.Period)
+		return err
+	}
+	st.Model = m
+	st.Time = *s.LastEventTime
+	return nil
+}
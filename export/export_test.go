@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package export_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/export"
+	"github.com/cshenton/seer/stream"
+)
+
+func testStream(t *testing.T, n int) (s *stream.Stream, events []*stream.Event) {
+	s, err := stream.New("sales", 3600, 0, 0, int(stream.ContinuousRight))
+	if err != nil {
+		t.Fatal("unexpected error in stream.New:", err)
+	}
+	s.Config.Retention = &stream.Retention{Count: 100, Age: 24 * time.Hour}
+	s.Config.Snapshots = &stream.SnapshotPolicy{Count: 3, Interval: time.Hour}
+	s.Config.Labels = map[string]string{"team": "growth", "region": "eu"}
+	s.Config.TTL = 7 * 24 * time.Hour
+	vals := make([]float64, n)
+	times := make([]time.Time, n)
+	for i := range vals {
+		vals[i] = float64(i % 5)
+		times[i] = time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC)
+	}
+	if n > 0 {
+		s.Update(vals, times)
+	}
+	s.Revision = 4
+	events, _ = stream.Events(vals, times)
+	return s, events
+}
+
+func TestNew(t *testing.T) {
+	s, events := testStream(t, 10)
+	e := export.New(s, events)
+
+	if e.Name != "sales" || e.Period != 3600 || e.Domain != "continuous_right" || e.Revision != 4 {
+		t.Errorf("expected sales config, but got %v", e)
+	}
+	if e.Retention == nil || e.Retention.Count != 100 || time.Duration(e.Retention.Age) != 24*time.Hour {
+		t.Errorf("expected retention policy, but got %v", e.Retention)
+	}
+	if e.Snapshots == nil || e.Snapshots.Count != 3 {
+		t.Errorf("expected snapshot policy, but got %v", e.Snapshots)
+	}
+	if e.Labels["team"] != "growth" {
+		t.Errorf("expected labels, but got %v", e.Labels)
+	}
+	if time.Duration(e.TTL) != 7*24*time.Hour {
+		t.Errorf("expected ttl %v, but got %v", 7*24*time.Hour, e.TTL)
+	}
+	if e.LastEventTime == nil || !e.LastEventTime.Equal(s.Time) {
+		t.Errorf("expected last event time %v, but got %v", s.Time, e.LastEventTime)
+	}
+	if e.Summary == nil || e.Summary.Noise != s.Model.RCE.Noise() {
+		t.Errorf("expected model summary, but got %v", e.Summary)
+	}
+	if len(e.Model) == 0 {
+		t.Error("expected model, but it was empty")
+	}
+	if len(e.Events) != 10 {
+		t.Errorf("expected %v events, but got %v", 10, len(e.Events))
+	}
+}
+
+func TestStream(t *testing.T) {
+	tt := []struct {
+		name   string
+		events bool
+		model  bool
+	}{
+		{"with events", true, true},
+		{"without events", false, true},
+		{"without model", true, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, events := testStream(t, 10)
+			if !tc.events {
+				events = nil
+			}
+			e := export.New(s, events)
+			if !tc.model {
+				e.Model = nil
+			}
+
+			st, ev, err := e.Stream()
+			if err != nil {
+				t.Fatal("unexpected error in Stream:", err)
+			}
+			if st.Config.Domain != stream.ContinuousRight || st.Config.Retention.Count != 100 || st.Config.Snapshots.Interval != time.Hour {
+				t.Errorf("expected sales config, but got %v", st.Config)
+			}
+			if len(st.Config.Labels) != 2 || st.Config.Labels["region"] != "eu" {
+				t.Errorf("expected labels, but got %v", st.Config.Labels)
+			}
+			if st.Config.TTL != s.Config.TTL {
+				t.Errorf("expected ttl %v, but got %v", s.Config.TTL, st.Config.TTL)
+			}
+			if !st.Time.Equal(s.Time) {
+				t.Errorf("expected time %v, but got %v", s.Time, st.Time)
+			}
+			if st.Revision != 0 {
+				t.Errorf("expected revision %v, but it was %v", 0, st.Revision)
+			}
+			if len(ev) != len(events) {
+				t.Errorf("expected %v events, but got %v", len(events), len(ev))
+			}
+
+			// Streams resume from their exported model, or are refit from
+			// their events without one.
+			if st.Model.RCE.Noise() != s.Model.RCE.Noise() || st.Model.Deterministic.Location[0] != s.Model.Deterministic.Location[0] {
+				t.Errorf("expected model %v, but got %v", s.Model, st.Model)
+			}
+		})
+	}
+}
+
+func TestStreamErrs(t *testing.T) {
+	s, _ := testStream(t, 10)
+	fit := export.New(s, nil)
+	daily, _ := stream.New("visits", 86400, 0, 0, 0)
+	daily.Update([]float64{1}, []time.Time{s.Time})
+	other := export.New(daily, nil)
+	last := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name string
+		s    *export.Stream
+	}{
+		{"bad domain", &export.Stream{Name: "sales", Period: 3600, Domain: "sideways"}},
+		{"bad config", &export.Stream{Name: "sales", Period: -1}},
+		{"bad retention", &export.Stream{Name: "sales", Period: 3600, Retention: &export.Retention{Count: -1}}},
+		{"bad snapshots", &export.Stream{Name: "sales", Period: 3600, Snapshots: &export.Snapshots{}}},
+		{"bad labels", &export.Stream{Name: "sales", Period: 3600, Labels: map[string]string{"team": "a b"}}},
+		{"bad ttl", &export.Stream{Name: "sales", Period: 3600, TTL: export.Duration(-time.Hour)}},
+		{"unordered events", &export.Stream{Name: "sales", Period: 3600, Events: []*export.Event{
+			{Time: time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Time: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}}},
+		{"fit without model", &export.Stream{Name: "sales", Period: 3600, LastEventTime: &last}},
+		{"model without time", &export.Stream{Name: "sales", Period: 3600, Model: fit.Model}},
+		{"bad model", &export.Stream{Name: "sales", Period: 3600, LastEventTime: &last, Model: []byte{1, 2, 3}}},
+		{"model of other period", &export.Stream{Name: "sales", Period: 3600, LastEventTime: &last, Model: other.Model}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := tc.s.Stream()
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package export
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cshenton/seer/label"
+)
+
+// The supported export formats.
+const (
+	JSON = "json"
+	CSV  = "csv"
+)
+
+// columns are the CSV columns. Streams with events have a row per event, and
+// other streams a single row without the event columns. The base64 encoded
+// model is only written on a stream's first row.
+var columns = []string{
+	"name", "period", "min", "max", "domain",
+	"retention_count", "retention_age", "snapshot_count", "snapshot_interval", "labels", "ttl",
+	"last_event_time", "revision", "level", "trend", "noise", "walk", "model",
+	"event_time", "event_value",
+}
+
+// checkFormat returns an error if the format is not supported.
+func checkFormat(format string) (err error) {
+	if format != JSON && format != CSV {
+		err = fmt.Errorf("format must be one of %v or %v, but was %q", JSON, CSV, format)
+		return err
+	}
+	return nil
+}
+
+// Writer writes exported streams as newline delimited JSON, or as CSV.
+type Writer struct {
+	json   *json.Encoder
+	csv    *csv.Writer
+	header bool
+}
+
+// NewWriter returns a Writer that writes exported streams to w in the format.
+func NewWriter(w io.Writer, format string) (wr *Writer, err error) {
+	err = checkFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	wr = &Writer{}
+	if format == JSON {
+		wr.json = json.NewEncoder(w)
+	} else {
+		wr.csv = csv.NewWriter(w)
+	}
+	return wr, nil
+}
+
+// Write writes the stream.
+func (w *Writer) Write(s *Stream) (err error) {
+	if w.json != nil {
+		return w.json.Encode(s)
+	}
+	err = w.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	row := streamRow(s)
+	if len(s.Events) == 0 {
+		return w.csv.Write(append(row, "", ""))
+	}
+	for i, e := range s.Events {
+		if i == 1 {
+			row[17] = ""
+		}
+		err = w.csv.Write(append(row, e.Time.Format(time.RFC3339Nano), formatFloat(e.Value)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data, and the CSV header if no streams were
+// written.
+func (w *Writer) Flush() (err error) {
+	if w.csv == nil {
+		return nil
+	}
+	err = w.writeHeader()
+	if err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// writeHeader writes the CSV header if it has not been written.
+func (w *Writer) writeHeader() (err error) {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.csv.Write(columns)
+}
+
+// streamRow returns the CSV fields of the stream, without the event columns.
+func streamRow(s *Stream) (row []string) {
+	row = []string{
+		s.Name, formatFloat(s.Period), formatFloat(s.Min), formatFloat(s.Max), s.Domain,
+		"", "", "", "", label.Format(s.Labels), "", "", strconv.FormatUint(s.Revision, 10), "", "", "", "", "",
+	}
+	if s.Retention != nil {
+		row[5] = strconv.Itoa(s.Retention.Count)
+		row[6] = time.Duration(s.Retention.Age).String()
+	}
+	if s.Snapshots != nil {
+		row[7] = strconv.Itoa(s.Snapshots.Count)
+		row[8] = time.Duration(s.Snapshots.Interval).String()
+	}
+	if s.TTL != 0 {
+		row[10] = time.Duration(s.TTL).String()
+	}
+	if s.LastEventTime != nil {
+		row[11] = s.LastEventTime.Format(time.RFC3339Nano)
+	}
+	if s.Summary != nil {
+		row[13] = formatFloat(s.Summary.Level)
+		row[14] = formatFloat(s.Summary.Trend)
+		row[15] = formatFloat(s.Summary.Noise)
+		row[16] = formatFloat(s.Summary.Walk)
+	}
+	if s.Model != nil {
+		row[17] = base64.StdEncoding.EncodeToString(s.Model)
+	}
+	return row
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Reader reads exported streams from newline delimited JSON, or from CSV.
+// CSV columns are matched by the names in the header row, so may be reordered,
+// and all but name may be omitted.
+type Reader struct {
+	json *json.Decoder
+	csv  *csv.Reader
+	cols map[string]int
+	next []string
+}
+
+// NewReader returns a Reader that reads exported streams from r in the format.
+func NewReader(r io.Reader, format string) (rd *Reader, err error) {
+	err = checkFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	rd = &Reader{}
+	if format == JSON {
+		rd.json = json.NewDecoder(r)
+	} else {
+		rd.csv = csv.NewReader(r)
+	}
+	return rd, nil
+}
+
+// Read returns the next stream, or io.EOF when there are no more streams.
+func (r *Reader) Read() (s *Stream, err error) {
+	if r.json != nil {
+		s = &Stream{}
+		err = r.json.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	if r.cols == nil {
+		err = r.readHeader()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for {
+		row := r.next
+		r.next = nil
+		if row == nil {
+			row, err = r.csv.Read()
+		}
+		if err == io.EOF && s != nil {
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		f := &fields{row: row, cols: r.cols}
+		if s == nil {
+			s = f.stream()
+		} else if f.get("name") != s.Name {
+			r.next = row
+			return s, nil
+		}
+		if f.get("event_time") != "" {
+			s.Events = append(s.Events, &Event{Time: f.time("event_time"), Value: f.float("event_value")})
+		}
+		if f.err != nil {
+			return nil, f.err
+		}
+	}
+}
+
+// readHeader reads the CSV header, and the columns it names.
+func (r *Reader) readHeader() (err error) {
+	header, err := r.csv.Read()
+	if err == io.EOF {
+		err = fmt.Errorf("missing csv header")
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	r.cols = make(map[string]int)
+	for i, c := range header {
+		r.cols[c] = i
+	}
+	if _, ok := r.cols["name"]; !ok {
+		err = fmt.Errorf("csv header must have a name column")
+		return err
+	}
+	return nil
+}
+
+// fields parses the fields of a CSV row, keeping the first error.
+type fields struct {
+	row  []string
+	cols map[string]int
+	err  error
+}
+
+func (f *fields) get(col string) string {
+	i, ok := f.cols[col]
+	if !ok {
+		return ""
+	}
+	return f.row[i]
+}
+
+func (f *fields) fail(col string, err error) {
+	if f.err == nil {
+		f.err = fmt.Errorf("stream %v has invalid %v: %v", f.get("name"), col, err)
+	}
+}
+
+func (f *fields) float(col string) (v float64) {
+	if f.get(col) == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(f.get(col), 64)
+	if err != nil {
+		f.fail(col, err)
+	}
+	return v
+}
+
+func (f *fields) int(col string) (v int) {
+	if f.get(col) == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(f.get(col))
+	if err != nil {
+		f.fail(col, err)
+	}
+	return v
+}
+
+func (f *fields) duration(col string) (d Duration) {
+	if f.get(col) == "" {
+		return 0
+	}
+	err := d.UnmarshalText([]byte(f.get(col)))
+	if err != nil {
+		f.fail(col, err)
+	}
+	return d
+}
+
+func (f *fields) time(col string) (t time.Time) {
+	t, err := time.Parse(time.RFC3339Nano, f.get(col))
+	if err != nil {
+		f.fail(col, err)
+	}
+	return t
+}
+
+// stream returns the stream described by the row, ignoring its summary.
+func (f *fields) stream() (s *Stream) {
+	s = &Stream{
+		Name:   f.get("name"),
+		Period: f.float("period"),
+		Min:    f.float("min"),
+		Max:    f.float("max"),
+		Domain: f.get("domain"),
+	}
+	if f.get("retention_count") != "" || f.get("retention_age") != "" {
+		s.Retention = &Retention{Count: f.int("retention_count"), Age: f.duration("retention_age")}
+	}
+	if f.get("snapshot_count") != "" || f.get("snapshot_interval") != "" {
+		s.Snapshots = &Snapshots{Count: f.int("snapshot_count"), Interval: f.duration("snapshot_interval")}
+	}
+	if f.get("labels") != "" {
+		labels, err := label.ParseSet(f.get("labels"))
+		if err != nil {
+			f.fail("labels", err)
+		}
+		s.Labels = labels
+	}
+	s.TTL = f.duration("ttl")
+	if f.get("last_event_time") != "" {
+		t := f.time("last_event_time")
+		s.LastEventTime = &t
+	}
+	if f.get("revision") != "" {
+		v, err := strconv.ParseUint(f.get("revision"), 10, 64)
+		if err != nil {
+			f.fail("revision", err)
+		}
+		s.Revision = v
+	}
+	if f.get("model") != "" {
+		m, err := base64.StdEncoding.DecodeString(f.get("model"))
+		if err != nil {
+			f.fail("model", err)
+		}
+		s.Model = m
+	}
+	return s
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package export_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/export"
+	"github.com/cshenton/seer/stream"
+)
+
+func TestWriter(t *testing.T) {
+	tt := []struct {
+		name   string
+		format string
+		events int
+	}{
+		{"json", export.JSON, 0},
+		{"json with events", export.JSON, 5},
+		{"csv", export.CSV, 0},
+		{"csv with events", export.CSV, 5},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, events := testStream(t, tc.events)
+			other, _ := stream.New("visits", 86400, 0, 0, 0)
+
+			buf := &bytes.Buffer{}
+			w, err := export.NewWriter(buf, tc.format)
+			if err != nil {
+				t.Fatal("unexpected error in NewWriter:", err)
+			}
+			w.Write(export.New(s, events))
+			w.Write(export.New(other, nil))
+			err = w.Flush()
+			if err != nil {
+				t.Fatal("unexpected error in Flush:", err)
+			}
+
+			r, err := export.NewReader(buf, tc.format)
+			if err != nil {
+				t.Fatal("unexpected error in NewReader:", err)
+			}
+			first, err := r.Read()
+			if err != nil {
+				t.Fatal("unexpected error in Read:", err)
+			}
+			if first.Name != "sales" || first.Domain != "continuous_right" || first.Revision != 4 {
+				t.Errorf("expected sales config, but got %v", first)
+			}
+			if first.Snapshots == nil || time.Duration(first.Snapshots.Interval) != time.Hour {
+				t.Errorf("expected snapshot policy, but got %v", first.Snapshots)
+			}
+			if len(first.Labels) != 2 || first.Labels["team"] != "growth" {
+				t.Errorf("expected labels, but got %v", first.Labels)
+			}
+			if time.Duration(first.TTL) != 7*24*time.Hour {
+				t.Errorf("expected ttl %v, but got %v", 7*24*time.Hour, first.TTL)
+			}
+			if len(first.Events) != tc.events {
+				t.Errorf("expected %v events, but got %v", tc.events, len(first.Events))
+			}
+			if (first.LastEventTime != nil) != (tc.events > 0) {
+				t.Errorf("expected last event time for %v events, but got %v", tc.events, first.LastEventTime)
+			}
+			if !bytes.Equal(first.Model, export.New(s, nil).Model) {
+				t.Errorf("expected model %v, but got %v", export.New(s, nil).Model, first.Model)
+			}
+
+			second, err := r.Read()
+			if err != nil {
+				t.Fatal("unexpected error in Read:", err)
+			}
+			if second.Name != "visits" || second.Retention != nil || len(second.Events) != 0 {
+				t.Errorf("expected visits config, but got %v", second)
+			}
+			_, err = r.Read()
+			if err != io.EOF {
+				t.Errorf("expected EOF, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestReaderColumns(t *testing.T) {
+	data := "period,name,event_value,event_time\n" +
+		"3600,sales,1,2016-01-01T00:00:00Z\n" +
+		"3600,sales,2,2016-01-01T01:00:00Z\n" +
+		"60,visits,,\n"
+	r, _ := export.NewReader(strings.NewReader(data), export.CSV)
+
+	s, err := r.Read()
+	if err != nil {
+		t.Fatal("unexpected error in Read:", err)
+	}
+	if s.Name != "sales" || s.Period != 3600 || len(s.Events) != 2 || s.Events[1].Value != 2 {
+		t.Errorf("expected sales with %v events, but got %v", 2, s)
+	}
+	s, err = r.Read()
+	if err != nil {
+		t.Fatal("unexpected error in Read:", err)
+	}
+	if s.Name != "visits" || s.Period != 60 {
+		t.Errorf("expected visits, but got %v", s)
+	}
+}
+
+func TestFormatErrs(t *testing.T) {
+	_, err := export.NewWriter(&bytes.Buffer{}, "xml")
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+	_, err = export.NewReader(&bytes.Buffer{}, "xml")
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func TestReaderErrs(t *testing.T) {
+	tt := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{"bad json", export.JSON, "{\"name\": 5}\n"},
+		{"missing header", export.CSV, ""},
+		{"missing name", export.CSV, "period\n3600\n"},
+		{"bad number", export.CSV, "name,period\nsales,hourly\n"},
+		{"bad duration", export.CSV, "name,retention_age\nsales,forever\n"},
+		{"bad event time", export.CSV, "name,event_time\nsales,yesterday\n"},
+		{"bad labels", export.CSV, "name,labels\nsales,team\n"},
+		{"bad ttl", export.CSV, "name,ttl\nsales,a week\n"},
+		{"bad model", export.CSV, "name,model\nsales,!!\n"},
+		{"ragged rows", export.CSV, "name,period\nsales\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := export.NewReader(strings.NewReader(tc.data), tc.format)
+			_, err := r.Read()
+			if err == nil || err == io.EOF {
+				t.Errorf("expected error, but got %v", err)
+			}
+		})
+	}
+}
@@ -46,6 +46,58 @@ func Predict(p *State, m *System) (n *State, err error) {
 	return n, err
 }
 
+// PredictN predicts the state distribution k steps ahead, as k calls to
+// Predict would, but with O(log k) matrix products, by repeatedly squaring
+// the transition and its accumulated process covariance.
+func PredictN(p *State, m *System, k int) (n *State, err error) {
+	pDim := p.Dim()
+	mDim, _ := m.Dims()
+
+	if pDim != mDim {
+		err = fmt.Errorf("Prev state dim must match process dim, but were %v, and %v", pDim, mDim)
+		return n, err
+	}
+	if k < 0 {
+		err = fmt.Errorf("Steps must be non-negative, but was %v", k)
+		return n, err
+	}
+
+	// a and s are the transition and added covariance of 2^i steps, and at
+	// and st those of the steps taken so far.
+	a := mat.DenseCopyOf(m.A)
+	s := mat.NewDense(mDim, mDim, nil)
+	s.Product(m.B, m.Q, m.B.T())
+	at := mat.NewDense(mDim, mDim, nil)
+	for i := 0; i < mDim; i++ {
+		at.Set(i, i, 1)
+	}
+	st := mat.NewDense(mDim, mDim, nil)
+
+	var tmp mat.Dense
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			tmp.Product(a, st, a.T())
+			st.Add(&tmp, s)
+			tmp.Mul(a, at)
+			at.Copy(&tmp)
+		}
+		if k > 1 {
+			tmp.Product(a, s, a.T())
+			s.Add(&tmp, s)
+			tmp.Mul(a, a)
+			a.Copy(&tmp)
+		}
+	}
+
+	var loc mat.Dense
+	var cov mat.Dense
+	loc.Mul(at, p.Loc)
+	cov.Product(at, p.Cov, at.T())
+	cov.Add(&cov, st)
+	n, err = NewState(&loc, &cov)
+	return n, err
+}
+
 // Observe determines the observation distribution for the current time step
 // using the linear measurement equation: `y_next = C * x_next + v_next`. It
 // returns an error if the state and system process dims do not match.
@@ -121,3 +173,65 @@ func Update(p *State, m *System, v float64) (n *State, res float64, err error) {
 	n, _ = NewState(&loc, &cov)
 	return n, res, err
 }
+
+// Accumulate augments a system with a scalar measurement, and a state of that
+// system, with a final state component that accumulates the noiseless
+// measurement: `s_next = s_prev + C * x_next`. The augmented measurement
+// matrix observes only the accumulator, so predicting k steps and observing
+// gives the distribution of the sum of k noiseless measurements. The
+// accumulator starts at zero, with no variance.
+func Accumulate(s *State, m *System) (as *State, am *System, err error) {
+	pDim, mDim := m.Dims()
+	if mDim != 1 {
+		err = fmt.Errorf("Measurement must be scalar to accumulate, but had dim %v", mDim)
+		return nil, nil, err
+	}
+	if s.Dim() != pDim {
+		err = fmt.Errorf("State dim must match process dim, but were %v, and %v", s.Dim(), pDim)
+		return nil, nil, err
+	}
+	_, bCol := m.B.Dims()
+	d := pDim + 1
+
+	var ca, cb mat.Dense
+	ca.Mul(m.C, m.A)
+	cb.Mul(m.C, m.B)
+
+	a := mat.NewDense(d, d, nil)
+	a.Slice(0, pDim, 0, pDim).(*mat.Dense).Copy(m.A)
+	a.Slice(pDim, d, 0, pDim).(*mat.Dense).Copy(&ca)
+	a.Set(pDim, pDim, 1)
+
+	b := mat.NewDense(d, bCol, nil)
+	b.Slice(0, pDim, 0, bCol).(*mat.Dense).Copy(m.B)
+	b.Slice(pDim, d, 0, bCol).(*mat.Dense).Copy(&cb)
+
+	c := mat.NewDense(1, d, nil)
+	c.Set(0, pDim, 1)
+
+	am, err = NewSystem(a, b, c, m.Q, m.R)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc := mat.NewDense(d, 1, nil)
+	loc.Slice(0, pDim, 0, 1).(*mat.Dense).Copy(s.Loc)
+	cov := mat.NewDense(d, d, nil)
+	cov.Slice(0, pDim, 0, pDim).(*mat.Dense).Copy(s.Cov)
+
+	as, err = NewState(loc, cov)
+	if err != nil {
+		return nil, nil, err
+	}
+	return as, am, nil
+}
+
+// Reset zeroes the accumulator of a state augmented by Accumulate.
+func Reset(s *State) {
+	d := s.Dim()
+	s.Loc.Set(d-1, 0, 0)
+	for i := 0; i < d; i++ {
+		s.Cov.Set(i, d-1, 0)
+		s.Cov.Set(d-1, i, 0)
+	}
+}
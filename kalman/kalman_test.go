@@ -83,6 +83,75 @@ func TestPredict(t *testing.T) {
 	}
 }
 
+func TestPredictN(t *testing.T) {
+	a := mat.NewDense(3, 3, []float64{0.8, -0.6, 0, 0.6, 0.8, 0, 0, 0, 1})
+	b := mat.NewDense(3, 3, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1})
+	c := mat.NewDense(1, 3, []float64{1, 0, 1})
+	q := mat.NewDense(3, 3, []float64{0.5, 0.1, 0, 0.1, 1, 0, 0, 0, 0.2})
+	r := mat.NewDense(1, 1, []float64{0.5})
+	m, err := kalman.NewSystem(a, b, c, q, r)
+	if err != nil {
+		t.Fatal("failed to create kalman.System", err)
+	}
+	prev, err := kalman.NewState(mat.NewDense(3, 1, []float64{1, 2, 3}), mat.NewDense(3, 3, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}))
+	if err != nil {
+		t.Fatal("failed to create kalman.State", err)
+	}
+
+	tt := []struct {
+		name string
+		k    int
+	}{
+		{"zero steps", 0},
+		{"one step", 1},
+		{"power of two", 8},
+		{"odd steps", 13},
+		{"many steps", 100},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			want := prev
+			for i := 0; i < tc.k; i++ {
+				want, _ = kalman.Predict(want, m)
+			}
+			got, err := kalman.PredictN(prev, m, tc.k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !mat.EqualApprox(got.Loc, want.Loc, 1e-9) {
+				t.Errorf("expected location %v, got %v", mat.Formatted(want.Loc), mat.Formatted(got.Loc))
+			}
+			if !mat.EqualApprox(got.Cov, want.Cov, 1e-9) {
+				t.Errorf("expected covariance %v, got %v", mat.Formatted(want.Cov), mat.Formatted(got.Cov))
+			}
+		})
+	}
+}
+
+func TestPredictNErrs(t *testing.T) {
+	a := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	m, _ := kalman.NewSystem(a, a, mat.NewDense(1, 2, []float64{1, 0}), a, mat.NewDense(1, 1, []float64{1}))
+	s, _ := kalman.NewState(mat.NewDense(2, 1, []float64{1, 1}), a)
+	small, _ := kalman.NewState(mat.NewDense(1, 1, []float64{1}), mat.NewDense(1, 1, []float64{1}))
+
+	tt := []struct {
+		name string
+		s    *kalman.State
+		k    int
+	}{
+		{"negative steps", s, -1},
+		{"mismatched state", small, 1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := kalman.PredictN(tc.s, m, tc.k)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
 func TestObserve(t *testing.T) {
 	tt := []struct {
 		name   string
@@ -195,3 +264,79 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAccumulate(t *testing.T) {
+	a := mat.NewDense(2, 2, []float64{1, 1, 0, 1})
+	b := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	c := mat.NewDense(1, 2, []float64{1, 0})
+	q := mat.NewDense(2, 2, []float64{0.5, 0.1, 0.1, 1.0})
+	r := mat.NewDense(1, 1, []float64{0.5})
+	m, _ := kalman.NewSystem(a, b, c, q, r)
+	s, _ := kalman.NewState(mat.NewDense(2, 1, []float64{1, 1}), mat.NewDense(2, 2, []float64{1, 0, 0, 1}))
+
+	as, am, err := kalman.Accumulate(s, m)
+	if err != nil {
+		t.Fatal("unexpected error in Accumulate:", err)
+	}
+	if as.Dim() != 3 {
+		t.Fatalf("expected augmented dim %v, but got %v", 3, as.Dim())
+	}
+
+	// After one step the accumulator is the first noiseless measurement.
+	next, _ := kalman.Predict(as, am)
+	acc, _ := kalman.StateObserve(next, am)
+	st, _ := kalman.Predict(s, m)
+	ob, _ := kalman.StateObserve(st, m)
+	if !mat.EqualApprox(acc.Loc, ob.Loc, 1e-12) || !mat.EqualApprox(acc.Cov, ob.Cov, 1e-12) {
+		t.Errorf("expected accumulator %v, %v, but got %v, %v", ob.Loc, ob.Cov, acc.Loc, acc.Cov)
+	}
+
+	// After two steps it is the sum of the first two, including their covariance.
+	next, _ = kalman.Predict(next, am)
+	acc, _ = kalman.StateObserve(next, am)
+	st2, _ := kalman.Predict(st, m)
+	ob2, _ := kalman.StateObserve(st2, m)
+	var cross mat.Dense
+	cross.Product(m.C, m.A, st.Cov, m.C.T())
+	loc := ob.Loc.At(0, 0) + ob2.Loc.At(0, 0)
+	cov := ob.Cov.At(0, 0) + ob2.Cov.At(0, 0) + 2*cross.At(0, 0)
+	if acc.Loc.At(0, 0) != loc || acc.Cov.At(0, 0) != cov {
+		t.Errorf("expected accumulator %v, %v, but got %v, %v", loc, cov, acc.Loc.At(0, 0), acc.Cov.At(0, 0))
+	}
+
+	kalman.Reset(next)
+	acc, _ = kalman.StateObserve(next, am)
+	if acc.Loc.At(0, 0) != 0 || acc.Cov.At(0, 0) != 0 {
+		t.Errorf("expected reset accumulator, but got %v, %v", acc.Loc.At(0, 0), acc.Cov.At(0, 0))
+	}
+}
+
+func TestAccumulateErrs(t *testing.T) {
+	a := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	q := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	c := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	s, _ := kalman.NewState(mat.NewDense(2, 1, []float64{1, 1}), mat.NewDense(2, 2, []float64{1, 0, 0, 1}))
+	small, _ := kalman.NewState(mat.NewDense(1, 1, []float64{1}), mat.NewDense(1, 1, []float64{1}))
+	vector, _ := kalman.NewSystem(a, a, c, q, q)
+	scalar, _ := kalman.NewSystem(a, a, mat.NewDense(1, 2, []float64{1, 0}), q, mat.NewDense(1, 1, []float64{1}))
+
+	tt := []struct {
+		name string
+		s    *kalman.State
+		m    *kalman.System
+	}{
+		{"vector measurement", s, vector},
+		{"mismatched state", small, scalar},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			as, am, err := kalman.Accumulate(tc.s, tc.m)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if as != nil || am != nil {
+				t.Error("expected nil state and system")
+			}
+		})
+	}
+}
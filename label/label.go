@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package label validates stream labels, and parses and matches label
+// selectors, following the syntax of Kubernetes labels and selectors.
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxLength is the maximum length of a label value, or of the name part of
+// a label key.
+const maxLength = 63
+
+// maxPrefixLength is the maximum length of a label key's prefix.
+const maxPrefixLength = 253
+
+var (
+	namePattern   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	prefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// ValidateKey returns an error if the key is not a valid label key, which is
+// a name of at most 63 alphanumerics, '-', '_' or '.', beginning and ending
+// with an alphanumeric, optionally prefixed by a DNS subdomain and '/'.
+func ValidateKey(key string) (err error) {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) > maxPrefixLength || !prefixPattern.MatchString(prefix) {
+			err = fmt.Errorf("label key %q has an invalid prefix", key)
+			return err
+		}
+	}
+	if len(name) > maxLength || !namePattern.MatchString(name) {
+		err = fmt.Errorf("label key %q is invalid, it must be at most %v alphanumerics, '-', '_' or '.', beginning and ending with an alphanumeric", key, maxLength)
+		return err
+	}
+	return nil
+}
+
+// ValidateValue returns an error if the value is not a valid label value,
+// which is empty, or a valid label key name.
+func ValidateValue(value string) (err error) {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxLength || !namePattern.MatchString(value) {
+		err = fmt.Errorf("label value %q is invalid, it must be empty, or at most %v alphanumerics, '-', '_' or '.', beginning and ending with an alphanumeric", value, maxLength)
+		return err
+	}
+	return nil
+}
+
+// Validate returns an error if any of the labels has an invalid key or value.
+func Validate(labels map[string]string) (err error) {
+	for k, v := range labels {
+		err = ValidateKey(k)
+		if err == nil {
+			err = ValidateValue(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Format returns the labels as comma separated key=value pairs, in key order.
+func Format(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + labels[k]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseSet parses labels formatted as comma separated key=value pairs, and
+// returns nil labels for an empty string.
+func ParseSet(s string) (labels map[string]string, err error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	labels = make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			err = fmt.Errorf("label %q must be a key=value pair", pair)
+			return nil, err
+		}
+		k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if _, ok := labels[k]; ok {
+			err = fmt.Errorf("label key %q is repeated", k)
+			return nil, err
+		}
+		labels[k] = v
+	}
+	err = Validate(labels)
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package label_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cshenton/seer/label"
+)
+
+func TestValidateKey(t *testing.T) {
+	tt := []struct {
+		name string
+		key  string
+		ok   bool
+	}{
+		{"simple", "team", true},
+		{"punctuated", "cost-centre_v2.1", true},
+		{"prefixed", "example.com/team", true},
+		{"empty", "", false},
+		{"leading dash", "-team", false},
+		{"trailing dot", "team.", false},
+		{"space", "cost centre", false},
+		{"long", strings.Repeat("a", 64), false},
+		{"empty prefix", "/team", false},
+		{"bad prefix", "Example.com/team", false},
+		{"empty name", "example.com/", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := label.ValidateKey(tc.key)
+			if (err == nil) != tc.ok {
+				t.Errorf("expected valid %v, but got error %v", tc.ok, err)
+			}
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	tt := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"simple", "growth", true},
+		{"empty", "", true},
+		{"slash", "a/b", false},
+		{"long", strings.Repeat("a", 64), false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := label.ValidateValue(tc.value)
+			if (err == nil) != tc.ok {
+				t.Errorf("expected valid %v, but got error %v", tc.ok, err)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	s := label.Format(map[string]string{"team": "growth", "region": "eu", "tier": ""})
+	if s != "region=eu,team=growth,tier=" {
+		t.Errorf("expected labels %v, but got %v", "region=eu,team=growth,tier=", s)
+	}
+
+	labels, err := label.ParseSet(s)
+	if err != nil {
+		t.Fatal("unexpected error in ParseSet:", err)
+	}
+	if len(labels) != 3 || labels["team"] != "growth" || labels["region"] != "eu" || labels["tier"] != "" {
+		t.Errorf("expected labels to round trip, but got %v", labels)
+	}
+}
+
+func TestParseSet(t *testing.T) {
+	tt := []struct {
+		name string
+		set  string
+		want int
+		ok   bool
+	}{
+		{"empty", "", 0, true},
+		{"single", "team=growth", 1, true},
+		{"spaced", "team = growth, region = eu", 2, true},
+		{"no value", "team", 0, false},
+		{"repeated", "team=growth,team=ops", 0, false},
+		{"invalid", "team=a b", 0, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			labels, err := label.ParseSet(tc.set)
+			if (err == nil) != tc.ok {
+				t.Fatalf("expected valid %v, but got error %v", tc.ok, err)
+			}
+			if len(labels) != tc.want {
+				t.Errorf("expected %v labels, but got %v", tc.want, labels)
+			}
+		})
+	}
+}
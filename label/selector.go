@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operator is the comparison made by a requirement.
+type Operator string
+
+// The supported operators.
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a condition on the value of a single label.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches reports whether the labels meet the requirement. Labels without the
+// key meet != and notin requirements.
+func (r *Requirement) Matches(labels map[string]string) bool {
+	v, ok := labels[r.Key]
+	switch r.Operator {
+	case Equals, In:
+		return ok && contains(r.Values, v)
+	case NotEquals, NotIn:
+		return !ok || !contains(r.Values, v)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// String returns the requirement in selector syntax.
+func (r *Requirement) String() string {
+	switch r.Operator {
+	case Equals, NotEquals:
+		return r.Key + string(r.Operator) + r.Values[0]
+	case In, NotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	case Exists:
+		return r.Key
+	}
+	return "!" + r.Key
+}
+
+func contains(values []string, v string) bool {
+	for i := range values {
+		if values[i] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Selector is a set of requirements, all of which labels must meet to match.
+// The empty selector matches all labels.
+type Selector []*Requirement
+
+// Matches reports whether the labels meet every requirement.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in selector syntax, with requirements in key
+// order, so equivalent selectors have the same string.
+func (s Selector) String() string {
+	reqs := make([]string, len(s))
+	for i, r := range s {
+		reqs[i] = r.String()
+	}
+	sort.Strings(reqs)
+	return strings.Join(reqs, ",")
+}
+
+// setPattern matches set based requirements, such as "region in (us, eu)".
+var setPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\(([^()]*)\)$`)
+
+// Parse parses a selector of comma separated requirements, each of which is
+// one of:
+//
+//	key=value, key==value   the label is set to value
+//	key!=value              the label is not set to value, or is unset
+//	key in (v1, v2)         the label is set to one of the values
+//	key notin (v1, v2)      the label is not set to any of the values
+//	key                     the label is set
+//	!key                    the label is unset
+func Parse(selector string) (s Selector, err error) {
+	for _, part := range split(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			err = fmt.Errorf("selector %q has an empty requirement", selector)
+			return nil, err
+		}
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, r)
+	}
+	return s, nil
+}
+
+// split splits the selector at commas outside of parentheses.
+func split(selector string) (parts []string) {
+	if strings.TrimSpace(selector) == "" {
+		return nil
+	}
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+// parseRequirement parses a single requirement.
+func parseRequirement(part string) (r *Requirement, err error) {
+	r = &Requirement{}
+	if m := setPattern.FindStringSubmatch(part); m != nil {
+		r.Key, r.Operator = m[1], Operator(m[2])
+		for _, v := range strings.Split(m[3], ",") {
+			r.Values = append(r.Values, strings.TrimSpace(v))
+		}
+	} else if strings.HasPrefix(part, "!") && !strings.Contains(part, "=") {
+		r.Key, r.Operator = strings.TrimSpace(part[1:]), DoesNotExist
+	} else if i := strings.Index(part, "!="); i >= 0 {
+		r.Key, r.Operator, r.Values = part[:i], NotEquals, []string{part[i+2:]}
+	} else if i := strings.Index(part, "=="); i >= 0 {
+		r.Key, r.Operator, r.Values = part[:i], Equals, []string{part[i+2:]}
+	} else if i := strings.Index(part, "="); i >= 0 {
+		r.Key, r.Operator, r.Values = part[:i], Equals, []string{part[i+1:]}
+	} else {
+		r.Key, r.Operator = part, Exists
+	}
+
+	r.Key = strings.TrimSpace(r.Key)
+	err = ValidateKey(r.Key)
+	if err != nil {
+		err = fmt.Errorf("invalid requirement %q: %v", part, err)
+		return nil, err
+	}
+	for i := range r.Values {
+		r.Values[i] = strings.TrimSpace(r.Values[i])
+		err = ValidateValue(r.Values[i])
+		if err != nil {
+			err = fmt.Errorf("invalid requirement %q: %v", part, err)
+			return nil, err
+		}
+	}
+	return r, nil
+}
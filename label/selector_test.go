@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package label_test
+
+import (
+	"testing"
+
+	"github.com/cshenton/seer/label"
+)
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		name     string
+		selector string
+		want     string
+	}{
+		{"empty", "", ""},
+		{"equals", "team=growth", "team=growth"},
+		{"double equals", "team==growth", "team=growth"},
+		{"not equals", "team != growth", "team!=growth"},
+		{"in", "region in (us, eu)", "region in (us,eu)"},
+		{"notin", "region notin (us)", "region notin (us)"},
+		{"exists", "team", "team"},
+		{"does not exist", "!team", "!team"},
+		{"several", "team=growth, region in (us,eu), !deprecated", "!deprecated,region in (us,eu),team=growth"},
+		{"empty value", "tier=", "tier="},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := label.Parse(tc.selector)
+			if err != nil {
+				t.Fatal("unexpected error in Parse:", err)
+			}
+			if s.String() != tc.want {
+				t.Errorf("expected selector %v, but got %v", tc.want, s.String())
+			}
+		})
+	}
+}
+
+func TestParseErrs(t *testing.T) {
+	tt := []struct {
+		name     string
+		selector string
+	}{
+		{"empty requirement", "team=growth,"},
+		{"bad key", "te am=growth"},
+		{"bad value", "team=gro wth"},
+		{"unclosed set", "region in (us, eu"},
+		{"bad set value", "region in (us, e/u)"},
+		{"empty key", "=growth"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := label.Parse(tc.selector)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	labels := map[string]string{"team": "growth", "region": "eu"}
+
+	tt := []struct {
+		name     string
+		selector string
+		want     bool
+	}{
+		{"empty", "", true},
+		{"equals", "team=growth", true},
+		{"equals other", "team=ops", false},
+		{"not equals", "team!=ops", true},
+		{"not equals unset", "tier!=gold", true},
+		{"in", "region in (us, eu)", true},
+		{"in unset", "tier in (gold)", false},
+		{"notin", "region notin (eu)", false},
+		{"exists", "region", true},
+		{"does not exist", "!tier", true},
+		{"does not exist set", "!team", false},
+		{"all", "team=growth,region in (eu),!tier", true},
+		{"one fails", "team=growth,region=us", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := label.Parse(tc.selector)
+			if err != nil {
+				t.Fatal("unexpected error in Parse:", err)
+			}
+			if s.Matches(labels) != tc.want {
+				t.Errorf("expected match %v, but got %v", tc.want, !tc.want)
+			}
+		})
+	}
+}
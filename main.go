@@ -18,29 +18,238 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net"
+	"net/http"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cshenton/seer/seer"
 	"github.com/cshenton/seer/server"
 	"google.golang.org/grpc"
 )
 
-var port = ":8080"
-var path = filepath.FromSlash("/var/seer")
+var port = flag.String("port", ":8080", "address to serve on")
+var backend = flag.String("backend", server.Bolt, "stream store, one of bolt, memory or sqlite")
+var path = flag.String("path", "", "bolt or sqlite database, or memory snapshot, path, by default /var/seer, or /var/seer.snap for memory")
+var interval = flag.Duration("snapshot-interval", 0, "interval between memory store snapshots")
+var format = flag.String("format", "json", "export and import format, json or csv")
+var history = flag.Bool("history", false, "include retained events in exports")
+var sweep = flag.Duration("janitor-interval", 0, "interval between deleting expired streams, zero never deletes them")
+var archive = flag.String("archive", "", "directory to write expired streams to as backups before deleting them")
+var metrics = flag.String("metrics", "", "address to serve metrics on at /debug/vars, if set")
+var cacheSize = flag.Int("forecast-cache", 1024, "number of forecasts to cache, zero disables the cache")
+
+// defaultPaths are the store paths of each backend when -path is not set. The
+// memory store's snapshot is a file, so has its own path.
+var defaultPaths = map[string]string{
+	server.Bolt:   "/var/seer",
+	server.Memory: "/var/seer.snap",
+	server.SQLite: "/var/seer",
+}
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %v [flags] [rebuild <stream> | backup <file> | restore <file> | export <file> | import <file>]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Serves the seer API, or with rebuild, rebuilds a stream from its retained events")
+		fmt.Fprintln(os.Stderr, "in the store, with backup, writes every stream to a file, and with restore, restores")
+		fmt.Fprintln(os.Stderr, "the streams in a backup file. Export writes stream configs and summaries as json or")
+		fmt.Fprintln(os.Stderr, "csv, and import creates streams from such a file. The store must not be in use by a")
+		fmt.Fprintln(os.Stderr, "running server.")
+		fmt.Fprintln(os.Stderr)
+		flag.PrintDefaults()
+	}
+}
+
+// rebuild rebuilds the named stream from its retained events, logging
+// progress, then closes the store.
+func rebuild(srv *server.Server, name string) {
+	st, err := srv.Rebuild(name, nil, func(done, total int) error {
+		log.Printf("replayed %v of %v events", done, total)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("failed to rebuild stream: %v", err)
+	}
+	log.Printf("rebuilt stream %v at revision %v", st.Config.Name, st.Revision)
+	closeStore(srv)
+}
+
+// backup writes a backup of every stream to the named file, then closes the
+// store.
+func backup(srv *server.Server, name string) {
+	f, err := os.Create(name)
+	if err != nil {
+		log.Fatalf("failed to create backup: %v", err)
+	}
+	n, err := srv.BackupTo(context.Background(), f)
+	if err != nil {
+		log.Fatalf("failed to back up streams: %v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		log.Fatalf("failed to write backup: %v", err)
+	}
+	log.Printf("backed up %v streams to %v", n, name)
+	closeStore(srv)
+}
+
+// restore restores the streams in the named backup file, then closes the
+// store.
+func restore(srv *server.Server, name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatalf("failed to open backup: %v", err)
+	}
+	defer f.Close()
+	n, err := srv.RestoreFrom(context.Background(), f)
+	if err != nil {
+		log.Fatalf("failed to restore streams: %v", err)
+	}
+	log.Printf("restored %v streams from %v", n, name)
+	closeStore(srv)
+}
+
+// exportStreams writes every stream in the export format to the named file,
+// then closes the store.
+func exportStreams(srv *server.Server, name string) {
+	f, err := os.Create(name)
+	if err != nil {
+		log.Fatalf("failed to create export: %v", err)
+	}
+	n, err := srv.Export(context.Background(), f, *format, *history)
+	if err != nil {
+		log.Fatalf("failed to export streams: %v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		log.Fatalf("failed to write export: %v", err)
+	}
+	log.Printf("exported %v streams to %v", n, name)
+	closeStore(srv)
+}
+
+// importStreams creates the streams in the named file, in the export format,
+// then closes the store.
+func importStreams(srv *server.Server, name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatalf("failed to open import: %v", err)
+	}
+	defer f.Close()
+	n, err := srv.Import(context.Background(), f, *format)
+	if err != nil {
+		log.Fatalf("failed to import streams: %v", err)
+	}
+	log.Printf("imported %v streams from %v", n, name)
+	closeStore(srv)
+}
+
+// janitor deletes expired streams every interval, logging any it fails to
+// delete.
+func janitor(srv *server.Server, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for now := range tick.C {
+		n, err := srv.Sweep(now, *archive)
+		if err != nil {
+			log.Printf("failed to expire streams: %v", err)
+		}
+		if n > 0 {
+			log.Printf("expired %v streams", n)
+		}
+	}
+}
+
+// closeStore closes the server's store, if it can be closed.
+func closeStore(srv *server.Server) {
+	if c, ok := srv.DB.(io.Closer); ok {
+		err := c.Close()
+		if err != nil {
+			log.Fatalf("failed to close store: %v", err)
+		}
+	}
+}
 
 func main() {
-	srv, err := server.New(path)
+	flag.Parse()
+
+	p := *path
+	if p == "" {
+		p = filepath.FromSlash(defaultPaths[*backend])
+	}
+	srv, err := server.New(server.Config{
+		Backend:           *backend,
+		Path:              p,
+		SnapshotInterval:  *interval,
+		ForecastCacheSize: *cacheSize,
+	})
 	if err != nil {
 		log.Fatal("failed to create server:", err)
 	}
 
-	lis, err := net.Listen("tcp", port)
+	switch flag.Arg(0) {
+	case "":
+	case "rebuild":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		rebuild(srv, flag.Arg(1))
+		return
+	case "backup":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		backup(srv, flag.Arg(1))
+		return
+	case "restore":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		restore(srv, flag.Arg(1))
+		return
+	case "export":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		exportStreams(srv, flag.Arg(1))
+		return
+	case "import":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		importStreams(srv, flag.Arg(1))
+		return
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	lis, err := net.Listen("tcp", *port)
 	if err != nil {
 		log.Fatal("failed to listen:", err)
 	}
 
+	if *sweep > 0 {
+		go janitor(srv, *sweep)
+	}
+	if *metrics != "" {
+		go func() {
+			log.Fatal("failed to serve metrics:", http.ListenAndServe(*metrics, nil))
+		}()
+	}
+
 	s := grpc.NewServer()
 	seer.RegisterSeerServer(s, srv)
 	if err := s.Serve(lis); err != nil {
@@ -49,9 +49,28 @@ func Harmonics(min, max float64) []float64 {
 	return harmonics
 }
 
+// Params are the process variances of the deterministic component. A nil
+// Params on a Deterministic means the package defaults are used.
+type Params struct {
+	LevelVar    float64
+	TrendVar    float64
+	HarmonicVar float64
+}
+
+// DefaultParams returns the default deterministic process variances.
+func DefaultParams() (p *Params) {
+	p = &Params{
+		LevelVar:    levelVar,
+		TrendVar:    trendVar,
+		HarmonicVar: harmonicVar,
+	}
+	return p
+}
+
 // Deterministic is the type against which we apply deterministic model updates.
 type Deterministic struct {
 	*mv.Normal
+	Params *Params
 }
 
 // NewDeterministic creates and returns a Deterministic with a proper state prior.
@@ -66,10 +85,18 @@ func NewDeterministic(period float64) (d *Deterministic) {
 	}
 	cov := Diag(v)
 	n, _ := mv.NewNormal(loc, cov)
-	d = &Deterministic{n}
+	d = &Deterministic{Normal: n}
 	return d
 }
 
+// params returns the process variances in use by this deterministic.
+func (d *Deterministic) params() (p *Params) {
+	if d.Params == nil {
+		return DefaultParams()
+	}
+	return d.Params
+}
+
 // State returns the kalman filter State.
 func (d *Deterministic) State() (k *kalman.State) {
 	l := mat.NewDense(d.Dim(), 1, d.Location)
@@ -103,11 +130,12 @@ func (d *Deterministic) System(noise, walk, period float64) (k *kalman.System) {
 	}
 	c := mat.NewDense(1, dim, cVals)
 
+	p := d.params()
 	qVals := make([]float64, dim)
-	qVals[0] = levelVar
-	qVals[1] = trendVar
+	qVals[0] = p.LevelVar
+	qVals[1] = p.TrendVar
 	for i := 2; i < len(qVals); i++ {
-		qVals[i] = harmonicVar
+		qVals[i] = p.HarmonicVar
 	}
 	q := mat.NewDense(dim, dim, Diag(qVals))
 
@@ -132,10 +160,16 @@ func (d *Deterministic) Update(noise, walk, period, val float64) (resid float64,
 
 // Forecast returns a forecasted slice of normal RVs for this deterministic component.
 func (d *Deterministic) Forecast(period float64, n int) (f []*uv.Normal) {
+	return d.ForecastFrom(period, 0, n)
+}
+
+// ForecastFrom returns a forecasted slice of normal RVs for this deterministic
+// component, starting after skip unforecast periods.
+func (d *Deterministic) ForecastFrom(period float64, skip, n int) (f []*uv.Normal) {
 	f = make([]*uv.Normal, n)
 
-	st := d.State()
 	sy := d.System(0, 0, period)
+	st, _ := kalman.PredictN(d.State(), sy, skip)
 
 	for i := 0; i < n; i++ {
 		st, _ = kalman.Predict(st, sy)
@@ -147,3 +181,43 @@ func (d *Deterministic) Forecast(period float64, n int) (f []*uv.Normal) {
 	}
 	return f
 }
+
+// ForecastSum returns a forecasted slice of normal RVs for the sum of this
+// deterministic component over each of n consecutive windows.
+func (d *Deterministic) ForecastSum(period float64, n, window int) (f []*uv.Normal) {
+	return d.ForecastSumFrom(period, 0, n, window)
+}
+
+// ForecastSumFrom is ForecastSum, starting after skip unforecast periods.
+func (d *Deterministic) ForecastSumFrom(period float64, skip, n, window int) (f []*uv.Normal) {
+	sy := d.System(0, 0, period)
+	st, _ := kalman.PredictN(d.State(), sy, skip)
+	return forecastSum(st, sy, n, window)
+}
+
+// Transform returns a deterministic for the period to, carrying over the state
+// of one for the period from. Harmonics are shared between periods up to the
+// shorter period's, and keep their state, while the trend is rescaled to the
+// new period and any new harmonics start from the prior. Params are dropped,
+// as they were fit to the old period.
+func (d *Deterministic) Transform(from, to float64) (t *Deterministic) {
+	t = NewDeterministic(to)
+	n, dim := d.Dim(), t.Dim()
+	if dim < n {
+		n = dim
+	}
+
+	scale := make([]float64, n)
+	for i := range scale {
+		scale[i] = 1
+	}
+	scale[1] = to / from
+
+	for i := 0; i < n; i++ {
+		t.Location[i] = d.Location[i] * scale[i]
+		for j := 0; j < n; j++ {
+			t.Covariance[i*dim+j] = d.Covariance[i*d.Dim()+j] * scale[i] * scale[j]
+		}
+	}
+	return t
+}
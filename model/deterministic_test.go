@@ -180,6 +180,19 @@ func TestDeterministicSystem(t *testing.T) {
 	}
 }
 
+func TestDeterministicSystemParams(t *testing.T) {
+	d := model.NewDeterministic(604800)
+	d.Params = &model.Params{LevelVar: 1, TrendVar: 2, HarmonicVar: 3}
+
+	s := d.System(100, 10, 604800)
+
+	for i, v := range []float64{1, 2, 3, 3} {
+		if s.Q.At(i, i) != v {
+			t.Errorf("expected Q diagonal %v at position %v, but got %v", v, i, s.Q.At(i, i))
+		}
+	}
+}
+
 func TestDeterministicUpdate(t *testing.T) {
 	d := model.NewDeterministic(604800)
 
@@ -214,3 +227,42 @@ func TestDeterministicForecast(t *testing.T) {
 		t.Errorf("expected length %v, but it was %v", n, len(f))
 	}
 }
+
+func TestDeterministicTransform(t *testing.T) {
+	tt := []struct {
+		name string
+		from float64
+		to   float64
+	}{
+		{"hourly to daily", 3600, 86400},
+		{"daily to hourly", 86400, 3600},
+		{"same period", 3600, 3600},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d := model.NewDeterministic(tc.from)
+			for i := 0; i < 10; i++ {
+				d.Update(100, 10, tc.from, float64(i))
+			}
+			d.Params = &model.Params{LevelVar: 1, TrendVar: 1, HarmonicVar: 1}
+
+			tr := d.Transform(tc.from, tc.to)
+			if tr.Dim() != model.NewDeterministic(tc.to).Dim() {
+				t.Fatalf("expected dim %v, but it was %v", model.NewDeterministic(tc.to).Dim(), tr.Dim())
+			}
+			if tr.Params != nil {
+				t.Error("expected params to be dropped, but they were", tr.Params)
+			}
+			if tr.Location[0] != d.Location[0] {
+				t.Errorf("expected level %v, but it was %v", d.Location[0], tr.Location[0])
+			}
+			trend := d.Location[1] * tc.to / tc.from
+			if tr.Location[1] != trend {
+				t.Errorf("expected trend %v, but it was %v", trend, tr.Location[1])
+			}
+			if tr.Location[2] != d.Location[2] || tr.Covariance[2*tr.Dim()+2] != d.Covariance[2*d.Dim()+2] {
+				t.Error("expected shared harmonics to keep their state")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cshenton/seer/kalman"
+)
+
+const (
+	// minFitPoints is the fewest observations we will fit parameters against.
+	minFitPoints = 10
+	// maxFitPoints bounds the cost of a fit, only the most recent are used.
+	maxFitPoints = 500
+	// maxFitEvals bounds the number of likelihood evaluations in a fit.
+	maxFitEvals = 200
+	// fitBurnIn is the number of initial innovations, dominated by the
+	// diffuse level and trend priors, that are left out of the likelihood.
+	fitBurnIn = 2
+)
+
+// Fit estimates the deterministic process variances by maximising the Kalman
+// filter likelihood of the provided values, observed at the provided period,
+// with the provided random walk variance held fixed. The observation variance
+// is estimated jointly, but is not returned, since it is tracked online by the
+// RCE.
+func Fit(period, walk float64, vals []float64) (p *Params, err error) {
+	if len(vals) < minFitPoints {
+		err = fmt.Errorf("at least %v values are required to fit, but got %v", minFitPoints, len(vals))
+		return nil, err
+	}
+	if len(vals) > maxFitPoints {
+		vals = vals[len(vals)-maxFitPoints:]
+	}
+
+	// Search over log variances, starting from the scale of the differences.
+	s := diffVariance(vals)
+	x := []float64{
+		math.Log(s / 10),
+		math.Log(s / 1000),
+		math.Log(s / 100),
+		math.Log(s / 2),
+	}
+	cost := func(x []float64) float64 {
+		ll := LogLikelihood(period, vals, logParams(x), math.Exp(x[3]), walk)
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	x = patternSearch(cost, x, 2, 0.05, maxFitEvals)
+
+	return logParams(x), nil
+}
+
+// LogLikelihood returns the log likelihood of the provided values under the
+// deterministic model with the given process, observation and random walk
+// variances.
+func LogLikelihood(period float64, vals []float64, p *Params, noise, walk float64) (ll float64) {
+	d := NewDeterministic(period)
+	d.Params = p
+	st := d.State()
+	sy := d.System(noise, walk, period)
+
+	for i, v := range vals {
+		st, _ = kalman.Predict(st, sy)
+		ob, _ := kalman.Observe(st, sy)
+		if i >= fitBurnIn {
+			loc := ob.Loc.At(0, 0)
+			cov := ob.Cov.At(0, 0)
+			ll -= (math.Log(2*math.Pi*cov) + math.Pow(v-loc, 2)/cov) / 2
+		}
+		st, _, _ = kalman.Update(st, sy, v)
+	}
+	return ll
+}
+
+// logParams constructs Params from a slice of log variances.
+func logParams(x []float64) (p *Params) {
+	p = &Params{
+		LevelVar:    math.Exp(x[0]),
+		TrendVar:    math.Exp(x[1]),
+		HarmonicVar: math.Exp(x[2]),
+	}
+	return p
+}
+
+// diffVariance returns the mean square first difference of vals, or 1 if
+// that is zero.
+func diffVariance(vals []float64) (v float64) {
+	for i := 1; i < len(vals); i++ {
+		v += math.Pow(vals[i]-vals[i-1], 2)
+	}
+	v /= float64(len(vals) - 1)
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// patternSearch minimises f by coordinate wise search from x, halving the
+// step size whenever no coordinate move improves on the current point.
+func patternSearch(f func([]float64) float64, x []float64, step, minStep float64, maxEvals int) []float64 {
+	best := f(x)
+	evals := 1
+
+	for step >= minStep && evals < maxEvals {
+		improved := false
+		for i := range x {
+			for _, dir := range []float64{1, -1} {
+				y := append([]float64{}, x...)
+				y[i] += dir * step
+				v := f(y)
+				evals++
+				if v < best {
+					x, best = y, v
+					improved = true
+					break
+				}
+			}
+		}
+		if !improved {
+			step /= 2
+		}
+	}
+	return x
+}
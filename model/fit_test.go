@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package model_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/cshenton/seer/model"
+)
+
+func TestFit(t *testing.T) {
+	period := 604800.0
+	rnd := rand.New(rand.NewSource(1))
+	vals := make([]float64, 60)
+	for i := range vals {
+		vals[i] = 100 + 10*math.Sin(2*math.Pi*float64(i)/52) + rnd.NormFloat64()
+	}
+
+	p, err := model.Fit(period, 0.5, vals)
+	if err != nil {
+		t.Fatal("unexpected error in Fit:", err)
+	}
+	for _, v := range []float64{p.LevelVar, p.TrendVar, p.HarmonicVar} {
+		if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			t.Errorf("expected finite positive variance, but got %v", v)
+		}
+	}
+
+	fitted := model.LogLikelihood(period, vals, p, 1, 0.5)
+	defaults := model.LogLikelihood(period, vals, model.DefaultParams(), 1, 0.5)
+	if fitted <= defaults {
+		t.Errorf("expected fitted likelihood %v to exceed default %v", fitted, defaults)
+	}
+}
+
+func TestFitWalk(t *testing.T) {
+	period := 604800.0
+	rnd := rand.New(rand.NewSource(1))
+	vals := make([]float64, 60)
+	level := 100.0
+	for i := range vals {
+		level += 5 * rnd.NormFloat64()
+		vals[i] = level + rnd.NormFloat64()
+	}
+
+	// A walk that explains the level's drift leaves less for the level
+	// variance to.
+	still, _ := model.Fit(period, 0, vals)
+	walking, _ := model.Fit(period, 25, vals)
+	if walking.LevelVar >= still.LevelVar {
+		t.Errorf("expected level variance below %v with a walk, but got %v", still.LevelVar, walking.LevelVar)
+	}
+}
+
+func TestFitErrs(t *testing.T) {
+	_, err := model.Fit(604800, 0, []float64{1, 2, 3})
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
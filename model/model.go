@@ -19,8 +19,11 @@ package model
 
 import (
 	"math"
+	"math/rand"
 
 	"github.com/cshenton/seer/dist/uv"
+	"github.com/cshenton/seer/kalman"
+	"gonum.org/v1/gonum/mat"
 )
 
 // Model stores dynamic state about a stream.
@@ -47,12 +50,21 @@ func (m *Model) Update(period, val float64) {
 	m.Stochastic.Update(m.RCE.Noise(), m.RCE.Walk(), resid)
 }
 
-// Forecast returns a slice of Normally distributed predictions.
+// Forecast returns a slice of Normally distributed predictions. The noise and
+// walk variances enter the forecast covariance linearly, so using their
+// posterior means gives the predictive variance with those variances
+// integrated out.
 func (m *Model) Forecast(period float64, n int) (f []*uv.Normal) {
+	return m.ForecastFrom(period, 0, n)
+}
+
+// ForecastFrom is Forecast, starting after skip unforecast periods. The state
+// is advanced through the skipped periods in O(log skip) matrix products.
+func (m *Model) ForecastFrom(period float64, skip, n int) (f []*uv.Normal) {
 	f = make([]*uv.Normal, n)
 
-	d := m.Deterministic.Forecast(period, n)
-	s := m.Stochastic.Forecast(m.RCE.Noise(), m.RCE.Walk(), n)
+	d := m.Deterministic.ForecastFrom(period, skip, n)
+	s := m.Stochastic.ForecastFrom(m.RCE.Noise(), m.RCE.Walk(), skip, n)
 
 	for i := range f {
 		dist := &uv.Normal{
@@ -63,3 +75,99 @@ func (m *Model) Forecast(period float64, n int) (f []*uv.Normal) {
 	}
 	return f
 }
+
+// ForecastSum returns a slice of Normally distributed predictions of the sum
+// of each of n consecutive windows of future observations.
+func (m *Model) ForecastSum(period float64, n, window int) (f []*uv.Normal) {
+	return m.ForecastSumFrom(period, 0, n, window)
+}
+
+// ForecastSumFrom is ForecastSum, starting after skip unforecast periods.
+func (m *Model) ForecastSumFrom(period float64, skip, n, window int) (f []*uv.Normal) {
+	f = make([]*uv.Normal, n)
+
+	d := m.Deterministic.ForecastSumFrom(period, skip, n, window)
+	s := m.Stochastic.ForecastSumFrom(m.RCE.Noise(), m.RCE.Walk(), skip, n, window)
+
+	for i := range f {
+		dist := &uv.Normal{
+			Location: d[i].Location + s[i].Location,
+			Scale:    math.Sqrt(math.Pow(d[i].Scale, 2) + math.Pow(s[i].Scale, 2)),
+		}
+		f[i] = dist
+	}
+	return f
+}
+
+// forecastSum forecasts the sum of each of n consecutive windows of
+// observations from the system, using the joint covariance of the states
+// across each window.
+func forecastSum(st *kalman.State, sy *kalman.System, n, window int) (f []*uv.Normal) {
+	f = make([]*uv.Normal, n)
+
+	as, am, _ := kalman.Accumulate(st, sy)
+	noise := sy.R.At(0, 0) * float64(window)
+
+	for i := range f {
+		kalman.Reset(as)
+		for j := 0; j < window; j++ {
+			as, _ = kalman.Predict(as, am)
+		}
+		ob, _ := kalman.StateObserve(as, am)
+		f[i] = &uv.Normal{
+			Location: DenseValues(ob.Loc)[0],
+			Scale:    math.Sqrt(DenseValues(ob.Cov)[0] + noise),
+		}
+	}
+	return f
+}
+
+// Sample draws sample paths of length n from the forecast distribution. Each
+// path uses its own posterior draw of the noise and walk covariances, so that
+// the paths share the heavier tails of the predictive distribution.
+func (m *Model) Sample(period float64, n, paths int, rnd *rand.Rand) (s [][]float64, err error) {
+	det, err := m.Deterministic.Sample(rnd, paths)
+	if err != nil {
+		return nil, err
+	}
+	sy := m.Deterministic.System(0, 0, period)
+	dim := m.Deterministic.Dim()
+	c := sy.C.RawRowView(0)
+	sd := make([]float64, dim)
+	for i := range sd {
+		sd[i] = math.Sqrt(sy.Q.At(i, i))
+	}
+
+	s = make([][]float64, paths)
+	next := mat.NewVecDense(dim, nil)
+	for i := range s {
+		noise, walk := m.RCE.Sample(rnd)
+		cov := m.Stochastic.Covariance[0] * noise / m.RCE.Noise()
+		level := m.Stochastic.Location[0] + math.Sqrt(cov)*rnd.NormFloat64()
+		x := mat.NewVecDense(dim, det[i])
+
+		s[i] = make([]float64, n)
+		for j := range s[i] {
+			next.MulVec(sy.A, x)
+			x.CopyVec(next)
+			for k := range sd {
+				x.SetVec(k, x.AtVec(k)+sd[k]*rnd.NormFloat64())
+			}
+			level += math.Sqrt(walk) * rnd.NormFloat64()
+			s[i][j] = mat.Dot(mat.NewVecDense(dim, c), x) + level + math.Sqrt(noise)*rnd.NormFloat64()
+		}
+	}
+	return s, nil
+}
+
+// Transform returns a model for the period to, carrying over the state of one
+// for the period from. The noise and walk estimates are kept, and adapt to the
+// new period as events arrive.
+func (m *Model) Transform(from, to float64) (t *Model) {
+	t = &Model{
+		Deterministic: m.Deterministic.Transform(from, to),
+		Stochastic:    m.Stochastic,
+		RCE:           m.RCE,
+	}
+	return t
+}
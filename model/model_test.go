@@ -18,8 +18,11 @@
 package model_test
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
+	"github.com/cshenton/seer/dist/uv"
 	"github.com/cshenton/seer/model"
 )
 
@@ -32,8 +35,8 @@ func TestNew(t *testing.T) {
 	if len(m.Stochastic.Location) != 1 {
 		t.Errorf("expected stochastic location dim of %v, but got %v", 1, len(m.Stochastic.Location))
 	}
-	if m.RCE.Theta.Scale != 180 {
-		t.Errorf("expected initial theta scale of %v, but got %v", 180, m.RCE.Theta.Scale)
+	if m.RCE.Noise() != 80 {
+		t.Errorf("expected initial noise of %v, but got %v", 80, m.RCE.Noise())
 	}
 }
 
@@ -50,7 +53,7 @@ func TestModelUpdate(t *testing.T) {
 		t.Error("stochastic not updated")
 	}
 
-	if m.RCE.History[0] == 0 {
+	if m.RCE.Levels[0].Covariance[0] != 1 {
 		t.Error("RCE not updated")
 	}
 }
@@ -67,3 +70,147 @@ func TestModelForecast(t *testing.T) {
 		t.Errorf("expected length %v, but it was %v", n, len(f))
 	}
 }
+
+func TestModelForecastSum(t *testing.T) {
+	period := 86400.0
+	m := model.New(period)
+	m.Deterministic.Params = &model.Params{LevelVar: 1e-2, TrendVar: 1e-6, HarmonicVar: 1e-6}
+	for i := 0; i < 50; i++ {
+		m.Update(period, float64(i%7))
+	}
+
+	// Windows of a single observation are the marginal forecasts.
+	f := m.Forecast(period, 5)
+	s := m.ForecastSum(period, 5, 1)
+	for i := range f {
+		if math.Abs(f[i].Location-s[i].Location) > 1e-6 || math.Abs(f[i].Scale-s[i].Scale) > 1e-6 {
+			t.Errorf("expected sum %v, %v at %v, but got %v, %v", f[i].Location, f[i].Scale, i, s[i].Location, s[i].Scale)
+		}
+	}
+
+	// Sums share the marginal means, but not their variances, since the
+	// observations are correlated through the level.
+	f = m.Forecast(period, 6)
+	s = m.ForecastSum(period, 2, 3)
+	for i := range s {
+		var loc, vr float64
+		for j := 3 * i; j < 3*i+3; j++ {
+			loc += f[j].Location
+			vr += math.Pow(f[j].Scale, 2)
+		}
+		if math.Abs(s[i].Location-loc) > 1e-6 {
+			t.Errorf("expected sum location %v, but got %v", loc, s[i].Location)
+		}
+		if math.Pow(s[i].Scale, 2) <= vr {
+			t.Errorf("expected sum variance greater than %v, but got %v", vr, math.Pow(s[i].Scale, 2))
+		}
+	}
+}
+
+func TestModelForecastFrom(t *testing.T) {
+	period := 3600.0
+	m := model.New(period)
+	m.Deterministic.Params = &model.Params{LevelVar: 1e-2, TrendVar: 1e-6, HarmonicVar: 1e-6}
+	for i := 0; i < 50; i++ {
+		m.Update(period, float64(i%24))
+	}
+
+	tt := []struct {
+		name   string
+		skip   int
+		n      int
+		window int
+	}{
+		{"no skip", 0, 5, 1},
+		{"one period", 1, 5, 1},
+		{"a day", 24, 8, 1},
+		{"odd skip", 37, 3, 1},
+		{"windowed", 24, 3, 4},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			total := tc.skip + tc.n*tc.window
+			var f, g []*uv.Normal
+			if tc.window == 1 {
+				f = m.Forecast(period, total)[tc.skip:]
+				g = m.ForecastFrom(period, tc.skip, tc.n)
+			} else {
+				f = m.ForecastSum(period, total/tc.window, tc.window)[tc.skip/tc.window:]
+				g = m.ForecastSumFrom(period, tc.skip, tc.n, tc.window)
+			}
+			if len(g) != tc.n {
+				t.Fatalf("expected length %v, but it was %v", tc.n, len(g))
+			}
+			for i := range g {
+				if math.Abs(f[i].Location-g[i].Location) > 1e-6 || math.Abs(f[i].Scale-g[i].Scale) > 1e-6 {
+					t.Errorf("expected %v, %v at %v, but got %v, %v", f[i].Location, f[i].Scale, i, g[i].Location, g[i].Scale)
+				}
+			}
+		})
+	}
+}
+
+func TestModelSample(t *testing.T) {
+	period := 86400.0
+	m := model.New(period)
+	m.Deterministic.Params = &model.Params{LevelVar: 1e-2, TrendVar: 1e-6, HarmonicVar: 1e-6}
+
+	rnd := rand.New(rand.NewSource(1))
+	level := 0.0
+	for i := 0; i < 200; i++ {
+		level += rnd.NormFloat64()
+		m.Update(period, level+rnd.NormFloat64())
+	}
+
+	n, paths := 5, 4000
+	s, err := m.Sample(period, n, paths, rnd)
+	if err != nil {
+		t.Fatal("unexpected error in Sample:", err)
+	}
+	if len(s) != paths {
+		t.Fatalf("expected %v paths, but got %v", paths, len(s))
+	}
+
+	f := m.Forecast(period, n)
+	for j := 0; j < n; j++ {
+		var mean, sq float64
+		for i := range s {
+			mean += s[i][j] / float64(paths)
+			sq += s[i][j] * s[i][j] / float64(paths)
+		}
+		sd := math.Sqrt(sq - mean*mean)
+		if math.Abs(mean-f[j].Location) > 0.1*f[j].Scale {
+			t.Errorf("expected mean %v at step %v, but got %v", f[j].Location, j, mean)
+		}
+		if math.Abs(sd-f[j].Scale)/f[j].Scale > 0.1 {
+			t.Errorf("expected sd %v at step %v, but got %v", f[j].Scale, j, sd)
+		}
+	}
+
+	// Paths should be positively correlated across steps, as the level walks.
+	var cov float64
+	for i := range s {
+		cov += (s[i][0] - f[0].Location) * (s[i][n-1] - f[n-1].Location) / float64(paths)
+	}
+	if cov <= 0 {
+		t.Errorf("expected positive covariance between steps, but got %v", cov)
+	}
+}
+
+func TestModelTransform(t *testing.T) {
+	m := model.New(3600)
+	for i := 0; i < 10; i++ {
+		m.Update(3600, float64(i))
+	}
+
+	tr := m.Transform(3600, 86400)
+	if tr.RCE.Noise() != m.RCE.Noise() {
+		t.Errorf("expected noise %v, but it was %v", m.RCE.Noise(), tr.RCE.Noise())
+	}
+	if tr.Stochastic.Location[0] != m.Stochastic.Location[0] {
+		t.Errorf("expected stochastic level %v, but it was %v", m.Stochastic.Location[0], tr.Stochastic.Location[0])
+	}
+	if f := tr.Forecast(86400, 5); len(f) != 5 {
+		t.Errorf("expected %v forecasts, but got %v", 5, len(f))
+	}
+}
@@ -19,56 +19,190 @@ package model
 
 import (
 	"math"
+	"math/rand"
 
+	"github.com/cshenton/seer/dist/mv"
 	"github.com/cshenton/seer/dist/uv"
 )
 
-// History contains the last two observations for this stream, it is required
-// to do covariance estimation in real time.
-type History []float64
-
-// Update shifts the history one place down and adds a new value.
-func (h History) Update(v float64) {
-	h[1] = h[0]
-	h[0] = v
-}
+const (
+	// varianceFloor is the smallest variance the RCE will report, it guards
+	// the kalman filters against degenerate covariances.
+	varianceFloor = 1e-6
+	// ratioMin, ratioMax and ratioSteps define the logarithmic grid of walk to
+	// noise variance ratios the RCE considers.
+	ratioMin   = 1e-4
+	ratioMax   = 1e4
+	ratioSteps = 65
+	// ratioLoc and ratioScale parameterise the log normal prior on the ratio.
+	ratioLoc   = 0.125
+	ratioScale = 2
+	// levelCov is the diffuse prior level covariance, in units of the noise.
+	levelCov = 1e8
+	// defaultNoise and defaultWalk are reported until there is enough data
+	// for the posterior means to exist.
+	defaultNoise = 80
+	defaultWalk  = 10
+)
 
 // RCE is a recursive covariance estimator for a local level kalman filter.
+//
+// It is a conjugate learner, given the ratio of walk to noise variance the
+// local level filter is exact and the noise variance has an inverse gamma
+// posterior. The ratio itself is restricted to a logarithmic grid, over which
+// the RCE keeps posterior weights, so the joint posterior is exact. The noise
+// prior is the scale free limit of the inverse gamma, so the estimates do not
+// depend on the units of the stream.
 type RCE struct {
-	Theta   *uv.InverseGamma
-	Zeta    *uv.InverseGamma
-	History History
+	// Ratios is the grid of walk to noise variance ratios.
+	Ratios []float64
+	// Weights are the log posterior weights of each ratio.
+	Weights []float64
+	// Levels are the posterior levels given each ratio, in units of the noise.
+	Levels []*mv.Normal
+	// Variances are the posterior noise variances given each ratio.
+	Variances []*uv.InverseGamma
 }
 
-// NewRCE constructs an RCE with appropriate priors for theta and zeta.
+// NewRCE constructs an RCE with appropriate priors for the noise variance and
+// walk to noise ratio.
 func NewRCE() (r *RCE) {
-	h := History([]float64{0, 0})
-	t, _ := uv.NewInverseGamma(2, 180)
-	z, _ := uv.NewInverseGamma(2, 100)
-
 	r = &RCE{
-		Theta:   t,
-		Zeta:    z,
-		History: h,
+		Ratios:    make([]float64, ratioSteps),
+		Weights:   make([]float64, ratioSteps),
+		Levels:    make([]*mv.Normal, ratioSteps),
+		Variances: make([]*uv.InverseGamma, ratioSteps),
+	}
+	step := math.Log(ratioMax/ratioMin) / (ratioSteps - 1)
+	for i := range r.Ratios {
+		lr := math.Log(ratioMin) + float64(i)*step
+		r.Ratios[i] = math.Exp(lr)
+		r.Weights[i] = -math.Pow((lr-math.Log(ratioLoc))/ratioScale, 2) / 2
+		r.Levels[i], _ = mv.NewNormal([]float64{0}, []float64{levelCov})
+		r.Variances[i] = &uv.InverseGamma{}
 	}
+	r.normalise()
 	return r
 }
 
-// Walk returns the current walk covariance.
+// Walk returns the posterior mean walk covariance.
 func (r *RCE) Walk() float64 {
-	return math.Abs(r.Zeta.Mean() - 0.5*r.Theta.Mean())
+	if !r.proper() {
+		return defaultWalk
+	}
+	var w float64
+	for i, p := range r.probs() {
+		w += p * r.Ratios[i] * r.Variances[i].Mean()
+	}
+	return math.Max(w, varianceFloor)
 }
 
-// Noise returns the current noise covariance.
+// Noise returns the posterior mean noise covariance.
 func (r *RCE) Noise() float64 {
-	return math.Abs(r.Zeta.Mean() - 2*r.Walk())
+	if !r.proper() {
+		return defaultNoise
+	}
+	var n float64
+	for i, p := range r.probs() {
+		n += p * r.Variances[i].Mean()
+	}
+	return math.Max(n, varianceFloor)
 }
 
-// Update updates the covariance estimator.
+// Dof returns the degrees of freedom of the noise posterior, which are also
+// those of the Student's t predictive distribution.
+func (r *RCE) Dof() float64 {
+	if len(r.Variances) == 0 {
+		return 0
+	}
+	return 2 * r.Variances[0].Shape
+}
+
+// Sample draws a noise and walk covariance from the posterior. It returns the
+// posterior means while the posterior is improper.
+func (r *RCE) Sample(rnd *rand.Rand) (noise, walk float64) {
+	if !r.proper() {
+		return r.Noise(), r.Walk()
+	}
+	u := rnd.Float64()
+	i := 0
+	for j, p := range r.probs() {
+		i = j
+		if u -= p; u < 0 {
+			break
+		}
+	}
+	noise = r.Variances[i].Sample(rnd)
+	walk = r.Ratios[i] * noise
+	return math.Max(noise, varianceFloor), math.Max(walk, varianceFloor)
+}
+
+// Update updates the covariance estimator with a new observation.
 func (r *RCE) Update(v float64) {
-	r.Zeta.Shape += 0.5
-	r.Theta.Shape += 0.5
-	r.Zeta.Scale += math.Pow(v-r.History[0], 2) / 2
-	r.Theta.Scale += math.Pow(v-r.History[1], 2) / 2
-	r.History.Update(v)
+	if len(r.Ratios) == 0 {
+		*r = *NewRCE()
+	}
+	for i, q := range r.Ratios {
+		l, ig := r.Levels[i], r.Variances[i]
+
+		// The first observation only locates the diffuse level.
+		if l.Covariance[0] >= levelCov {
+			l.Location[0] = v
+			l.Covariance[0] = 1
+			continue
+		}
+
+		// One step predictive in units of the noise variance.
+		pc := l.Covariance[0] + q
+		f := pc + 1
+		e := v - l.Location[0]
+
+		if ig.Shape > 0 && ig.Scale > 0 {
+			r.Weights[i] += studentLogPdf(e, 2*ig.Shape, f*ig.Scale/ig.Shape)
+		}
+
+		l.Location[0] += pc / f * e
+		l.Covariance[0] = pc / f
+		ig.Shape += 0.5
+		ig.Scale += e * e / (2 * f)
+	}
+	r.normalise()
+}
+
+// proper reports whether the noise posterior has a finite mean.
+func (r *RCE) proper() bool {
+	return len(r.Variances) > 0 && r.Variances[0].Shape > 1
+}
+
+// probs returns the posterior probability of each ratio.
+func (r *RCE) probs() (p []float64) {
+	p = make([]float64, len(r.Weights))
+	var total float64
+	for i, w := range r.Weights {
+		p[i] = math.Exp(w)
+		total += p[i]
+	}
+	for i := range p {
+		p[i] /= total
+	}
+	return p
+}
+
+// normalise shifts the log weights so that the largest is zero.
+func (r *RCE) normalise() {
+	max := math.Inf(-1)
+	for _, w := range r.Weights {
+		max = math.Max(max, w)
+	}
+	for i := range r.Weights {
+		r.Weights[i] -= max
+	}
+}
+
+// studentLogPdf is the log density of x under a zero location Student's t
+// distribution with nu degrees of freedom and squared scale s2.
+func studentLogPdf(x, nu, s2 float64) float64 {
+	a, _ := math.Lgamma((nu + 1) / 2)
+	b, _ := math.Lgamma(nu / 2)
+	return a - b - math.Log(nu*math.Pi*s2)/2 - (nu+1)/2*math.Log1p(x*x/(nu*s2))
 }
@@ -18,37 +18,24 @@
 package model_test
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/cshenton/seer/model"
 )
 
-func TestHistoryUpdate(t *testing.T) {
-	h := model.History([]float64{1, 2})
-	h.Update(3)
-
-	if h[0] != 3 {
-		t.Errorf("expected entry 0 to be %v, but it was %v", 3, h[0])
-	}
-	if h[1] != 1 {
-		t.Errorf("expected entry 1 to be %v, but it was %v", 1, h[1])
-	}
-}
-
 func TestNewRCE(t *testing.T) {
 	r := model.NewRCE()
 
-	if r.History[0] != 0 {
-		t.Errorf("expected history 0 of %v, but it was %v", 0, r.History[0])
-	}
-	if r.History[1] != 0 {
-		t.Errorf("expected history 1 of %v, but it was %v", 0, r.History[1])
+	if len(r.Ratios) != 65 {
+		t.Fatalf("expected %v ratios, but there were %v", 65, len(r.Ratios))
 	}
-	if r.Theta.Scale != 180 {
-		t.Errorf("expected theta scale of %v, but it was %v", 180, r.Theta.Scale)
+	if len(r.Weights) != len(r.Ratios) || len(r.Levels) != len(r.Ratios) || len(r.Variances) != len(r.Ratios) {
+		t.Fatal("expected weights, levels and variances for every ratio")
 	}
-	if r.Zeta.Scale != 100 {
-		t.Errorf("expected zeta scale of %v, but it was %v", 100, r.Zeta.Scale)
+	if r.Variances[0].Shape != 0 || r.Variances[0].Scale != 0 {
+		t.Errorf("expected scale free variance prior, but it was %v", r.Variances[0])
 	}
 }
 
@@ -68,26 +55,79 @@ func TestRCENoise(t *testing.T) {
 	}
 }
 
-func TestRCEUpdate(t *testing.T) {
+func TestRCEFloor(t *testing.T) {
 	r := model.NewRCE()
-	r.Update(1.0)
+	for _, v := range r.Variances {
+		v.Shape = 10
+		v.Scale = 1e-20
+	}
 
-	if r.Theta.Shape != 2.5 {
-		t.Errorf("expected theta shape %v, got %v", 2.5, r.Theta.Shape)
+	if r.Noise() <= 0 {
+		t.Errorf("expected positive noise covariance, but it was %v", r.Noise())
 	}
-	if r.Theta.Scale != 180.5 {
-		t.Errorf("expected theta scale %v, got %v", 180.5, r.Theta.Scale)
+	if r.Walk() <= 0 {
+		t.Errorf("expected positive walk covariance, but it was %v", r.Walk())
 	}
-	if r.Zeta.Shape != 2.5 {
-		t.Errorf("expected zeta shape %v, got %v", 2.5, r.Zeta.Shape)
+}
+
+func TestRCEUpdate(t *testing.T) {
+	r := model.NewRCE()
+	r.Update(1.0)
+	r.Update(2.0)
+
+	for i := range r.Ratios {
+		if r.Variances[i].Shape != 0.5 {
+			t.Errorf("expected variance shape %v, got %v", 0.5, r.Variances[i].Shape)
+		}
+		if r.Variances[i].Scale <= 0 {
+			t.Errorf("expected variance scale to increase from %v, got %v", 0, r.Variances[i].Scale)
+		}
+		if r.Levels[i].Location[0] <= 1 {
+			t.Error("expected level to be updated, but it was not")
+		}
 	}
-	if r.Zeta.Scale != 100.5 {
-		t.Errorf("expected theta scale %v, got %v", 100.5, r.Zeta.Scale)
+}
+
+func TestRCELegacy(t *testing.T) {
+	r := &model.RCE{}
+	r.Update(1.0)
+
+	if len(r.Ratios) == 0 {
+		t.Error("expected empty RCE to be initialised on update")
 	}
-	if r.History[0] != 1 {
-		t.Errorf("expected history 0 of %v, got %v", 1, r.History[0])
+}
+
+func TestRCEConvergence(t *testing.T) {
+	tt := []struct {
+		name  string
+		noise float64
+		walk  float64
+	}{
+		{"noise dominated", 4, 0.25},
+		{"walk dominated", 0.25, 4},
+		{"balanced", 1, 1},
 	}
-	if r.History[1] != 0 {
-		t.Errorf("expected history 1 of %v, got %v", 0, r.History[1])
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			r := model.NewRCE()
+
+			level := 0.0
+			for i := 0; i < 5000; i++ {
+				level += math.Sqrt(tc.walk) * rnd.NormFloat64()
+				v := level + math.Sqrt(tc.noise)*rnd.NormFloat64()
+				r.Update(v)
+			}
+
+			// The smaller component is only weakly identified, so both are
+			// checked against the total variance.
+			tol := 0.15 * (tc.noise + tc.walk)
+			if math.Abs(r.Noise()-tc.noise) > tol {
+				t.Errorf("expected noise near %v, but it was %v", tc.noise, r.Noise())
+			}
+			if math.Abs(r.Walk()-tc.walk) > tol {
+				t.Errorf("expected walk near %v, but it was %v", tc.walk, r.Walk())
+			}
+		})
 	}
 }
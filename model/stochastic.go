@@ -76,10 +76,16 @@ func (s *Stochastic) Update(noise, walk, val float64) (err error) {
 
 // Forecast returns a forecasted slice of normal RVs for this stochastic component.
 func (s *Stochastic) Forecast(noise, walk float64, n int) (f []*uv.Normal) {
+	return s.ForecastFrom(noise, walk, 0, n)
+}
+
+// ForecastFrom returns a forecasted slice of normal RVs for this stochastic
+// component, starting after skip unforecast periods.
+func (s *Stochastic) ForecastFrom(noise, walk float64, skip, n int) (f []*uv.Normal) {
 	f = make([]*uv.Normal, n)
 
-	st := s.State()
 	sy := s.System(noise, walk)
+	st, _ := kalman.PredictN(s.State(), sy, skip)
 
 	for i := 0; i < n; i++ {
 		st, _ = kalman.Predict(st, sy)
@@ -91,3 +97,16 @@ func (s *Stochastic) Forecast(noise, walk float64, n int) (f []*uv.Normal) {
 	}
 	return f
 }
+
+// ForecastSum returns a forecasted slice of normal RVs for the sum of this
+// stochastic component over each of n consecutive windows.
+func (s *Stochastic) ForecastSum(noise, walk float64, n, window int) (f []*uv.Normal) {
+	return s.ForecastSumFrom(noise, walk, 0, n, window)
+}
+
+// ForecastSumFrom is ForecastSum, starting after skip unforecast periods.
+func (s *Stochastic) ForecastSumFrom(noise, walk float64, skip, n, window int) (f []*uv.Normal) {
+	sy := s.System(noise, walk)
+	st, _ := kalman.PredictN(s.State(), sy, skip)
+	return forecastSum(st, sy, n, window)
+}
@@ -9,9 +9,13 @@ It is generated from these files:
 
 It has these top-level messages:
 	Stream
+	Retention
+	SnapshotPolicy
 	Event
 	Interval
 	Forecast
+	Path
+	Samples
 	CreateStreamRequest
 	GetStreamRequest
 	DeleteStreamRequest
@@ -19,14 +23,49 @@ It has these top-level messages:
 	ListStreamsResponse
 	UpdateStreamRequest
 	GetForecastRequest
+	RefitStreamRequest
+	SampleForecastRequest
+	GetEventsRequest
+	GetEventsResponse
+	RebuildStreamRequest
+	RebuildProgress
+	RollbackStreamRequest
+	BackupChunk
+	RestoreResponse
+	ExportStreamsRequest
+	ExportChunk
+	ImportStreamsRequest
+	ImportStreamsResponse
+	Namespace
+	CreateNamespaceRequest
+	GetNamespaceRequest
+	UpdateNamespaceRequest
+	DeleteNamespaceRequest
+	ListNamespacesResponse
+	PatchStreamRequest
+	PatchStreamResponse
+	RenameStreamRequest
+	CloneStreamRequest
+	ListExpiredStreamsRequest
+	ListExpiredStreamsResponse
+	BatchGetForecastRequest
+	BatchGetForecastResponse
+	StreamForecast
+	BatchError
+	BatchUpdateStreamsRequest
+	BatchUpdateStreamsResponse
+	StreamUpdate
+	ScenarioForecastRequest
 */
 package seer
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
-import google_protobuf "github.com/golang/protobuf/ptypes/empty"
-import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
+import google_protobuf "github.com/golang/protobuf/ptypes/duration"
+import google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
+import google_protobuf2 "google.golang.org/genproto/protobuf/field_mask"
+import google_protobuf3 "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -74,14 +113,118 @@ func (x Domain) String() string {
 }
 func (Domain) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+type Aggregation int32
+
+const (
+	Aggregation_NONE Aggregation = 0
+	Aggregation_SUM  Aggregation = 1
+	Aggregation_MEAN Aggregation = 2
+)
+
+var Aggregation_name = map[int32]string{
+	0: "NONE",
+	1: "SUM",
+	2: "MEAN",
+}
+var Aggregation_value = map[string]int32{
+	"NONE": 0,
+	"SUM":  1,
+	"MEAN": 2,
+}
+
+func (x Aggregation) String() string {
+	return proto.EnumName(Aggregation_name, int32(x))
+}
+func (Aggregation) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+// The orders streams can be listed in, streams with the same last event time
+// are listed by name
+type ListOrder int32
+
+const (
+	ListOrder_BY_NAME            ListOrder = 0
+	ListOrder_BY_LAST_EVENT_TIME ListOrder = 1
+)
+
+var ListOrder_name = map[int32]string{
+	0: "BY_NAME",
+	1: "BY_LAST_EVENT_TIME",
+}
+var ListOrder_value = map[string]int32{
+	"BY_NAME":            0,
+	"BY_LAST_EVENT_TIME": 1,
+}
+
+func (x ListOrder) String() string {
+	return proto.EnumName(ListOrder_name, int32(x))
+}
+func (ListOrder) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+// The formats streams can be exported in
+type ExportFormat int32
+
+const (
+	ExportFormat_JSON ExportFormat = 0
+	ExportFormat_CSV  ExportFormat = 1
+)
+
+var ExportFormat_name = map[int32]string{
+	0: "JSON",
+	1: "CSV",
+}
+var ExportFormat_value = map[string]int32{
+	"JSON": 0,
+	"CSV":  1,
+}
+
+func (x ExportFormat) String() string {
+	return proto.EnumName(ExportFormat_name, int32(x))
+}
+func (ExportFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+// How a stream's model was changed by a patch. Changes to its period alter the
+// model's state space, so are transformed to the new period, or for streams
+// that have seen no events, re-initialised
+type Migration int32
+
+const (
+	Migration_IN_PLACE      Migration = 0
+	Migration_TRANSFORMED   Migration = 1
+	Migration_REINITIALISED Migration = 2
+)
+
+var Migration_name = map[int32]string{
+	0: "IN_PLACE",
+	1: "TRANSFORMED",
+	2: "REINITIALISED",
+}
+var Migration_value = map[string]int32{
+	"IN_PLACE":      0,
+	"TRANSFORMED":   1,
+	"REINITIALISED": 2,
+}
+
+func (x Migration) String() string {
+	return proto.EnumName(Migration_name, int32(x))
+}
+func (Migration) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
 // A data stream
 type Stream struct {
 	Name          string                      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Period        float64                     `protobuf:"fixed64,2,opt,name=period" json:"period,omitempty"`
-	LastEventTime *google_protobuf1.Timestamp `protobuf:"bytes,3,opt,name=last_event_time,json=lastEventTime" json:"last_event_time,omitempty"`
+	LastEventTime *google_protobuf3.Timestamp `protobuf:"bytes,3,opt,name=last_event_time,json=lastEventTime" json:"last_event_time,omitempty"`
 	Domain        Domain                      `protobuf:"varint,4,opt,name=domain,enum=seer.Domain" json:"domain,omitempty"`
 	Min           float64                     `protobuf:"fixed64,5,opt,name=min" json:"min,omitempty"`
 	Max           float64                     `protobuf:"fixed64,6,opt,name=max" json:"max,omitempty"`
+	Revision      uint64                      `protobuf:"varint,7,opt,name=revision" json:"revision,omitempty"`
+	Retention     *Retention                  `protobuf:"bytes,8,opt,name=retention" json:"retention,omitempty"`
+	Snapshots     *SnapshotPolicy             `protobuf:"bytes,9,opt,name=snapshots" json:"snapshots,omitempty"`
+	// Labels for selecting streams in ListStreams, see label_selector
+	Labels map[string]string `protobuf:"bytes,10,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// How long after its last event the stream expires and is deleted by the
+	// server's janitor, unset or zero never expires
+	Ttl *google_protobuf.Duration `protobuf:"bytes,11,opt,name=ttl" json:"ttl,omitempty"`
 }
 
 func (m *Stream) Reset()                    { *m = Stream{} }
@@ -103,7 +246,7 @@ func (m *Stream) GetPeriod() float64 {
 	return 0
 }
 
-func (m *Stream) GetLastEventTime() *google_protobuf1.Timestamp {
+func (m *Stream) GetLastEventTime() *google_protobuf3.Timestamp {
 	if m != nil {
 		return m.LastEventTime
 	}
@@ -131,18 +274,107 @@ func (m *Stream) GetMax() float64 {
 	return 0
 }
 
+func (m *Stream) GetRevision() uint64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Stream) GetRetention() *Retention {
+	if m != nil {
+		return m.Retention
+	}
+	return nil
+}
+
+func (m *Stream) GetSnapshots() *SnapshotPolicy {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+func (m *Stream) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Stream) GetTtl() *google_protobuf.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+// Which of a stream's raw events to keep, at most count events and none older
+// than age before its latest event, where zero is unbounded. Events are only
+// kept for streams with a retention policy.
+type Retention struct {
+	Count int64                     `protobuf:"varint,1,opt,name=count" json:"count,omitempty"`
+	Age   *google_protobuf.Duration `protobuf:"bytes,2,opt,name=age" json:"age,omitempty"`
+}
+
+func (m *Retention) Reset()                    { *m = Retention{} }
+func (m *Retention) String() string            { return proto.CompactTextString(m) }
+func (*Retention) ProtoMessage()               {}
+func (*Retention) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Retention) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Retention) GetAge() *google_protobuf.Duration {
+	if m != nil {
+		return m.Age
+	}
+	return nil
+}
+
+// How many prior states of a stream to keep, so that it can be rolled back. A
+// state is kept if it is at least interval, in stream time, after the latest
+// kept state, and at most count states are kept.
+type SnapshotPolicy struct {
+	Count    int64                     `protobuf:"varint,1,opt,name=count" json:"count,omitempty"`
+	Interval *google_protobuf.Duration `protobuf:"bytes,2,opt,name=interval" json:"interval,omitempty"`
+}
+
+func (m *SnapshotPolicy) Reset()                    { *m = SnapshotPolicy{} }
+func (m *SnapshotPolicy) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotPolicy) ProtoMessage()               {}
+func (*SnapshotPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *SnapshotPolicy) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *SnapshotPolicy) GetInterval() *google_protobuf.Duration {
+	if m != nil {
+		return m.Interval
+	}
+	return nil
+}
+
 // A set of ordered events (values and times) in a stream
 type Event struct {
-	Times  []*google_protobuf1.Timestamp `protobuf:"bytes,1,rep,name=times" json:"times,omitempty"`
+	Times  []*google_protobuf3.Timestamp `protobuf:"bytes,1,rep,name=times" json:"times,omitempty"`
 	Values []float64                     `protobuf:"fixed64,2,rep,packed,name=values" json:"values,omitempty"`
 }
 
 func (m *Event) Reset()                    { *m = Event{} }
 func (m *Event) String() string            { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()               {}
-func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
-func (m *Event) GetTimes() []*google_protobuf1.Timestamp {
+func (m *Event) GetTimes() []*google_protobuf3.Timestamp {
 	if m != nil {
 		return m.Times
 	}
@@ -166,7 +398,7 @@ type Interval struct {
 func (m *Interval) Reset()                    { *m = Interval{} }
 func (m *Interval) String() string            { return proto.CompactTextString(m) }
 func (*Interval) ProtoMessage()               {}
-func (*Interval) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*Interval) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *Interval) GetProbability() float64 {
 	if m != nil {
@@ -191,7 +423,7 @@ func (m *Interval) GetUpperBound() []float64 {
 
 // A forecast, with point predictions and confidence intervals
 type Forecast struct {
-	Times     []*google_protobuf1.Timestamp `protobuf:"bytes,1,rep,name=times" json:"times,omitempty"`
+	Times     []*google_protobuf3.Timestamp `protobuf:"bytes,1,rep,name=times" json:"times,omitempty"`
 	Values    []float64                     `protobuf:"fixed64,2,rep,packed,name=values" json:"values,omitempty"`
 	Intervals []*Interval                   `protobuf:"bytes,3,rep,name=intervals" json:"intervals,omitempty"`
 }
@@ -199,9 +431,9 @@ type Forecast struct {
 func (m *Forecast) Reset()                    { *m = Forecast{} }
 func (m *Forecast) String() string            { return proto.CompactTextString(m) }
 func (*Forecast) ProtoMessage()               {}
-func (*Forecast) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Forecast) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
-func (m *Forecast) GetTimes() []*google_protobuf1.Timestamp {
+func (m *Forecast) GetTimes() []*google_protobuf3.Timestamp {
 	if m != nil {
 		return m.Times
 	}
@@ -222,6 +454,48 @@ func (m *Forecast) GetIntervals() []*Interval {
 	return nil
 }
 
+// A sampled forecast trajectory
+type Path struct {
+	Values []float64 `protobuf:"fixed64,1,rep,packed,name=values" json:"values,omitempty"`
+}
+
+func (m *Path) Reset()                    { *m = Path{} }
+func (m *Path) String() string            { return proto.CompactTextString(m) }
+func (*Path) ProtoMessage()               {}
+func (*Path) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *Path) GetValues() []float64 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// Sampled forecast trajectories, sharing a set of times
+type Samples struct {
+	Times []*google_protobuf3.Timestamp `protobuf:"bytes,1,rep,name=times" json:"times,omitempty"`
+	Paths []*Path                       `protobuf:"bytes,2,rep,name=paths" json:"paths,omitempty"`
+}
+
+func (m *Samples) Reset()                    { *m = Samples{} }
+func (m *Samples) String() string            { return proto.CompactTextString(m) }
+func (*Samples) ProtoMessage()               {}
+func (*Samples) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *Samples) GetTimes() []*google_protobuf3.Timestamp {
+	if m != nil {
+		return m.Times
+	}
+	return nil
+}
+
+func (m *Samples) GetPaths() []*Path {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
 // The request message containing the stream to be created
 type CreateStreamRequest struct {
 	Stream *Stream `protobuf:"bytes,1,opt,name=stream" json:"stream,omitempty"`
@@ -230,7 +504,7 @@ type CreateStreamRequest struct {
 func (m *CreateStreamRequest) Reset()                    { *m = CreateStreamRequest{} }
 func (m *CreateStreamRequest) String() string            { return proto.CompactTextString(m) }
 func (*CreateStreamRequest) ProtoMessage()               {}
-func (*CreateStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*CreateStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *CreateStreamRequest) GetStream() *Stream {
 	if m != nil {
@@ -247,7 +521,7 @@ type GetStreamRequest struct {
 func (m *GetStreamRequest) Reset()                    { *m = GetStreamRequest{} }
 func (m *GetStreamRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetStreamRequest) ProtoMessage()               {}
-func (*GetStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*GetStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *GetStreamRequest) GetName() string {
 	if m != nil {
@@ -264,7 +538,7 @@ type DeleteStreamRequest struct {
 func (m *DeleteStreamRequest) Reset()                    { *m = DeleteStreamRequest{} }
 func (m *DeleteStreamRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteStreamRequest) ProtoMessage()               {}
-func (*DeleteStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*DeleteStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *DeleteStreamRequest) GetName() string {
 	if m != nil {
@@ -273,16 +547,25 @@ func (m *DeleteStreamRequest) GetName() string {
 	return ""
 }
 
-// The request message containing the paging data for the stream to list
+// The request message containing the paging, filtering and ordering of the
+// streams to list
 type ListStreamsRequest struct {
-	PageSize   int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
-	PageNumber int32 `protobuf:"varint,2,opt,name=page_number,json=pageNumber" json:"page_number,omitempty"`
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
+	// Deprecated, use page_token, which does not rescan earlier pages
+	PageNumber int32     `protobuf:"varint,2,opt,name=page_number,json=pageNumber" json:"page_number,omitempty"`
+	PageToken  string    `protobuf:"bytes,3,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
+	Prefix     string    `protobuf:"bytes,4,opt,name=prefix" json:"prefix,omitempty"`
+	Order      ListOrder `protobuf:"varint,5,opt,name=order,enum=seer.ListOrder" json:"order,omitempty"`
+	Descending bool      `protobuf:"varint,6,opt,name=descending" json:"descending,omitempty"`
+	// Comma separated label requirements streams must meet, each of key=value,
+	// key!=value, key in (v1, v2), key notin (v1, v2), key or !key
+	LabelSelector string `protobuf:"bytes,7,opt,name=label_selector,json=labelSelector" json:"label_selector,omitempty"`
 }
 
 func (m *ListStreamsRequest) Reset()                    { *m = ListStreamsRequest{} }
 func (m *ListStreamsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListStreamsRequest) ProtoMessage()               {}
-func (*ListStreamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*ListStreamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *ListStreamsRequest) GetPageSize() int32 {
 	if m != nil {
@@ -298,15 +581,54 @@ func (m *ListStreamsRequest) GetPageNumber() int32 {
 	return 0
 }
 
-// The response message containing a list of streams
+func (m *ListStreamsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListStreamsRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListStreamsRequest) GetOrder() ListOrder {
+	if m != nil {
+		return m.Order
+	}
+	return ListOrder_BY_NAME
+}
+
+func (m *ListStreamsRequest) GetDescending() bool {
+	if m != nil {
+		return m.Descending
+	}
+	return false
+}
+
+func (m *ListStreamsRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+// The response message containing a page of streams, the token for the next
+// page, empty if there are no more streams, and the number of streams with the
+// requested prefix and labels
 type ListStreamsResponse struct {
-	Streams []*Stream `protobuf:"bytes,1,rep,name=streams" json:"streams,omitempty"`
+	Streams       []*Stream `protobuf:"bytes,1,rep,name=streams" json:"streams,omitempty"`
+	NextPageToken string    `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
+	TotalSize     int64     `protobuf:"varint,3,opt,name=total_size,json=totalSize" json:"total_size,omitempty"`
 }
 
 func (m *ListStreamsResponse) Reset()                    { *m = ListStreamsResponse{} }
 func (m *ListStreamsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListStreamsResponse) ProtoMessage()               {}
-func (*ListStreamsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*ListStreamsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *ListStreamsResponse) GetStreams() []*Stream {
 	if m != nil {
@@ -315,6 +637,20 @@ func (m *ListStreamsResponse) GetStreams() []*Stream {
 	return nil
 }
 
+func (m *ListStreamsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListStreamsResponse) GetTotalSize() int64 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
 // The request message containing events to apply to the stream
 type UpdateStreamRequest struct {
 	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
@@ -324,7 +660,7 @@ type UpdateStreamRequest struct {
 func (m *UpdateStreamRequest) Reset()                    { *m = UpdateStreamRequest{} }
 func (m *UpdateStreamRequest) String() string            { return proto.CompactTextString(m) }
 func (*UpdateStreamRequest) ProtoMessage()               {}
-func (*UpdateStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*UpdateStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *UpdateStreamRequest) GetName() string {
 	if m != nil {
@@ -340,16 +676,23 @@ func (m *UpdateStreamRequest) GetEvent() *Event {
 	return nil
 }
 
-// The request message containing the forecast length
+// The request message containing the forecast length, and optionally how to
+// aggregate the forecast over windows of periods, and when to start it. The
+// length may instead be given by an end time, which includes every whole
+// window ending at or before it
 type GetForecastRequest struct {
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	N    int32  `protobuf:"varint,2,opt,name=n" json:"n,omitempty"`
+	Name        string                      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	N           int32                       `protobuf:"varint,2,opt,name=n" json:"n,omitempty"`
+	Aggregation Aggregation                 `protobuf:"varint,3,opt,name=aggregation,enum=seer.Aggregation" json:"aggregation,omitempty"`
+	Window      int32                       `protobuf:"varint,4,opt,name=window" json:"window,omitempty"`
+	StartTime   *google_protobuf3.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
+	EndTime     *google_protobuf3.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime" json:"end_time,omitempty"`
 }
 
 func (m *GetForecastRequest) Reset()                    { *m = GetForecastRequest{} }
 func (m *GetForecastRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetForecastRequest) ProtoMessage()               {}
-func (*GetForecastRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*GetForecastRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *GetForecastRequest) GetName() string {
 	if m != nil {
@@ -365,221 +708,1945 @@ func (m *GetForecastRequest) GetN() int32 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*Stream)(nil), "seer.Stream")
-	proto.RegisterType((*Event)(nil), "seer.Event")
-	proto.RegisterType((*Interval)(nil), "seer.Interval")
-	proto.RegisterType((*Forecast)(nil), "seer.Forecast")
-	proto.RegisterType((*CreateStreamRequest)(nil), "seer.CreateStreamRequest")
-	proto.RegisterType((*GetStreamRequest)(nil), "seer.GetStreamRequest")
-	proto.RegisterType((*DeleteStreamRequest)(nil), "seer.DeleteStreamRequest")
-	proto.RegisterType((*ListStreamsRequest)(nil), "seer.ListStreamsRequest")
-	proto.RegisterType((*ListStreamsResponse)(nil), "seer.ListStreamsResponse")
-	proto.RegisterType((*UpdateStreamRequest)(nil), "seer.UpdateStreamRequest")
-	proto.RegisterType((*GetForecastRequest)(nil), "seer.GetForecastRequest")
-	proto.RegisterEnum("seer.Domain", Domain_name, Domain_value)
+func (m *GetForecastRequest) GetAggregation() Aggregation {
+	if m != nil {
+		return m.Aggregation
+	}
+	return Aggregation_NONE
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// Client API for Seer service
+func (m *GetForecastRequest) GetWindow() int32 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
 
-type SeerClient interface {
-	CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*Stream, error)
-	GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (*Stream, error)
-	UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*Stream, error)
-	DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
-	ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error)
-	GetForecast(ctx context.Context, in *GetForecastRequest, opts ...grpc.CallOption) (*Forecast, error)
+func (m *GetForecastRequest) GetStartTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
 }
 
-type seerClient struct {
-	cc *grpc.ClientConn
+func (m *GetForecastRequest) GetEndTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
 }
 
-func NewSeerClient(cc *grpc.ClientConn) SeerClient {
-	return &seerClient{cc}
+// The request message containing the history to refit the stream against, if
+// no event is given the stream's retained events are used
+type RefitStreamRequest struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Event *Event `protobuf:"bytes,2,opt,name=event" json:"event,omitempty"`
 }
 
-func (c *seerClient) CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
-	out := new(Stream)
-	err := grpc.Invoke(ctx, "/seer.Seer/CreateStream", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *RefitStreamRequest) Reset()                    { *m = RefitStreamRequest{} }
+func (m *RefitStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*RefitStreamRequest) ProtoMessage()               {}
+func (*RefitStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *RefitStreamRequest) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *seerClient) GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
-	out := new(Stream)
-	err := grpc.Invoke(ctx, "/seer.Seer/GetStream", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *RefitStreamRequest) GetEvent() *Event {
+	if m != nil {
+		return m.Event
 	}
-	return out, nil
+	return nil
 }
 
-func (c *seerClient) UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
-	out := new(Stream)
-	err := grpc.Invoke(ctx, "/seer.Seer/UpdateStream", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+// The request message containing the forecast length and number of paths
+type SampleForecastRequest struct {
+	Name     string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	N        int32  `protobuf:"varint,2,opt,name=n" json:"n,omitempty"`
+	NumPaths int32  `protobuf:"varint,3,opt,name=num_paths,json=numPaths" json:"num_paths,omitempty"`
+	Seed     int64  `protobuf:"varint,4,opt,name=seed" json:"seed,omitempty"`
 }
 
-func (c *seerClient) DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/seer.Seer/DeleteStream", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SampleForecastRequest) Reset()                    { *m = SampleForecastRequest{} }
+func (m *SampleForecastRequest) String() string            { return proto.CompactTextString(m) }
+func (*SampleForecastRequest) ProtoMessage()               {}
+func (*SampleForecastRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *SampleForecastRequest) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *seerClient) ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error) {
-	out := new(ListStreamsResponse)
-	err := grpc.Invoke(ctx, "/seer.Seer/ListStreams", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SampleForecastRequest) GetN() int32 {
+	if m != nil {
+		return m.N
 	}
-	return out, nil
+	return 0
 }
 
-func (c *seerClient) GetForecast(ctx context.Context, in *GetForecastRequest, opts ...grpc.CallOption) (*Forecast, error) {
-	out := new(Forecast)
-	err := grpc.Invoke(ctx, "/seer.Seer/GetForecast", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *SampleForecastRequest) GetNumPaths() int32 {
+	if m != nil {
+		return m.NumPaths
 	}
-	return out, nil
+	return 0
 }
 
-// Server API for Seer service
-
-type SeerServer interface {
-	CreateStream(context.Context, *CreateStreamRequest) (*Stream, error)
-	GetStream(context.Context, *GetStreamRequest) (*Stream, error)
-	UpdateStream(context.Context, *UpdateStreamRequest) (*Stream, error)
-	DeleteStream(context.Context, *DeleteStreamRequest) (*google_protobuf.Empty, error)
-	ListStreams(context.Context, *ListStreamsRequest) (*ListStreamsResponse, error)
-	GetForecast(context.Context, *GetForecastRequest) (*Forecast, error)
+func (m *SampleForecastRequest) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
 }
 
-func RegisterSeerServer(s *grpc.Server, srv SeerServer) {
-	s.RegisterService(&_Seer_serviceDesc, srv)
+// The request message containing the time range and paging data for the
+// events to get, the page token overrides the start time
+type GetEventsRequest struct {
+	Name      string                      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	StartTime *google_protobuf3.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
+	EndTime   *google_protobuf3.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime" json:"end_time,omitempty"`
+	PageSize  int32                       `protobuf:"varint,4,opt,name=page_size,json=pageSize" json:"page_size,omitempty"`
+	PageToken string                      `protobuf:"bytes,5,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
 }
 
-func _Seer_CreateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func (m *GetEventsRequest) Reset()                    { *m = GetEventsRequest{} }
+func (m *GetEventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetEventsRequest) ProtoMessage()               {}
+func (*GetEventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *GetEventsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetEventsRequest) GetStartTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *GetEventsRequest) GetEndTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *GetEventsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *GetEventsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// The response message containing a page of events, and the token for the
+// next page, empty if there are no more events
+type GetEventsResponse struct {
+	Event         *Event `protobuf:"bytes,1,opt,name=event" json:"event,omitempty"`
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
+}
+
+func (m *GetEventsResponse) Reset()                    { *m = GetEventsResponse{} }
+func (m *GetEventsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetEventsResponse) ProtoMessage()               {}
+func (*GetEventsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *GetEventsResponse) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *GetEventsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// The request message containing the stream to rebuild from its retained
+// events, and optionally a new configuration for it, with the same name
+type RebuildStreamRequest struct {
+	Name   string  `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Stream *Stream `protobuf:"bytes,2,opt,name=stream" json:"stream,omitempty"`
+}
+
+func (m *RebuildStreamRequest) Reset()                    { *m = RebuildStreamRequest{} }
+func (m *RebuildStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*RebuildStreamRequest) ProtoMessage()               {}
+func (*RebuildStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *RebuildStreamRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RebuildStreamRequest) GetStream() *Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
+// The progress of a rebuild, the final message contains the rebuilt stream
+type RebuildProgress struct {
+	EventsReplayed int64   `protobuf:"varint,1,opt,name=events_replayed,json=eventsReplayed" json:"events_replayed,omitempty"`
+	EventsTotal    int64   `protobuf:"varint,2,opt,name=events_total,json=eventsTotal" json:"events_total,omitempty"`
+	Stream         *Stream `protobuf:"bytes,3,opt,name=stream" json:"stream,omitempty"`
+}
+
+func (m *RebuildProgress) Reset()                    { *m = RebuildProgress{} }
+func (m *RebuildProgress) String() string            { return proto.CompactTextString(m) }
+func (*RebuildProgress) ProtoMessage()               {}
+func (*RebuildProgress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *RebuildProgress) GetEventsReplayed() int64 {
+	if m != nil {
+		return m.EventsReplayed
+	}
+	return 0
+}
+
+func (m *RebuildProgress) GetEventsTotal() int64 {
+	if m != nil {
+		return m.EventsTotal
+	}
+	return 0
+}
+
+func (m *RebuildProgress) GetStream() *Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
+// The request message containing the stream to roll back, and the time to roll
+// it back to
+type RollbackStreamRequest struct {
+	Name string                      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Time *google_protobuf3.Timestamp `protobuf:"bytes,2,opt,name=time" json:"time,omitempty"`
+}
+
+func (m *RollbackStreamRequest) Reset()                    { *m = RollbackStreamRequest{} }
+func (m *RollbackStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*RollbackStreamRequest) ProtoMessage()               {}
+func (*RollbackStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *RollbackStreamRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RollbackStreamRequest) GetTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+// A chunk of a backup of every stream in the store, with their retained
+// events and snapshots
+type BackupChunk struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data" json:"data,omitempty"`
+}
+
+func (m *BackupChunk) Reset()                    { *m = BackupChunk{} }
+func (m *BackupChunk) String() string            { return proto.CompactTextString(m) }
+func (*BackupChunk) ProtoMessage()               {}
+func (*BackupChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *BackupChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// The response message containing the number of streams restored
+type RestoreResponse struct {
+	Streams int64 `protobuf:"varint,1,opt,name=streams" json:"streams,omitempty"`
+}
+
+func (m *RestoreResponse) Reset()                    { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()               {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *RestoreResponse) GetStreams() int64 {
+	if m != nil {
+		return m.Streams
+	}
+	return 0
+}
+
+// The request message containing the export format, and whether to include
+// retained events
+type ExportStreamsRequest struct {
+	Format  ExportFormat `protobuf:"varint,1,opt,name=format,enum=seer.ExportFormat" json:"format,omitempty"`
+	History bool         `protobuf:"varint,2,opt,name=history" json:"history,omitempty"`
+}
+
+func (m *ExportStreamsRequest) Reset()                    { *m = ExportStreamsRequest{} }
+func (m *ExportStreamsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportStreamsRequest) ProtoMessage()               {}
+func (*ExportStreamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *ExportStreamsRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_JSON
+}
+
+func (m *ExportStreamsRequest) GetHistory() bool {
+	if m != nil {
+		return m.History
+	}
+	return false
+}
+
+// A chunk of exported streams
+type ExportChunk struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data" json:"data,omitempty"`
+}
+
+func (m *ExportChunk) Reset()                    { *m = ExportChunk{} }
+func (m *ExportChunk) String() string            { return proto.CompactTextString(m) }
+func (*ExportChunk) ProtoMessage()               {}
+func (*ExportChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *ExportChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// The request message containing a chunk of streams to import, the format is
+// read from the first chunk
+type ImportStreamsRequest struct {
+	Format ExportFormat `protobuf:"varint,1,opt,name=format,enum=seer.ExportFormat" json:"format,omitempty"`
+	Data   []byte       `protobuf:"bytes,2,opt,name=data" json:"data,omitempty"`
+}
+
+func (m *ImportStreamsRequest) Reset()                    { *m = ImportStreamsRequest{} }
+func (m *ImportStreamsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportStreamsRequest) ProtoMessage()               {}
+func (*ImportStreamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *ImportStreamsRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_JSON
+}
+
+func (m *ImportStreamsRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// The response message containing the number of streams imported
+type ImportStreamsResponse struct {
+	Streams int64 `protobuf:"varint,1,opt,name=streams" json:"streams,omitempty"`
+}
+
+func (m *ImportStreamsResponse) Reset()                    { *m = ImportStreamsResponse{} }
+func (m *ImportStreamsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportStreamsResponse) ProtoMessage()               {}
+func (*ImportStreamsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *ImportStreamsResponse) GetStreams() int64 {
+	if m != nil {
+		return m.Streams
+	}
+	return 0
+}
+
+// An isolated set of streams, with quotas on the number of streams and the
+// points per second sent to them, where zero is unlimited. Stream requests are
+// made in a namespace by setting the seer-namespace metadata key, and without
+// it in the default namespace, which sees the streams of every namespace by
+// their full names, namespace/name
+type Namespace struct {
+	Name               string  `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	MaxStreams         int64   `protobuf:"varint,2,opt,name=max_streams,json=maxStreams" json:"max_streams,omitempty"`
+	MaxPointsPerSecond float64 `protobuf:"fixed64,3,opt,name=max_points_per_second,json=maxPointsPerSecond" json:"max_points_per_second,omitempty"`
+}
+
+func (m *Namespace) Reset()                    { *m = Namespace{} }
+func (m *Namespace) String() string            { return proto.CompactTextString(m) }
+func (*Namespace) ProtoMessage()               {}
+func (*Namespace) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *Namespace) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Namespace) GetMaxStreams() int64 {
+	if m != nil {
+		return m.MaxStreams
+	}
+	return 0
+}
+
+func (m *Namespace) GetMaxPointsPerSecond() float64 {
+	if m != nil {
+		return m.MaxPointsPerSecond
+	}
+	return 0
+}
+
+// The request message containing the namespace to create
+type CreateNamespaceRequest struct {
+	Namespace *Namespace `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+}
+
+func (m *CreateNamespaceRequest) Reset()                    { *m = CreateNamespaceRequest{} }
+func (m *CreateNamespaceRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateNamespaceRequest) ProtoMessage()               {}
+func (*CreateNamespaceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *CreateNamespaceRequest) GetNamespace() *Namespace {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+// The request message containing the name of the namespace to get
+type GetNamespaceRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *GetNamespaceRequest) Reset()                    { *m = GetNamespaceRequest{} }
+func (m *GetNamespaceRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetNamespaceRequest) ProtoMessage()               {}
+func (*GetNamespaceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *GetNamespaceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// The request message containing the namespace's new quotas
+type UpdateNamespaceRequest struct {
+	Namespace *Namespace `protobuf:"bytes,1,opt,name=namespace" json:"namespace,omitempty"`
+}
+
+func (m *UpdateNamespaceRequest) Reset()                    { *m = UpdateNamespaceRequest{} }
+func (m *UpdateNamespaceRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateNamespaceRequest) ProtoMessage()               {}
+func (*UpdateNamespaceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *UpdateNamespaceRequest) GetNamespace() *Namespace {
+	if m != nil {
+		return m.Namespace
+	}
+	return nil
+}
+
+// The request message containing the name of the namespace to delete, which
+// must have no streams
+type DeleteNamespaceRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *DeleteNamespaceRequest) Reset()                    { *m = DeleteNamespaceRequest{} }
+func (m *DeleteNamespaceRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteNamespaceRequest) ProtoMessage()               {}
+func (*DeleteNamespaceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *DeleteNamespaceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// The response message containing every namespace
+type ListNamespacesResponse struct {
+	Namespaces []*Namespace `protobuf:"bytes,1,rep,name=namespaces" json:"namespaces,omitempty"`
+}
+
+func (m *ListNamespacesResponse) Reset()                    { *m = ListNamespacesResponse{} }
+func (m *ListNamespacesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListNamespacesResponse) ProtoMessage()               {}
+func (*ListNamespacesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *ListNamespacesResponse) GetNamespaces() []*Namespace {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+// The request message containing the stream to patch, and the fields of
+// stream to apply to it. Paths are name, period, min, max, domain, retention,
+// snapshots, labels and ttl, where labels replaces every label, and name moves
+// the stream as RenameStream does.
+type PatchStreamRequest struct {
+	Name       string                      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Stream     *Stream                     `protobuf:"bytes,2,opt,name=stream" json:"stream,omitempty"`
+	UpdateMask *google_protobuf2.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask" json:"update_mask,omitempty"`
+}
+
+func (m *PatchStreamRequest) Reset()                    { *m = PatchStreamRequest{} }
+func (m *PatchStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*PatchStreamRequest) ProtoMessage()               {}
+func (*PatchStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *PatchStreamRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PatchStreamRequest) GetStream() *Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
+func (m *PatchStreamRequest) GetUpdateMask() *google_protobuf2.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+// The response message containing the patched stream, and how its model was
+// changed
+type PatchStreamResponse struct {
+	Stream    *Stream   `protobuf:"bytes,1,opt,name=stream" json:"stream,omitempty"`
+	Migration Migration `protobuf:"varint,2,opt,name=migration,enum=seer.Migration" json:"migration,omitempty"`
+}
+
+func (m *PatchStreamResponse) Reset()                    { *m = PatchStreamResponse{} }
+func (m *PatchStreamResponse) String() string            { return proto.CompactTextString(m) }
+func (*PatchStreamResponse) ProtoMessage()               {}
+func (*PatchStreamResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *PatchStreamResponse) GetStream() *Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
+func (m *PatchStreamResponse) GetMigration() Migration {
+	if m != nil {
+		return m.Migration
+	}
+	return Migration_IN_PLACE
+}
+
+// The request message containing the stream to rename, and its new name. Its
+// events and snapshots move with it
+type RenameStreamRequest struct {
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	NewName string `protobuf:"bytes,2,opt,name=new_name,json=newName" json:"new_name,omitempty"`
+}
+
+func (m *RenameStreamRequest) Reset()                    { *m = RenameStreamRequest{} }
+func (m *RenameStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*RenameStreamRequest) ProtoMessage()               {}
+func (*RenameStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *RenameStreamRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RenameStreamRequest) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
+// The request message containing the stream to clone, and the name of the
+// clone, which starts with the source's model state, events and snapshots, and
+// is then independent of it
+type CloneStreamRequest struct {
+	Source      string `protobuf:"bytes,1,opt,name=source" json:"source,omitempty"`
+	Destination string `protobuf:"bytes,2,opt,name=destination" json:"destination,omitempty"`
+}
+
+func (m *CloneStreamRequest) Reset()                    { *m = CloneStreamRequest{} }
+func (m *CloneStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*CloneStreamRequest) ProtoMessage()               {}
+func (*CloneStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *CloneStreamRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *CloneStreamRequest) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+// The request message containing the prefix of the streams to check for
+// expiry, and the time to check at, now if unset
+type ListExpiredStreamsRequest struct {
+	Prefix string                      `protobuf:"bytes,1,opt,name=prefix" json:"prefix,omitempty"`
+	AsOf   *google_protobuf3.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf" json:"as_of,omitempty"`
+}
+
+func (m *ListExpiredStreamsRequest) Reset()                    { *m = ListExpiredStreamsRequest{} }
+func (m *ListExpiredStreamsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListExpiredStreamsRequest) ProtoMessage()               {}
+func (*ListExpiredStreamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *ListExpiredStreamsRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListExpiredStreamsRequest) GetAsOf() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.AsOf
+	}
+	return nil
+}
+
+// The response message containing the streams the janitor would delete if it
+// swept at the requested time, in name order
+type ListExpiredStreamsResponse struct {
+	Streams []*Stream `protobuf:"bytes,1,rep,name=streams" json:"streams,omitempty"`
+}
+
+func (m *ListExpiredStreamsResponse) Reset()                    { *m = ListExpiredStreamsResponse{} }
+func (m *ListExpiredStreamsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListExpiredStreamsResponse) ProtoMessage()               {}
+func (*ListExpiredStreamsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *ListExpiredStreamsResponse) GetStreams() []*Stream {
+	if m != nil {
+		return m.Streams
+	}
+	return nil
+}
+
+// The request message containing the streams to forecast, either by name or
+// by label selector, and the forecast to generate for each, as in
+// GetForecastRequest, with interval probabilities of 0.8, 0.9 and 0.95 if
+// none are given
+type BatchGetForecastRequest struct {
+	Names         []string    `protobuf:"bytes,1,rep,name=names" json:"names,omitempty"`
+	LabelSelector string      `protobuf:"bytes,2,opt,name=label_selector,json=labelSelector" json:"label_selector,omitempty"`
+	N             int32       `protobuf:"varint,3,opt,name=n" json:"n,omitempty"`
+	Aggregation   Aggregation `protobuf:"varint,4,opt,name=aggregation,enum=seer.Aggregation" json:"aggregation,omitempty"`
+	Window        int32       `protobuf:"varint,5,opt,name=window" json:"window,omitempty"`
+	Probabilities []float64   `protobuf:"fixed64,6,rep,packed,name=probabilities" json:"probabilities,omitempty"`
+}
+
+func (m *BatchGetForecastRequest) Reset()                    { *m = BatchGetForecastRequest{} }
+func (m *BatchGetForecastRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchGetForecastRequest) ProtoMessage()               {}
+func (*BatchGetForecastRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *BatchGetForecastRequest) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *BatchGetForecastRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *BatchGetForecastRequest) GetN() int32 {
+	if m != nil {
+		return m.N
+	}
+	return 0
+}
+
+func (m *BatchGetForecastRequest) GetAggregation() Aggregation {
+	if m != nil {
+		return m.Aggregation
+	}
+	return Aggregation_NONE
+}
+
+func (m *BatchGetForecastRequest) GetWindow() int32 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
+
+func (m *BatchGetForecastRequest) GetProbabilities() []float64 {
+	if m != nil {
+		return m.Probabilities
+	}
+	return nil
+}
+
+// The response message containing a result for each requested name, in order,
+// or for each stream matching the label selector, in name order
+type BatchGetForecastResponse struct {
+	Results []*StreamForecast `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *BatchGetForecastResponse) Reset()                    { *m = BatchGetForecastResponse{} }
+func (m *BatchGetForecastResponse) String() string            { return proto.CompactTextString(m) }
+func (*BatchGetForecastResponse) ProtoMessage()               {}
+func (*BatchGetForecastResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *BatchGetForecastResponse) GetResults() []*StreamForecast {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// The forecast of one stream in a batch, or the error that prevented it
+type StreamForecast struct {
+	Name     string      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Forecast *Forecast   `protobuf:"bytes,2,opt,name=forecast" json:"forecast,omitempty"`
+	Error    *BatchError `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *StreamForecast) Reset()                    { *m = StreamForecast{} }
+func (m *StreamForecast) String() string            { return proto.CompactTextString(m) }
+func (*StreamForecast) ProtoMessage()               {}
+func (*StreamForecast) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *StreamForecast) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StreamForecast) GetForecast() *Forecast {
+	if m != nil {
+		return m.Forecast
+	}
+	return nil
+}
+
+func (m *StreamForecast) GetError() *BatchError {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// The grpc status code and message of the error for one stream in a batch
+type BatchError struct {
+	Code    int32  `protobuf:"varint,1,opt,name=code" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *BatchError) Reset()                    { *m = BatchError{} }
+func (m *BatchError) String() string            { return proto.CompactTextString(m) }
+func (*BatchError) ProtoMessage()               {}
+func (*BatchError) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *BatchError) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *BatchError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// The request message containing the events to apply to many streams, each as
+// in UpdateStreamRequest, and each to a different stream
+type BatchUpdateStreamsRequest struct {
+	Updates []*UpdateStreamRequest `protobuf:"bytes,1,rep,name=updates" json:"updates,omitempty"`
+}
+
+func (m *BatchUpdateStreamsRequest) Reset()                    { *m = BatchUpdateStreamsRequest{} }
+func (m *BatchUpdateStreamsRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchUpdateStreamsRequest) ProtoMessage()               {}
+func (*BatchUpdateStreamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *BatchUpdateStreamsRequest) GetUpdates() []*UpdateStreamRequest {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+// The response message containing a result for each update, in order
+type BatchUpdateStreamsResponse struct {
+	Results []*StreamUpdate `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *BatchUpdateStreamsResponse) Reset()                    { *m = BatchUpdateStreamsResponse{} }
+func (m *BatchUpdateStreamsResponse) String() string            { return proto.CompactTextString(m) }
+func (*BatchUpdateStreamsResponse) ProtoMessage()               {}
+func (*BatchUpdateStreamsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *BatchUpdateStreamsResponse) GetResults() []*StreamUpdate {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// The updated stream in a batch, or the error that prevented its update
+type StreamUpdate struct {
+	Name   string      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Stream *Stream     `protobuf:"bytes,2,opt,name=stream" json:"stream,omitempty"`
+	Error  *BatchError `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *StreamUpdate) Reset()                    { *m = StreamUpdate{} }
+func (m *StreamUpdate) String() string            { return proto.CompactTextString(m) }
+func (*StreamUpdate) ProtoMessage()               {}
+func (*StreamUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *StreamUpdate) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StreamUpdate) GetStream() *Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return nil
+}
+
+func (m *StreamUpdate) GetError() *BatchError {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// The request message containing hypothetical events to apply to a copy of
+// the stream, and the forecast to make from it, as in GetForecastRequest. The
+// stream itself is left unchanged
+type ScenarioForecastRequest struct {
+	Name        string                      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Event       *Event                      `protobuf:"bytes,2,opt,name=event" json:"event,omitempty"`
+	N           int32                       `protobuf:"varint,3,opt,name=n" json:"n,omitempty"`
+	Aggregation Aggregation                 `protobuf:"varint,4,opt,name=aggregation,enum=seer.Aggregation" json:"aggregation,omitempty"`
+	Window      int32                       `protobuf:"varint,5,opt,name=window" json:"window,omitempty"`
+	StartTime   *google_protobuf3.Timestamp `protobuf:"bytes,6,opt,name=start_time,json=startTime" json:"start_time,omitempty"`
+	EndTime     *google_protobuf3.Timestamp `protobuf:"bytes,7,opt,name=end_time,json=endTime" json:"end_time,omitempty"`
+}
+
+func (m *ScenarioForecastRequest) Reset()                    { *m = ScenarioForecastRequest{} }
+func (m *ScenarioForecastRequest) String() string            { return proto.CompactTextString(m) }
+func (*ScenarioForecastRequest) ProtoMessage()               {}
+func (*ScenarioForecastRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *ScenarioForecastRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ScenarioForecastRequest) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *ScenarioForecastRequest) GetN() int32 {
+	if m != nil {
+		return m.N
+	}
+	return 0
+}
+
+func (m *ScenarioForecastRequest) GetAggregation() Aggregation {
+	if m != nil {
+		return m.Aggregation
+	}
+	return Aggregation_NONE
+}
+
+func (m *ScenarioForecastRequest) GetWindow() int32 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
+
+func (m *ScenarioForecastRequest) GetStartTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *ScenarioForecastRequest) GetEndTime() *google_protobuf3.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Stream)(nil), "seer.Stream")
+	proto.RegisterType((*Retention)(nil), "seer.Retention")
+	proto.RegisterType((*SnapshotPolicy)(nil), "seer.SnapshotPolicy")
+	proto.RegisterType((*Event)(nil), "seer.Event")
+	proto.RegisterType((*Interval)(nil), "seer.Interval")
+	proto.RegisterType((*Forecast)(nil), "seer.Forecast")
+	proto.RegisterType((*Path)(nil), "seer.Path")
+	proto.RegisterType((*Samples)(nil), "seer.Samples")
+	proto.RegisterType((*CreateStreamRequest)(nil), "seer.CreateStreamRequest")
+	proto.RegisterType((*GetStreamRequest)(nil), "seer.GetStreamRequest")
+	proto.RegisterType((*DeleteStreamRequest)(nil), "seer.DeleteStreamRequest")
+	proto.RegisterType((*ListStreamsRequest)(nil), "seer.ListStreamsRequest")
+	proto.RegisterType((*ListStreamsResponse)(nil), "seer.ListStreamsResponse")
+	proto.RegisterType((*UpdateStreamRequest)(nil), "seer.UpdateStreamRequest")
+	proto.RegisterType((*GetForecastRequest)(nil), "seer.GetForecastRequest")
+	proto.RegisterType((*RefitStreamRequest)(nil), "seer.RefitStreamRequest")
+	proto.RegisterType((*SampleForecastRequest)(nil), "seer.SampleForecastRequest")
+	proto.RegisterType((*GetEventsRequest)(nil), "seer.GetEventsRequest")
+	proto.RegisterType((*GetEventsResponse)(nil), "seer.GetEventsResponse")
+	proto.RegisterType((*RebuildStreamRequest)(nil), "seer.RebuildStreamRequest")
+	proto.RegisterType((*RebuildProgress)(nil), "seer.RebuildProgress")
+	proto.RegisterType((*RollbackStreamRequest)(nil), "seer.RollbackStreamRequest")
+	proto.RegisterType((*BackupChunk)(nil), "seer.BackupChunk")
+	proto.RegisterType((*RestoreResponse)(nil), "seer.RestoreResponse")
+	proto.RegisterType((*ExportStreamsRequest)(nil), "seer.ExportStreamsRequest")
+	proto.RegisterType((*ExportChunk)(nil), "seer.ExportChunk")
+	proto.RegisterType((*ImportStreamsRequest)(nil), "seer.ImportStreamsRequest")
+	proto.RegisterType((*ImportStreamsResponse)(nil), "seer.ImportStreamsResponse")
+	proto.RegisterType((*Namespace)(nil), "seer.Namespace")
+	proto.RegisterType((*CreateNamespaceRequest)(nil), "seer.CreateNamespaceRequest")
+	proto.RegisterType((*GetNamespaceRequest)(nil), "seer.GetNamespaceRequest")
+	proto.RegisterType((*UpdateNamespaceRequest)(nil), "seer.UpdateNamespaceRequest")
+	proto.RegisterType((*DeleteNamespaceRequest)(nil), "seer.DeleteNamespaceRequest")
+	proto.RegisterType((*ListNamespacesResponse)(nil), "seer.ListNamespacesResponse")
+	proto.RegisterType((*PatchStreamRequest)(nil), "seer.PatchStreamRequest")
+	proto.RegisterType((*PatchStreamResponse)(nil), "seer.PatchStreamResponse")
+	proto.RegisterType((*RenameStreamRequest)(nil), "seer.RenameStreamRequest")
+	proto.RegisterType((*CloneStreamRequest)(nil), "seer.CloneStreamRequest")
+	proto.RegisterType((*ListExpiredStreamsRequest)(nil), "seer.ListExpiredStreamsRequest")
+	proto.RegisterType((*ListExpiredStreamsResponse)(nil), "seer.ListExpiredStreamsResponse")
+	proto.RegisterType((*BatchGetForecastRequest)(nil), "seer.BatchGetForecastRequest")
+	proto.RegisterType((*BatchGetForecastResponse)(nil), "seer.BatchGetForecastResponse")
+	proto.RegisterType((*StreamForecast)(nil), "seer.StreamForecast")
+	proto.RegisterType((*BatchError)(nil), "seer.BatchError")
+	proto.RegisterType((*BatchUpdateStreamsRequest)(nil), "seer.BatchUpdateStreamsRequest")
+	proto.RegisterType((*BatchUpdateStreamsResponse)(nil), "seer.BatchUpdateStreamsResponse")
+	proto.RegisterType((*StreamUpdate)(nil), "seer.StreamUpdate")
+	proto.RegisterType((*ScenarioForecastRequest)(nil), "seer.ScenarioForecastRequest")
+	proto.RegisterEnum("seer.Domain", Domain_name, Domain_value)
+	proto.RegisterEnum("seer.Aggregation", Aggregation_name, Aggregation_value)
+	proto.RegisterEnum("seer.ListOrder", ListOrder_name, ListOrder_value)
+	proto.RegisterEnum("seer.ExportFormat", ExportFormat_name, ExportFormat_value)
+	proto.RegisterEnum("seer.Migration", Migration_name, Migration_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Seer service
+
+type SeerClient interface {
+	CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
+	ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error)
+	GetForecast(ctx context.Context, in *GetForecastRequest, opts ...grpc.CallOption) (*Forecast, error)
+	RefitStream(ctx context.Context, in *RefitStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	SampleForecast(ctx context.Context, in *SampleForecastRequest, opts ...grpc.CallOption) (*Samples, error)
+	GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (*GetEventsResponse, error)
+	RebuildStream(ctx context.Context, in *RebuildStreamRequest, opts ...grpc.CallOption) (Seer_RebuildStreamClient, error)
+	RollbackStream(ctx context.Context, in *RollbackStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	Backup(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (Seer_BackupClient, error)
+	Restore(ctx context.Context, opts ...grpc.CallOption) (Seer_RestoreClient, error)
+	ExportStreams(ctx context.Context, in *ExportStreamsRequest, opts ...grpc.CallOption) (Seer_ExportStreamsClient, error)
+	ImportStreams(ctx context.Context, opts ...grpc.CallOption) (Seer_ImportStreamsClient, error)
+	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error)
+	GetNamespace(ctx context.Context, in *GetNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error)
+	UpdateNamespace(ctx context.Context, in *UpdateNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error)
+	DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
+	ListNamespaces(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
+	PatchStream(ctx context.Context, in *PatchStreamRequest, opts ...grpc.CallOption) (*PatchStreamResponse, error)
+	RenameStream(ctx context.Context, in *RenameStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	CloneStream(ctx context.Context, in *CloneStreamRequest, opts ...grpc.CallOption) (*Stream, error)
+	ListExpiredStreams(ctx context.Context, in *ListExpiredStreamsRequest, opts ...grpc.CallOption) (*ListExpiredStreamsResponse, error)
+	BatchGetForecast(ctx context.Context, in *BatchGetForecastRequest, opts ...grpc.CallOption) (*BatchGetForecastResponse, error)
+	BatchUpdateStreams(ctx context.Context, in *BatchUpdateStreamsRequest, opts ...grpc.CallOption) (*BatchUpdateStreamsResponse, error)
+	ScenarioForecast(ctx context.Context, in *ScenarioForecastRequest, opts ...grpc.CallOption) (*Forecast, error)
+}
+
+type seerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSeerClient(cc *grpc.ClientConn) SeerClient {
+	return &seerClient{cc}
+}
+
+func (c *seerClient) CreateStream(ctx context.Context, in *CreateStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/CreateStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) GetStream(ctx context.Context, in *GetStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/GetStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/UpdateStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) DeleteStream(ctx context.Context, in *DeleteStreamRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc.Invoke(ctx, "/seer.Seer/DeleteStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error) {
+	out := new(ListStreamsResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/ListStreams", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) GetForecast(ctx context.Context, in *GetForecastRequest, opts ...grpc.CallOption) (*Forecast, error) {
+	out := new(Forecast)
+	err := grpc.Invoke(ctx, "/seer.Seer/GetForecast", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) RefitStream(ctx context.Context, in *RefitStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/RefitStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) SampleForecast(ctx context.Context, in *SampleForecastRequest, opts ...grpc.CallOption) (*Samples, error) {
+	out := new(Samples)
+	err := grpc.Invoke(ctx, "/seer.Seer/SampleForecast", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (*GetEventsResponse, error) {
+	out := new(GetEventsResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/GetEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) RebuildStream(ctx context.Context, in *RebuildStreamRequest, opts ...grpc.CallOption) (Seer_RebuildStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Seer_serviceDesc.Streams[0], c.cc, "/seer.Seer/RebuildStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seerRebuildStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Seer_RebuildStreamClient interface {
+	Recv() (*RebuildProgress, error)
+	grpc.ClientStream
+}
+
+type seerRebuildStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *seerRebuildStreamClient) Recv() (*RebuildProgress, error) {
+	m := new(RebuildProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *seerClient) RollbackStream(ctx context.Context, in *RollbackStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/RollbackStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) Backup(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (Seer_BackupClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Seer_serviceDesc.Streams[1], c.cc, "/seer.Seer/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seerBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Seer_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type seerBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *seerBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *seerClient) Restore(ctx context.Context, opts ...grpc.CallOption) (Seer_RestoreClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Seer_serviceDesc.Streams[2], c.cc, "/seer.Seer/Restore", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seerRestoreClient{stream}
+	return x, nil
+}
+
+type Seer_RestoreClient interface {
+	Send(*BackupChunk) error
+	CloseAndRecv() (*RestoreResponse, error)
+	grpc.ClientStream
+}
+
+type seerRestoreClient struct {
+	grpc.ClientStream
+}
+
+func (x *seerRestoreClient) Send(m *BackupChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *seerRestoreClient) CloseAndRecv() (*RestoreResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RestoreResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *seerClient) ExportStreams(ctx context.Context, in *ExportStreamsRequest, opts ...grpc.CallOption) (Seer_ExportStreamsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Seer_serviceDesc.Streams[3], c.cc, "/seer.Seer/ExportStreams", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seerExportStreamsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Seer_ExportStreamsClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type seerExportStreamsClient struct {
+	grpc.ClientStream
+}
+
+func (x *seerExportStreamsClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *seerClient) ImportStreams(ctx context.Context, opts ...grpc.CallOption) (Seer_ImportStreamsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Seer_serviceDesc.Streams[4], c.cc, "/seer.Seer/ImportStreams", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seerImportStreamsClient{stream}
+	return x, nil
+}
+
+type Seer_ImportStreamsClient interface {
+	Send(*ImportStreamsRequest) error
+	CloseAndRecv() (*ImportStreamsResponse, error)
+	grpc.ClientStream
+}
+
+type seerImportStreamsClient struct {
+	grpc.ClientStream
+}
+
+func (x *seerImportStreamsClient) Send(m *ImportStreamsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *seerImportStreamsClient) CloseAndRecv() (*ImportStreamsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportStreamsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *seerClient) CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error) {
+	out := new(Namespace)
+	err := grpc.Invoke(ctx, "/seer.Seer/CreateNamespace", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) GetNamespace(ctx context.Context, in *GetNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error) {
+	out := new(Namespace)
+	err := grpc.Invoke(ctx, "/seer.Seer/GetNamespace", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) UpdateNamespace(ctx context.Context, in *UpdateNamespaceRequest, opts ...grpc.CallOption) (*Namespace, error) {
+	out := new(Namespace)
+	err := grpc.Invoke(ctx, "/seer.Seer/UpdateNamespace", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc.Invoke(ctx, "/seer.Seer/DeleteNamespace", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) ListNamespaces(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*ListNamespacesResponse, error) {
+	out := new(ListNamespacesResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/ListNamespaces", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) PatchStream(ctx context.Context, in *PatchStreamRequest, opts ...grpc.CallOption) (*PatchStreamResponse, error) {
+	out := new(PatchStreamResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/PatchStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) RenameStream(ctx context.Context, in *RenameStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/RenameStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) CloneStream(ctx context.Context, in *CloneStreamRequest, opts ...grpc.CallOption) (*Stream, error) {
+	out := new(Stream)
+	err := grpc.Invoke(ctx, "/seer.Seer/CloneStream", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) ListExpiredStreams(ctx context.Context, in *ListExpiredStreamsRequest, opts ...grpc.CallOption) (*ListExpiredStreamsResponse, error) {
+	out := new(ListExpiredStreamsResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/ListExpiredStreams", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) BatchGetForecast(ctx context.Context, in *BatchGetForecastRequest, opts ...grpc.CallOption) (*BatchGetForecastResponse, error) {
+	out := new(BatchGetForecastResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/BatchGetForecast", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) BatchUpdateStreams(ctx context.Context, in *BatchUpdateStreamsRequest, opts ...grpc.CallOption) (*BatchUpdateStreamsResponse, error) {
+	out := new(BatchUpdateStreamsResponse)
+	err := grpc.Invoke(ctx, "/seer.Seer/BatchUpdateStreams", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seerClient) ScenarioForecast(ctx context.Context, in *ScenarioForecastRequest, opts ...grpc.CallOption) (*Forecast, error) {
+	out := new(Forecast)
+	err := grpc.Invoke(ctx, "/seer.Seer/ScenarioForecast", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Seer service
+
+type SeerServer interface {
+	CreateStream(context.Context, *CreateStreamRequest) (*Stream, error)
+	GetStream(context.Context, *GetStreamRequest) (*Stream, error)
+	UpdateStream(context.Context, *UpdateStreamRequest) (*Stream, error)
+	DeleteStream(context.Context, *DeleteStreamRequest) (*google_protobuf1.Empty, error)
+	ListStreams(context.Context, *ListStreamsRequest) (*ListStreamsResponse, error)
+	GetForecast(context.Context, *GetForecastRequest) (*Forecast, error)
+	RefitStream(context.Context, *RefitStreamRequest) (*Stream, error)
+	SampleForecast(context.Context, *SampleForecastRequest) (*Samples, error)
+	GetEvents(context.Context, *GetEventsRequest) (*GetEventsResponse, error)
+	RebuildStream(*RebuildStreamRequest, Seer_RebuildStreamServer) error
+	RollbackStream(context.Context, *RollbackStreamRequest) (*Stream, error)
+	Backup(*google_protobuf1.Empty, Seer_BackupServer) error
+	Restore(Seer_RestoreServer) error
+	ExportStreams(*ExportStreamsRequest, Seer_ExportStreamsServer) error
+	ImportStreams(Seer_ImportStreamsServer) error
+	CreateNamespace(context.Context, *CreateNamespaceRequest) (*Namespace, error)
+	GetNamespace(context.Context, *GetNamespaceRequest) (*Namespace, error)
+	UpdateNamespace(context.Context, *UpdateNamespaceRequest) (*Namespace, error)
+	DeleteNamespace(context.Context, *DeleteNamespaceRequest) (*google_protobuf1.Empty, error)
+	ListNamespaces(context.Context, *google_protobuf1.Empty) (*ListNamespacesResponse, error)
+	PatchStream(context.Context, *PatchStreamRequest) (*PatchStreamResponse, error)
+	RenameStream(context.Context, *RenameStreamRequest) (*Stream, error)
+	CloneStream(context.Context, *CloneStreamRequest) (*Stream, error)
+	ListExpiredStreams(context.Context, *ListExpiredStreamsRequest) (*ListExpiredStreamsResponse, error)
+	BatchGetForecast(context.Context, *BatchGetForecastRequest) (*BatchGetForecastResponse, error)
+	BatchUpdateStreams(context.Context, *BatchUpdateStreamsRequest) (*BatchUpdateStreamsResponse, error)
+	ScenarioForecast(context.Context, *ScenarioForecastRequest) (*Forecast, error)
+}
+
+func RegisterSeerServer(s *grpc.Server, srv SeerServer) {
+	s.RegisterService(&_Seer_serviceDesc, srv)
+}
+
+func _Seer_CreateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateStreamRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SeerServer).CreateStream(ctx, in)
+		return srv.(SeerServer).CreateStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/CreateStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).CreateStream(ctx, req.(*CreateStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_GetStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).GetStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/GetStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).GetStream(ctx, req.(*GetStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_UpdateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).UpdateStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/UpdateStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).UpdateStream(ctx, req.(*UpdateStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_DeleteStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).DeleteStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/DeleteStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).DeleteStream(ctx, req.(*DeleteStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_ListStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).ListStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/ListStreams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).ListStreams(ctx, req.(*ListStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_GetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).GetForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/GetForecast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).GetForecast(ctx, req.(*GetForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_RefitStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefitStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).RefitStream(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/seer.Seer/CreateStream",
+		FullMethod: "/seer.Seer/RefitStream",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SeerServer).CreateStream(ctx, req.(*CreateStreamRequest))
+		return srv.(SeerServer).RefitStream(ctx, req.(*RefitStreamRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Seer_GetStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetStreamRequest)
+func _Seer_SampleForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SampleForecastRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SeerServer).GetStream(ctx, in)
+		return srv.(SeerServer).SampleForecast(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/seer.Seer/GetStream",
+		FullMethod: "/seer.Seer/SampleForecast",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SeerServer).GetStream(ctx, req.(*GetStreamRequest))
+		return srv.(SeerServer).SampleForecast(ctx, req.(*SampleForecastRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Seer_UpdateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateStreamRequest)
+func _Seer_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SeerServer).UpdateStream(ctx, in)
+		return srv.(SeerServer).GetEvents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/seer.Seer/UpdateStream",
+		FullMethod: "/seer.Seer/GetEvents",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SeerServer).UpdateStream(ctx, req.(*UpdateStreamRequest))
+		return srv.(SeerServer).GetEvents(ctx, req.(*GetEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Seer_DeleteStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteStreamRequest)
+func _Seer_RebuildStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RebuildStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SeerServer).RebuildStream(m, &seerRebuildStreamServer{stream})
+}
+
+type Seer_RebuildStreamServer interface {
+	Send(*RebuildProgress) error
+	grpc.ServerStream
+}
+
+type seerRebuildStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *seerRebuildStreamServer) Send(m *RebuildProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Seer_RollbackStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackStreamRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SeerServer).DeleteStream(ctx, in)
+		return srv.(SeerServer).RollbackStream(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/seer.Seer/DeleteStream",
+		FullMethod: "/seer.Seer/RollbackStream",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SeerServer).DeleteStream(ctx, req.(*DeleteStreamRequest))
+		return srv.(SeerServer).RollbackStream(ctx, req.(*RollbackStreamRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Seer_ListStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListStreamsRequest)
+func _Seer_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(google_protobuf1.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SeerServer).Backup(m, &seerBackupServer{stream})
+}
+
+type Seer_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type seerBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *seerBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Seer_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SeerServer).Restore(&seerRestoreServer{stream})
+}
+
+type Seer_RestoreServer interface {
+	SendAndClose(*RestoreResponse) error
+	Recv() (*BackupChunk, error)
+	grpc.ServerStream
+}
+
+type seerRestoreServer struct {
+	grpc.ServerStream
+}
+
+func (x *seerRestoreServer) SendAndClose(m *RestoreResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *seerRestoreServer) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Seer_ExportStreams_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportStreamsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SeerServer).ExportStreams(m, &seerExportStreamsServer{stream})
+}
+
+type Seer_ExportStreamsServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type seerExportStreamsServer struct {
+	grpc.ServerStream
+}
+
+func (x *seerExportStreamsServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Seer_ImportStreams_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SeerServer).ImportStreams(&seerImportStreamsServer{stream})
+}
+
+type Seer_ImportStreamsServer interface {
+	SendAndClose(*ImportStreamsResponse) error
+	Recv() (*ImportStreamsRequest, error)
+	grpc.ServerStream
+}
+
+type seerImportStreamsServer struct {
+	grpc.ServerStream
+}
+
+func (x *seerImportStreamsServer) SendAndClose(m *ImportStreamsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *seerImportStreamsServer) Recv() (*ImportStreamsRequest, error) {
+	m := new(ImportStreamsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Seer_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNamespaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SeerServer).ListStreams(ctx, in)
+		return srv.(SeerServer).CreateNamespace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/seer.Seer/ListStreams",
+		FullMethod: "/seer.Seer/CreateNamespace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SeerServer).ListStreams(ctx, req.(*ListStreamsRequest))
+		return srv.(SeerServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Seer_GetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetForecastRequest)
+func _Seer_GetNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNamespaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SeerServer).GetForecast(ctx, in)
+		return srv.(SeerServer).GetNamespace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/seer.Seer/GetForecast",
+		FullMethod: "/seer.Seer/GetNamespace",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SeerServer).GetForecast(ctx, req.(*GetForecastRequest))
+		return srv.(SeerServer).GetNamespace(ctx, req.(*GetNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_UpdateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).UpdateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/UpdateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).UpdateNamespace(ctx, req.(*UpdateNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_DeleteNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).DeleteNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/DeleteNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).DeleteNamespace(ctx, req.(*DeleteNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).ListNamespaces(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_PatchStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).PatchStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/PatchStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).PatchStream(ctx, req.(*PatchStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_RenameStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).RenameStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/RenameStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).RenameStream(ctx, req.(*RenameStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_CloneStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).CloneStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/CloneStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).CloneStream(ctx, req.(*CloneStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_ListExpiredStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExpiredStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).ListExpiredStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/ListExpiredStreams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).ListExpiredStreams(ctx, req.(*ListExpiredStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_BatchGetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).BatchGetForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/BatchGetForecast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).BatchGetForecast(ctx, req.(*BatchGetForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_BatchUpdateStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).BatchUpdateStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/BatchUpdateStreams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).BatchUpdateStreams(ctx, req.(*BatchUpdateStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seer_ScenarioForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScenarioForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeerServer).ScenarioForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/seer.Seer/ScenarioForecast",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeerServer).ScenarioForecast(ctx, req.(*ScenarioForecastRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -612,55 +2679,250 @@ var _Seer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetForecast",
 			Handler:    _Seer_GetForecast_Handler,
 		},
+		{
+			MethodName: "RefitStream",
+			Handler:    _Seer_RefitStream_Handler,
+		},
+		{
+			MethodName: "SampleForecast",
+			Handler:    _Seer_SampleForecast_Handler,
+		},
+		{
+			MethodName: "GetEvents",
+			Handler:    _Seer_GetEvents_Handler,
+		},
+		{
+			MethodName: "RollbackStream",
+			Handler:    _Seer_RollbackStream_Handler,
+		},
+		{
+			MethodName: "CreateNamespace",
+			Handler:    _Seer_CreateNamespace_Handler,
+		},
+		{
+			MethodName: "GetNamespace",
+			Handler:    _Seer_GetNamespace_Handler,
+		},
+		{
+			MethodName: "UpdateNamespace",
+			Handler:    _Seer_UpdateNamespace_Handler,
+		},
+		{
+			MethodName: "DeleteNamespace",
+			Handler:    _Seer_DeleteNamespace_Handler,
+		},
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _Seer_ListNamespaces_Handler,
+		},
+		{
+			MethodName: "PatchStream",
+			Handler:    _Seer_PatchStream_Handler,
+		},
+		{
+			MethodName: "RenameStream",
+			Handler:    _Seer_RenameStream_Handler,
+		},
+		{
+			MethodName: "CloneStream",
+			Handler:    _Seer_CloneStream_Handler,
+		},
+		{
+			MethodName: "ListExpiredStreams",
+			Handler:    _Seer_ListExpiredStreams_Handler,
+		},
+		{
+			MethodName: "BatchGetForecast",
+			Handler:    _Seer_BatchGetForecast_Handler,
+		},
+		{
+			MethodName: "BatchUpdateStreams",
+			Handler:    _Seer_BatchUpdateStreams_Handler,
+		},
+		{
+			MethodName: "ScenarioForecast",
+			Handler:    _Seer_ScenarioForecast_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RebuildStream",
+			Handler:       _Seer_RebuildStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Backup",
+			Handler:       _Seer_Backup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Restore",
+			Handler:       _Seer_Restore_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportStreams",
+			Handler:       _Seer_ExportStreams_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportStreams",
+			Handler:       _Seer_ImportStreams_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "seer.proto",
 }
 
 func init() { proto.RegisterFile("seer.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 670 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x54, 0xcd, 0x6f, 0xd3, 0x4e,
-	0x10, 0x8d, 0xf3, 0xf5, 0x6b, 0xc6, 0xf9, 0x85, 0x30, 0x81, 0xe2, 0xba, 0x87, 0x1a, 0x0b, 0x55,
-	0x01, 0xa1, 0x14, 0xa5, 0x12, 0x52, 0x85, 0x38, 0xd0, 0x24, 0x94, 0x48, 0x55, 0x2a, 0x36, 0x29,
-	0xd7, 0x68, 0x43, 0x86, 0xca, 0x92, 0xbf, 0xb0, 0x9d, 0xd2, 0xf6, 0xc8, 0x9f, 0xc6, 0x3f, 0xc5,
-	0x15, 0xed, 0xae, 0x9d, 0x3a, 0x1f, 0x02, 0x0e, 0xdc, 0xbc, 0x6f, 0xde, 0xcc, 0xec, 0x9b, 0x79,
-	0x6b, 0x80, 0x98, 0x28, 0xea, 0x84, 0x51, 0x90, 0x04, 0x58, 0x16, 0xdf, 0xe6, 0xfe, 0x55, 0x10,
-	0x5c, 0xb9, 0x74, 0x24, 0xb1, 0xd9, 0xe2, 0xcb, 0x11, 0x79, 0x61, 0x72, 0xab, 0x28, 0xe6, 0xc1,
-	0x7a, 0x30, 0x71, 0x3c, 0x8a, 0x13, 0xee, 0x85, 0x8a, 0x60, 0xff, 0xd0, 0xa0, 0x3a, 0x4e, 0x22,
-	0xe2, 0x1e, 0x22, 0x94, 0x7d, 0xee, 0x91, 0xa1, 0x59, 0x5a, 0xbb, 0xc6, 0xe4, 0x37, 0xee, 0x42,
-	0x35, 0xa4, 0xc8, 0x09, 0xe6, 0x46, 0xd1, 0xd2, 0xda, 0x1a, 0x4b, 0x4f, 0x78, 0x0a, 0x0f, 0x5c,
-	0x1e, 0x27, 0x53, 0xba, 0x26, 0x3f, 0x99, 0x8a, 0xa2, 0x46, 0xc9, 0xd2, 0xda, 0x7a, 0xd7, 0xec,
-	0xa8, 0x8e, 0x9d, 0xac, 0x63, 0x67, 0x92, 0x75, 0x64, 0xff, 0x8b, 0x94, 0x81, 0xc8, 0x10, 0x18,
-	0x3e, 0x83, 0xea, 0x3c, 0xf0, 0xb8, 0xe3, 0x1b, 0x65, 0x4b, 0x6b, 0x37, 0xba, 0xf5, 0x8e, 0xd4,
-	0xd6, 0x97, 0x18, 0x4b, 0x63, 0xd8, 0x84, 0x92, 0xe7, 0xf8, 0x46, 0x45, 0xb6, 0x17, 0x9f, 0x12,
-	0xe1, 0x37, 0x46, 0x35, 0x45, 0xf8, 0x8d, 0xfd, 0x11, 0x2a, 0xb2, 0x2c, 0xbe, 0x82, 0x8a, 0x14,
-	0x68, 0x68, 0x56, 0xe9, 0x0f, 0x97, 0x51, 0x44, 0x21, 0xf0, 0x9a, 0xbb, 0x0b, 0x8a, 0x8d, 0xa2,
-	0x55, 0x12, 0x02, 0xd5, 0xc9, 0xf6, 0x61, 0x67, 0xe8, 0x27, 0x14, 0x5d, 0x73, 0x17, 0x2d, 0xd0,
-	0xc3, 0x28, 0x98, 0xf1, 0x99, 0xe3, 0x3a, 0xc9, 0xad, 0x9c, 0x8f, 0xc6, 0xf2, 0x10, 0x1e, 0x80,
-	0xee, 0x06, 0xdf, 0x28, 0x9a, 0xce, 0x82, 0x85, 0x3f, 0x4f, 0x4b, 0x81, 0x84, 0x4e, 0x05, 0x22,
-	0x08, 0x8b, 0x30, 0x5c, 0x12, 0x4a, 0x8a, 0x20, 0x21, 0x49, 0xb0, 0xbf, 0x6b, 0xb0, 0xf3, 0x3e,
-	0x88, 0xe8, 0x33, 0x8f, 0xff, 0xa1, 0x0c, 0x7c, 0x09, 0x35, 0x27, 0x95, 0x11, 0xcb, 0xae, 0x7a,
-	0xb7, 0xa1, 0xc6, 0x9c, 0xa9, 0x63, 0xf7, 0x04, 0xfb, 0x0d, 0xb4, 0x7a, 0x11, 0xf1, 0x84, 0x94,
-	0x23, 0x18, 0x7d, 0x5d, 0x50, 0x9c, 0x88, 0x45, 0xc5, 0x12, 0x90, 0xd2, 0xf5, 0x6c, 0x51, 0x29,
-	0x29, 0x8d, 0xd9, 0x87, 0xd0, 0x3c, 0xa3, 0x64, 0x35, 0x73, 0x8b, 0xa5, 0xec, 0xe7, 0xd0, 0xea,
-	0x93, 0x4b, 0xeb, 0x4d, 0xb6, 0x51, 0x19, 0xe0, 0xb9, 0x13, 0xa7, 0x35, 0xe3, 0x8c, 0xb9, 0x0f,
-	0xb5, 0x90, 0x5f, 0xd1, 0x34, 0x76, 0xee, 0x14, 0xbd, 0xc2, 0x76, 0x04, 0x30, 0x76, 0xee, 0x48,
-	0x0c, 0x5a, 0x06, 0xfd, 0x85, 0x37, 0xa3, 0x48, 0xba, 0xb6, 0xc2, 0x40, 0x40, 0x23, 0x89, 0xd8,
-	0x6f, 0xa1, 0xb5, 0x52, 0x33, 0x0e, 0x03, 0x3f, 0x26, 0x3c, 0x84, 0xff, 0x94, 0x8e, 0x6c, 0xe8,
-	0xab, 0x22, 0xb3, 0xa0, 0x7d, 0x0e, 0xad, 0xcb, 0x70, 0xce, 0xff, 0xe2, 0xf6, 0xf8, 0x14, 0x2a,
-	0xf2, 0x79, 0xc8, 0x4b, 0xe8, 0x5d, 0x5d, 0x15, 0x94, 0x46, 0x65, 0x2a, 0x62, 0xbf, 0x06, 0x3c,
-	0xa3, 0x24, 0xdb, 0xfb, 0xef, 0x8a, 0xd5, 0x41, 0xf3, 0x53, 0x35, 0x9a, 0xff, 0x22, 0x82, 0xaa,
-	0x7a, 0x26, 0xd8, 0x00, 0xe8, 0x5d, 0x8c, 0x26, 0xc3, 0xd1, 0xe5, 0xc5, 0xe5, 0xb8, 0x59, 0xc0,
-	0x47, 0xd0, 0xbc, 0x3f, 0x4f, 0xd9, 0xf0, 0xec, 0xc3, 0xa4, 0xa9, 0xe1, 0x13, 0x68, 0xe5, 0xd0,
-	0xe1, 0x68, 0x32, 0x60, 0x9f, 0xde, 0x9d, 0x37, 0x8b, 0x88, 0xd0, 0xe8, 0x0f, 0xc7, 0x3d, 0x36,
-	0x98, 0x0c, 0x52, 0x72, 0x09, 0x1f, 0xc3, 0xc3, 0x25, 0xb6, 0xa4, 0x96, 0xbb, 0x3f, 0x8b, 0x50,
-	0x1e, 0x13, 0x45, 0x78, 0x02, 0xf5, 0xbc, 0x4b, 0x70, 0x4f, 0x09, 0xdb, 0xe2, 0x1c, 0x73, 0x65,
-	0x88, 0x76, 0x01, 0x8f, 0xa1, 0xb6, 0xf4, 0x08, 0xee, 0xaa, 0xe0, 0xba, 0x69, 0x36, 0x92, 0x4e,
-	0xa0, 0x9e, 0x1f, 0x79, 0xd6, 0x6f, 0xcb, 0x1a, 0x36, 0x52, 0x7b, 0x50, 0xcf, 0x7b, 0x2d, 0x4b,
-	0xdd, 0xe2, 0x3f, 0x73, 0x77, 0xe3, 0x91, 0x0d, 0xc4, 0x7f, 0xd4, 0x2e, 0x60, 0x1f, 0xf4, 0x9c,
-	0x63, 0xd0, 0x50, 0x35, 0x36, 0x8d, 0x69, 0xee, 0x6d, 0x89, 0x28, 0x7b, 0x49, 0x15, 0x7a, 0x6e,
-	0xd5, 0x59, 0x95, 0xcd, 0xed, 0x9b, 0xe9, 0xfb, 0xcc, 0x60, 0xbb, 0x30, 0xab, 0xca, 0x2b, 0x1d,
-	0xff, 0x0a, 0x00, 0x00, 0xff, 0xff, 0x31, 0xcb, 0x5c, 0x49, 0xfc, 0x05, 0x00, 0x00,
+	// 2352 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0x5b, 0x6f, 0xdb, 0xc8,
+	0xf5, 0x37, 0x25, 0xeb, 0xc2, 0x23, 0x59, 0x96, 0xc7, 0x97, 0xc8, 0xcc, 0x6e, 0xa2, 0x10, 0xbb,
+	0xf9, 0xfb, 0xef, 0xcd, 0x3a, 0x59, 0x07, 0x45, 0x9a, 0xdd, 0x6e, 0x01, 0x5f, 0x14, 0xaf, 0x52,
+	0x5b, 0x76, 0x47, 0x4a, 0x80, 0xa0, 0xdd, 0x0a, 0xb4, 0x34, 0x96, 0xb9, 0xe6, 0x45, 0xe5, 0x50,
+	0x89, 0xbd, 0x8f, 0x6d, 0xdf, 0xfa, 0x19, 0x0a, 0xf4, 0x43, 0x15, 0xe8, 0x43, 0x3f, 0x47, 0xd1,
+	0xd7, 0x62, 0x6e, 0x14, 0x29, 0x51, 0xb6, 0x73, 0xe9, 0x1b, 0xe7, 0x9c, 0xdf, 0x9c, 0xdb, 0xcc,
+	0x39, 0x73, 0x0e, 0x01, 0x28, 0x21, 0xc1, 0xd6, 0x30, 0xf0, 0x43, 0x1f, 0xcd, 0xb3, 0x6f, 0xe3,
+	0xde, 0xc0, 0xf7, 0x07, 0x0e, 0x79, 0xcc, 0x69, 0xa7, 0xa3, 0xb3, 0xc7, 0xfd, 0x51, 0x60, 0x85,
+	0xb6, 0xef, 0x09, 0x94, 0x71, 0x77, 0x92, 0x4f, 0xdc, 0x61, 0x78, 0x25, 0x99, 0xf5, 0x49, 0xe6,
+	0x99, 0x4d, 0x9c, 0x7e, 0xd7, 0xb5, 0xe8, 0x85, 0x44, 0xdc, 0x9f, 0x44, 0x84, 0xb6, 0x4b, 0x68,
+	0x68, 0xb9, 0x43, 0x01, 0x30, 0xff, 0x95, 0x85, 0x7c, 0x3b, 0x0c, 0x88, 0xe5, 0x22, 0x04, 0xf3,
+	0x9e, 0xe5, 0x92, 0x9a, 0x56, 0xd7, 0x36, 0x74, 0xcc, 0xbf, 0xd1, 0x1a, 0xe4, 0x87, 0x24, 0xb0,
+	0xfd, 0x7e, 0x2d, 0x53, 0xd7, 0x36, 0x34, 0x2c, 0x57, 0x68, 0x17, 0x16, 0x1d, 0x8b, 0x86, 0x5d,
+	0xf2, 0x96, 0x78, 0x61, 0x97, 0x09, 0xad, 0x65, 0xeb, 0xda, 0x46, 0x69, 0xdb, 0xd8, 0x12, 0x1a,
+	0xb7, 0x94, 0xc6, 0xad, 0x8e, 0xd2, 0x88, 0x17, 0xd8, 0x96, 0x06, 0xdb, 0xc1, 0x68, 0xe8, 0x0b,
+	0xc8, 0xf7, 0x7d, 0xd7, 0xb2, 0xbd, 0xda, 0x7c, 0x5d, 0xdb, 0xa8, 0x6c, 0x97, 0xb7, 0x78, 0x74,
+	0xf6, 0x39, 0x0d, 0x4b, 0x1e, 0xaa, 0x42, 0xd6, 0xb5, 0xbd, 0x5a, 0x8e, 0xab, 0xcf, 0xba, 0x92,
+	0x62, 0x5d, 0xd6, 0xf2, 0x92, 0x62, 0x5d, 0x22, 0x03, 0x8a, 0x01, 0x79, 0x6b, 0x53, 0xdb, 0xf7,
+	0x6a, 0x85, 0xba, 0xb6, 0x31, 0x8f, 0xa3, 0x35, 0xfa, 0x1a, 0xf4, 0x80, 0x84, 0xc4, 0x63, 0x31,
+	0xad, 0x15, 0xb9, 0x8d, 0x8b, 0x42, 0x11, 0x56, 0x64, 0x3c, 0x46, 0xa0, 0x6d, 0xd0, 0xa9, 0x67,
+	0x0d, 0xe9, 0xb9, 0x1f, 0xd2, 0x9a, 0xce, 0xe1, 0x2b, 0x02, 0xde, 0x96, 0xe4, 0x13, 0xdf, 0xb1,
+	0x7b, 0x57, 0x78, 0x0c, 0x43, 0x4f, 0x20, 0xef, 0x58, 0xa7, 0xc4, 0xa1, 0x35, 0xa8, 0x67, 0x37,
+	0x4a, 0xdb, 0x35, 0xb9, 0x81, 0x87, 0x75, 0xeb, 0x90, 0xb3, 0x1a, 0x5e, 0x18, 0x5c, 0x61, 0x89,
+	0x43, 0x5f, 0x41, 0x36, 0x0c, 0x9d, 0x5a, 0x89, 0xcb, 0x5f, 0x9f, 0x0a, 0xd9, 0xbe, 0xbc, 0x03,
+	0x98, 0xa1, 0x8c, 0xe7, 0x50, 0x8a, 0xc9, 0x60, 0xee, 0x5f, 0x90, 0x2b, 0x79, 0x4a, 0xec, 0x13,
+	0xad, 0x40, 0xee, 0xad, 0xe5, 0x8c, 0x08, 0x3f, 0x23, 0x1d, 0x8b, 0xc5, 0xb7, 0x99, 0x5f, 0x6a,
+	0x66, 0x0b, 0xf4, 0xc8, 0x4b, 0x06, 0xeb, 0xf9, 0x23, 0x2f, 0xe4, 0x5b, 0xb3, 0x58, 0x2c, 0x98,
+	0x29, 0xd6, 0x40, 0x6c, 0xbd, 0xde, 0x14, 0x6b, 0x40, 0xcc, 0x1f, 0xa1, 0x92, 0x0c, 0xc3, 0x0c,
+	0xa1, 0xbf, 0x80, 0xa2, 0xed, 0x85, 0x24, 0x78, 0x6b, 0x39, 0x37, 0x4b, 0x8e, 0xa0, 0xe6, 0x6f,
+	0x21, 0xc7, 0xaf, 0x07, 0x7a, 0x02, 0x39, 0x7e, 0x51, 0x6b, 0x5a, 0x3d, 0x7b, 0xc3, 0xa5, 0x12,
+	0x40, 0x76, 0x51, 0xb9, 0xdb, 0xb4, 0x96, 0xa9, 0x67, 0xd9, 0x45, 0x15, 0x2b, 0xd3, 0x83, 0x62,
+	0x53, 0x8a, 0x47, 0x75, 0x28, 0x0d, 0x03, 0xff, 0xd4, 0x3a, 0xb5, 0x1d, 0x3b, 0x14, 0x11, 0xd4,
+	0x70, 0x9c, 0x84, 0xee, 0x43, 0xc9, 0xf1, 0xdf, 0x91, 0xa0, 0x7b, 0xea, 0x8f, 0xbc, 0xbe, 0x14,
+	0x05, 0x9c, 0xb4, 0xcb, 0x28, 0x0c, 0x30, 0x1a, 0x0e, 0x23, 0x40, 0x56, 0x00, 0x38, 0x89, 0x03,
+	0xcc, 0x3f, 0x69, 0x50, 0x7c, 0xe1, 0x07, 0xa4, 0x67, 0xd1, 0x4f, 0xe8, 0x06, 0x7a, 0x04, 0xba,
+	0x8a, 0x12, 0xe5, 0x5a, 0x4b, 0xdb, 0x15, 0x71, 0xcb, 0x94, 0x77, 0x78, 0x0c, 0x30, 0xef, 0xc1,
+	0xfc, 0x89, 0x15, 0x9e, 0xc7, 0xa4, 0x69, 0x89, 0xa0, 0xfc, 0x08, 0x85, 0xb6, 0xe5, 0x0e, 0x1d,
+	0x42, 0x3f, 0xc0, 0xc4, 0x3a, 0xe4, 0x86, 0x56, 0x78, 0x2e, 0x2c, 0x2c, 0x6d, 0x83, 0x30, 0x83,
+	0xe9, 0xc3, 0x82, 0x61, 0x7e, 0x07, 0xcb, 0x7b, 0x01, 0xb1, 0x42, 0x22, 0x32, 0x00, 0x93, 0x3f,
+	0x8e, 0x08, 0x0d, 0x59, 0xbe, 0x53, 0x4e, 0xe0, 0x91, 0x2f, 0xa9, 0x7c, 0x97, 0x20, 0xc9, 0x33,
+	0x1f, 0x42, 0xf5, 0x80, 0x84, 0xc9, 0x9d, 0x29, 0x95, 0xc9, 0xfc, 0x7f, 0x58, 0xde, 0x27, 0x0e,
+	0x09, 0xc9, 0xcd, 0xd0, 0xff, 0x68, 0x80, 0x0e, 0x6d, 0x2a, 0x85, 0x52, 0x05, 0xbd, 0x0b, 0xfa,
+	0xd0, 0x1a, 0x90, 0x2e, 0xb5, 0x7f, 0x16, 0xf8, 0x1c, 0x2e, 0x32, 0x42, 0xdb, 0xfe, 0x99, 0xb0,
+	0x83, 0xe6, 0x4c, 0x6f, 0xe4, 0x9e, 0x92, 0x80, 0x5f, 0xe2, 0x1c, 0x06, 0x46, 0x6a, 0x71, 0x0a,
+	0xfa, 0x1c, 0xf8, 0xaa, 0x1b, 0xfa, 0x17, 0xc4, 0xe3, 0xc5, 0x4f, 0xc7, 0x5c, 0x5e, 0x87, 0x11,
+	0x78, 0xe1, 0x0c, 0xc8, 0x99, 0x7d, 0xc9, 0x8b, 0x9b, 0x8e, 0xe5, 0x0a, 0x7d, 0x09, 0x39, 0x3f,
+	0xe8, 0x93, 0x80, 0x17, 0xb4, 0x8a, 0x2a, 0x45, 0xcc, 0xba, 0x63, 0x46, 0xc6, 0x82, 0x8b, 0xee,
+	0x01, 0xf4, 0x09, 0xed, 0x11, 0xaf, 0x6f, 0x7b, 0x03, 0x5e, 0xea, 0x8a, 0x38, 0x46, 0x41, 0x5f,
+	0x42, 0x85, 0x97, 0x92, 0x2e, 0x25, 0x0e, 0xe9, 0x85, 0x7e, 0xc0, 0xeb, 0x9e, 0xce, 0x4a, 0xec,
+	0x29, 0x71, 0xda, 0x92, 0x68, 0xfe, 0x45, 0x83, 0xe5, 0x84, 0xe7, 0x74, 0xe8, 0x7b, 0x94, 0xa0,
+	0x87, 0x50, 0x10, 0xe1, 0x56, 0xe7, 0x9e, 0x3c, 0x0b, 0xc5, 0x44, 0x0f, 0x61, 0xd1, 0x23, 0x97,
+	0x61, 0x37, 0xe6, 0xa9, 0xa8, 0x31, 0x0b, 0x8c, 0x7c, 0x12, 0x79, 0xfb, 0x39, 0x40, 0xe8, 0x87,
+	0x96, 0x23, 0x62, 0x99, 0xe5, 0xa5, 0x40, 0xe7, 0x14, 0x16, 0x4c, 0xf3, 0x10, 0x96, 0x5f, 0x0d,
+	0xfb, 0xd6, 0x2d, 0xce, 0x0a, 0x3d, 0x80, 0x1c, 0x7f, 0x53, 0x64, 0xd9, 0x28, 0x09, 0xbb, 0x78,
+	0x55, 0xc0, 0x82, 0x63, 0xfe, 0x5b, 0x03, 0x74, 0x40, 0x42, 0x95, 0x65, 0xd7, 0x49, 0x2b, 0x83,
+	0xe6, 0xc9, 0xb3, 0xd3, 0x3c, 0xf4, 0x14, 0x4a, 0xd6, 0x60, 0x10, 0x90, 0x01, 0xaf, 0x3b, 0xdc,
+	0xcc, 0xca, 0xf6, 0x92, 0xd0, 0xb0, 0x33, 0x66, 0xe0, 0x38, 0x8a, 0x1d, 0xe4, 0x3b, 0xdb, 0xeb,
+	0xfb, 0xef, 0xf8, 0x41, 0xe6, 0xb0, 0x5c, 0xa1, 0xe7, 0x00, 0x34, 0xb4, 0x02, 0xf9, 0xf8, 0xe5,
+	0x6e, 0x7c, 0xfc, 0x74, 0x8e, 0x66, 0x6b, 0x56, 0x1d, 0x89, 0xd7, 0x17, 0x1b, 0xf3, 0x37, 0x6e,
+	0x2c, 0x10, 0xaf, 0xcf, 0x56, 0xe6, 0x6f, 0x00, 0x61, 0x72, 0x66, 0x87, 0x9f, 0x24, 0x88, 0x3f,
+	0xc1, 0xaa, 0x28, 0x01, 0xef, 0x1f, 0xc6, 0xbb, 0xa0, 0x7b, 0x23, 0xb7, 0x2b, 0x8a, 0x40, 0x56,
+	0xe4, 0x8d, 0x37, 0x72, 0x59, 0x05, 0xa0, 0x6c, 0x3b, 0x25, 0xa4, 0xcf, 0x83, 0x95, 0xc5, 0xfc,
+	0xdb, 0xfc, 0x87, 0xc6, 0x73, 0x9a, 0xeb, 0xa7, 0xd7, 0xe9, 0x49, 0xc6, 0x34, 0xf3, 0xa1, 0x31,
+	0xcd, 0xde, 0x3a, 0xa6, 0xc9, 0x1a, 0x30, 0x3f, 0x51, 0x03, 0x92, 0x29, 0x9e, 0x9b, 0x48, 0x71,
+	0xf3, 0x0f, 0xb0, 0x14, 0xf3, 0x4a, 0x66, 0x56, 0x14, 0x7a, 0x6d, 0x56, 0xe8, 0x6f, 0x9b, 0x54,
+	0xe6, 0x09, 0xac, 0x60, 0x72, 0x3a, 0xb2, 0x9d, 0xfe, 0xcd, 0x27, 0x3e, 0xae, 0xad, 0x99, 0x6b,
+	0x6a, 0xeb, 0x9f, 0x35, 0x58, 0x94, 0x22, 0x4f, 0x02, 0x7f, 0x10, 0x10, 0x4a, 0xd1, 0xff, 0xc1,
+	0x22, 0x37, 0x8b, 0x76, 0x03, 0x32, 0x74, 0xac, 0x2b, 0xd2, 0x97, 0x4f, 0x79, 0x85, 0x48, 0xcf,
+	0x04, 0x15, 0x3d, 0x80, 0xb2, 0x04, 0xf2, 0xc4, 0xe6, 0x8a, 0xb2, 0xb8, 0x24, 0x68, 0x1d, 0x46,
+	0x8a, 0x59, 0x91, 0xbd, 0xc6, 0x8a, 0xdf, 0xc1, 0x2a, 0xf6, 0x1d, 0xe7, 0xd4, 0xea, 0x5d, 0xdc,
+	0xec, 0xd8, 0x16, 0xcc, 0xdf, 0xf2, 0x32, 0x70, 0x9c, 0xf9, 0x00, 0x4a, 0xbb, 0x56, 0xef, 0x62,
+	0x34, 0xdc, 0x3b, 0x1f, 0x79, 0x17, 0x4c, 0x64, 0xdf, 0x0a, 0x2d, 0x2e, 0xb2, 0x8c, 0xf9, 0xb7,
+	0xf9, 0x15, 0x0b, 0x02, 0x0d, 0xfd, 0x80, 0x44, 0xa7, 0x56, 0x8b, 0xd7, 0x43, 0xe6, 0x96, 0x5a,
+	0x9a, 0xbf, 0x87, 0x95, 0xc6, 0xe5, 0xd0, 0x0f, 0x26, 0x1f, 0x8f, 0x4d, 0xc8, 0x9f, 0xf9, 0x81,
+	0x6b, 0x89, 0x83, 0xae, 0x6c, 0x23, 0x79, 0xd0, 0x1c, 0xfb, 0x82, 0x73, 0xb0, 0x44, 0x30, 0xe9,
+	0xe7, 0x36, 0x53, 0x78, 0xc5, 0xdd, 0x28, 0x62, 0xb5, 0x64, 0xd6, 0x8a, 0x1d, 0xb3, 0xad, 0x7d,
+	0x0d, 0x2b, 0x4d, 0xf7, 0x23, 0x0d, 0x50, 0x72, 0x33, 0x31, 0xb9, 0xdf, 0xc0, 0xea, 0x84, 0xdc,
+	0x1b, 0x63, 0x41, 0x41, 0x6f, 0x59, 0x2e, 0xa1, 0x43, 0xab, 0x47, 0x52, 0x0f, 0xeb, 0x3e, 0x94,
+	0x5c, 0xeb, 0xb2, 0xab, 0xb6, 0x8b, 0x1b, 0x02, 0xae, 0x75, 0x29, 0x75, 0xa0, 0x6f, 0x60, 0x95,
+	0x01, 0x86, 0xbe, 0xcd, 0xee, 0x11, 0xeb, 0xa3, 0x28, 0xe9, 0xf9, 0xbc, 0x91, 0x62, 0xbd, 0x18,
+	0x72, 0xad, 0xcb, 0x13, 0xce, 0x3b, 0x21, 0x41, 0x9b, 0x73, 0xcc, 0x03, 0x58, 0x13, 0xcd, 0x44,
+	0xa4, 0x5a, 0x45, 0xe0, 0x6b, 0xd0, 0x3d, 0x45, 0x93, 0xe9, 0x26, 0x9f, 0xd3, 0x31, 0x74, 0x8c,
+	0x60, 0x0d, 0xc3, 0x01, 0x09, 0xa7, 0xa4, 0xa4, 0x35, 0x0c, 0x07, 0xb0, 0x26, 0xde, 0xab, 0x8f,
+	0xd5, 0xf9, 0x08, 0xd6, 0x44, 0x93, 0x72, 0x2b, 0xb5, 0x4d, 0x58, 0x63, 0x8f, 0x75, 0x84, 0x1d,
+	0x9f, 0xc9, 0x63, 0x80, 0x48, 0xa8, 0x7a, 0xb2, 0xa7, 0xf4, 0xc6, 0x20, 0xe6, 0x5f, 0x35, 0x40,
+	0x27, 0x56, 0xd8, 0x3b, 0xff, 0x44, 0xa5, 0x03, 0x7d, 0xc7, 0x1a, 0x5f, 0x16, 0x12, 0x3e, 0x5d,
+	0xce, 0x2c, 0xb1, 0x2f, 0xd8, 0x00, 0x7a, 0x64, 0xd1, 0x0b, 0x0c, 0x02, 0xce, 0xbe, 0xcd, 0x9f,
+	0x60, 0x39, 0x61, 0x8c, 0xf4, 0xea, 0x56, 0x0d, 0x21, 0x0b, 0xb9, 0x6b, 0x0f, 0xc4, 0xac, 0x50,
+	0xcb, 0xc4, 0xbb, 0xa6, 0x23, 0x45, 0xc6, 0x63, 0x84, 0xb9, 0x0f, 0xcb, 0x98, 0x30, 0xc7, 0x6e,
+	0xf6, 0x7c, 0x1d, 0x8a, 0x1e, 0x79, 0xd7, 0xe5, 0x74, 0x51, 0x81, 0x0b, 0x1e, 0x79, 0xc7, 0x02,
+	0x6a, 0xb6, 0x00, 0xed, 0x39, 0xbe, 0x37, 0x21, 0x64, 0x0d, 0xf2, 0xd4, 0x1f, 0x05, 0x3d, 0x25,
+	0x46, 0xae, 0xd8, 0x60, 0xd1, 0x27, 0x34, 0xb4, 0xbd, 0xb1, 0x91, 0x3a, 0x8e, 0x93, 0xcc, 0x3e,
+	0xac, 0xb3, 0xa3, 0x6d, 0x5c, 0x0e, 0xed, 0x80, 0xf4, 0x27, 0x52, 0x79, 0xdc, 0x2b, 0x6a, 0x89,
+	0x5e, 0xf1, 0x31, 0xe4, 0x2c, 0xda, 0xf5, 0xcf, 0x6e, 0x53, 0xfc, 0x2c, 0x7a, 0x7c, 0x66, 0xee,
+	0x83, 0x91, 0xa6, 0xe5, 0xfd, 0x9a, 0x3e, 0xf3, 0x9f, 0x1a, 0xdc, 0xd9, 0x65, 0xc7, 0x95, 0xd2,
+	0x64, 0xad, 0x40, 0x8e, 0xdf, 0x32, 0x2e, 0x41, 0xc7, 0x62, 0x91, 0xd2, 0x8d, 0x66, 0x52, 0xba,
+	0x51, 0xd1, 0x46, 0x64, 0x67, 0x74, 0x63, 0xf3, 0xef, 0xd9, 0x8d, 0xe5, 0x12, 0xdd, 0xd8, 0x17,
+	0xb0, 0x30, 0x9e, 0xe3, 0x6c, 0x42, 0x6b, 0x79, 0x3e, 0xf0, 0x24, 0x89, 0xe6, 0x4b, 0xa8, 0x4d,
+	0x3b, 0x26, 0xa3, 0xb3, 0x05, 0x85, 0x80, 0xd0, 0x91, 0x13, 0xaa, 0xe8, 0xac, 0xc4, 0xa3, 0x13,
+	0xc1, 0x15, 0xc8, 0xbc, 0x84, 0x4a, 0x92, 0x95, 0x7a, 0xc5, 0x36, 0xa1, 0x78, 0x26, 0xf9, 0xf2,
+	0x14, 0xe5, 0xd8, 0x16, 0x09, 0x8c, 0xf8, 0xe8, 0x21, 0xe4, 0x48, 0x10, 0xf8, 0x81, 0x4c, 0xae,
+	0xaa, 0x00, 0x72, 0x83, 0x1b, 0x8c, 0x8e, 0x05, 0xdb, 0xfc, 0x16, 0x60, 0x4c, 0x64, 0x5a, 0x7b,
+	0x7e, 0x5f, 0x0d, 0x30, 0xfc, 0x9b, 0x95, 0x70, 0x97, 0x50, 0xaa, 0xe6, 0x7a, 0x1d, 0xab, 0xa5,
+	0x79, 0x02, 0xeb, 0x7c, 0x6f, 0xbc, 0x1d, 0x8f, 0xee, 0xe1, 0x53, 0x28, 0x88, 0xa4, 0x55, 0x21,
+	0x58, 0x17, 0x26, 0xa4, 0xf4, 0xee, 0x58, 0x21, 0xcd, 0x97, 0x60, 0xa4, 0x49, 0x94, 0x51, 0x7d,
+	0x34, 0x19, 0x55, 0x14, 0x8f, 0xaa, 0xd8, 0x33, 0x8e, 0xe9, 0x10, 0xca, 0x71, 0xc6, 0x47, 0x94,
+	0xab, 0xdb, 0xc6, 0xf2, 0x6f, 0x19, 0xb8, 0xd3, 0xee, 0x11, 0xcf, 0x0a, 0x6c, 0xff, 0x36, 0x9d,
+	0xf0, 0xcd, 0x9d, 0xf5, 0xff, 0xf2, 0x96, 0x27, 0xfb, 0xe3, 0xfc, 0x87, 0xf6, 0xc7, 0x85, 0x5b,
+	0xf7, 0xc7, 0x9b, 0x01, 0xe4, 0xc5, 0xff, 0x38, 0x54, 0x01, 0xd8, 0x3b, 0x6e, 0x75, 0x9a, 0xad,
+	0x57, 0xc7, 0xaf, 0xda, 0xd5, 0x39, 0xb4, 0x02, 0xd5, 0xf1, 0xba, 0x8b, 0x9b, 0x07, 0x3f, 0x74,
+	0xaa, 0x1a, 0xba, 0x03, 0xcb, 0x31, 0x6a, 0xb3, 0xd5, 0x69, 0xe0, 0xd7, 0x3b, 0x87, 0xd5, 0x0c,
+	0x42, 0x50, 0xd9, 0x6f, 0xb6, 0xf7, 0x70, 0xa3, 0xd3, 0x90, 0xe0, 0x2c, 0x5a, 0x85, 0xa5, 0x88,
+	0x16, 0x41, 0xe7, 0x37, 0x37, 0xa1, 0x14, 0x8b, 0x0c, 0x2a, 0xc2, 0x7c, 0xeb, 0xb8, 0xd5, 0xa8,
+	0xce, 0xa1, 0x02, 0x64, 0xdb, 0xaf, 0x8e, 0xaa, 0x1a, 0x23, 0x1d, 0x35, 0x76, 0x5a, 0xd5, 0xcc,
+	0xe6, 0x13, 0xd0, 0xa3, 0xd9, 0x19, 0x95, 0xa0, 0xb0, 0xfb, 0xa6, 0xdb, 0xda, 0x39, 0x62, 0xe0,
+	0x35, 0x40, 0xbb, 0x6f, 0xba, 0x87, 0x3b, 0xed, 0x4e, 0xb7, 0xf1, 0xba, 0xd1, 0xea, 0x74, 0x3b,
+	0xcd, 0xa3, 0x46, 0x55, 0xdb, 0x7c, 0x00, 0xe5, 0x78, 0x8f, 0xc4, 0x64, 0xbd, 0x6c, 0x1f, 0xb7,
+	0x84, 0xf8, 0xbd, 0xf6, 0xeb, 0xaa, 0xb6, 0xf9, 0x3d, 0xe8, 0xd1, 0xd3, 0x82, 0xca, 0x50, 0x6c,
+	0xb6, 0xba, 0x27, 0x87, 0x3b, 0x7b, 0x4c, 0xea, 0x22, 0x94, 0x3a, 0x78, 0xa7, 0xd5, 0x7e, 0x71,
+	0x8c, 0x8f, 0x1a, 0xfb, 0x55, 0x0d, 0x2d, 0xc1, 0x02, 0x6e, 0x34, 0x5b, 0xcd, 0x4e, 0x73, 0xe7,
+	0xb0, 0xd9, 0x6e, 0xec, 0x57, 0x33, 0xdb, 0x7f, 0xaf, 0xc0, 0x7c, 0x9b, 0x90, 0x00, 0x3d, 0x87,
+	0x72, 0xfc, 0x3f, 0x08, 0x92, 0xe9, 0x94, 0xf2, 0x6f, 0xc4, 0x48, 0xdc, 0x62, 0x73, 0x0e, 0x3d,
+	0x05, 0x3d, 0xfa, 0x0b, 0x82, 0xd6, 0x04, 0x73, 0xf2, 0xb7, 0xc8, 0xd4, 0xa6, 0xe7, 0x50, 0x8e,
+	0x67, 0x21, 0x9a, 0x9d, 0xbe, 0x53, 0x5b, 0xf7, 0xa0, 0x1c, 0xff, 0x9b, 0xa2, 0xb6, 0xa6, 0xfc,
+	0x61, 0x31, 0xd6, 0xa6, 0xee, 0x4d, 0x83, 0xfd, 0x92, 0x36, 0xe7, 0xd0, 0x3e, 0x94, 0x62, 0x3f,
+	0x1b, 0x50, 0x6d, 0xfc, 0x6f, 0x23, 0x59, 0x68, 0x8c, 0xf5, 0x14, 0x8e, 0x28, 0x18, 0xdc, 0x8b,
+	0x52, 0xac, 0x3e, 0x2b, 0x29, 0xd3, 0x6f, 0x91, 0x31, 0x51, 0x49, 0xcd, 0x39, 0xf4, 0x0c, 0x4a,
+	0xb1, 0x09, 0x59, 0x6d, 0x9d, 0x1e, 0x9a, 0xa7, 0xdc, 0xff, 0x35, 0x54, 0x92, 0xd3, 0x30, 0xba,
+	0x2b, 0x11, 0x69, 0x33, 0xb2, 0xb1, 0x10, 0x67, 0x52, 0xbe, 0x5f, 0x8f, 0x46, 0xc1, 0xd8, 0x71,
+	0x25, 0x26, 0x5e, 0xe3, 0xce, 0x14, 0x3d, 0xf2, 0xf9, 0x05, 0x2c, 0x24, 0x46, 0x3d, 0x64, 0x28,
+	0xd3, 0xa7, 0xe7, 0x3f, 0x63, 0x35, 0xc1, 0x53, 0x83, 0x9c, 0x39, 0xf7, 0x44, 0x43, 0xdf, 0x43,
+	0x25, 0x39, 0x5a, 0x29, 0x3f, 0x52, 0x07, 0xae, 0xa9, 0x30, 0x3c, 0x83, 0xbc, 0x18, 0x9e, 0xd0,
+	0x8c, 0x43, 0x36, 0x96, 0x54, 0x21, 0x8d, 0x46, 0x2c, 0xae, 0xf7, 0x19, 0x14, 0xe4, 0x48, 0x85,
+	0xa6, 0x11, 0x63, 0x83, 0x13, 0x43, 0x97, 0x39, 0xb7, 0xa1, 0xa1, 0x5d, 0x58, 0x48, 0x8c, 0x57,
+	0xca, 0xf1, 0xb4, 0x99, 0xcb, 0x58, 0x8a, 0xf3, 0xc6, 0xca, 0x0f, 0x61, 0xa1, 0xe9, 0xa6, 0xc8,
+	0x48, 0x1b, 0x9b, 0x8c, 0xbb, 0xa9, 0xbc, 0x84, 0x45, 0x8b, 0x13, 0xf3, 0x06, 0xfa, 0x2c, 0x9e,
+	0xb7, 0x93, 0x9d, 0xbc, 0x31, 0xd9, 0x87, 0x9b, 0x73, 0xe8, 0x57, 0x50, 0x8e, 0x8f, 0x1a, 0x2a,
+	0x9b, 0x52, 0xc6, 0x8f, 0xb4, 0xdd, 0xbb, 0xb0, 0x38, 0x31, 0x7d, 0x28, 0x0b, 0xd2, 0x87, 0x92,
+	0x34, 0x19, 0x4d, 0x58, 0x9c, 0x18, 0x3c, 0x94, 0x8c, 0xf4, 0x79, 0xe4, 0x9a, 0xac, 0xfe, 0x01,
+	0x2a, 0xc9, 0xa9, 0x64, 0xe6, 0xe5, 0xf8, 0x6c, 0x9c, 0xd6, 0xd3, 0x33, 0x8c, 0xa8, 0x0f, 0xb1,
+	0x31, 0x40, 0xa5, 0xe7, 0xf4, 0x98, 0x62, 0xac, 0xa7, 0x70, 0x62, 0xf5, 0xa1, 0x1c, 0x6f, 0xf0,
+	0x55, 0x70, 0x53, 0x9a, 0xfe, 0x94, 0xfb, 0x5d, 0x8a, 0x75, 0xf5, 0xca, 0x80, 0xe9, 0x46, 0x7f,
+	0x6a, 0xe3, 0x1b, 0x40, 0xd3, 0x8d, 0x35, 0xba, 0x3f, 0xf6, 0x37, 0xb5, 0xb1, 0x37, 0xea, 0xb3,
+	0x01, 0x91, 0x3b, 0x6d, 0xa8, 0x4e, 0xf6, 0xa4, 0xe8, 0xf3, 0x58, 0xbb, 0x92, 0x52, 0xf8, 0xee,
+	0xcd, 0x62, 0x47, 0x42, 0xdf, 0x00, 0x9a, 0x6e, 0xca, 0x94, 0xbd, 0x33, 0x1b, 0x40, 0xa3, 0x3e,
+	0x1b, 0x10, 0x89, 0xde, 0x83, 0xea, 0x64, 0xc3, 0xa4, 0xec, 0x9d, 0xd1, 0x48, 0x4d, 0x17, 0xea,
+	0xd3, 0x3c, 0xbf, 0x39, 0x4f, 0xff, 0x3b, 0x00, 0x3f, 0x3f, 0xac, 0xa9, 0x10, 0x1d, 0x00, 0x00,
 }
@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chunkSize is the largest chunk of a backup or export sent in one message.
+const chunkSize = 1 << 20
+
+// BackupTo writes a backup of every namespace, then every stream, with its
+// retained events and snapshots, to w, returning the number of streams
+// written. The streams are read in a single read transaction, so the backup
+// is consistent while the server is in use. Only requests in the default
+// namespace may back up the store. Errors are returned as grpc statuses.
+func (srv *Server) BackupTo(c context.Context, w io.Writer) (n int, err error) {
+	err = srv.checkDefault(c)
+	if err != nil {
+		return 0, err
+	}
+	bw := schema.NewWriter(w)
+	all, err := srv.DB.ListNamespaces()
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return 0, err
+	}
+	for _, ns := range all {
+		err = bw.Write(&store.Record{Namespace: ns})
+		if err != nil {
+			err = status.Error(codes.Unavailable, err.Error())
+			return 0, err
+		}
+	}
+
+	var werr error
+	err = srv.DB.Backup(func(r *store.Record) error {
+		werr = bw.Write(r)
+		if werr != nil {
+			return werr
+		}
+		n++
+		return nil
+	})
+	if werr == nil && err == nil {
+		werr = bw.Close()
+	}
+	if werr != nil {
+		if _, ok := status.FromError(werr); !ok {
+			werr = status.Error(codes.Unavailable, werr.Error())
+		}
+		return n, werr
+	}
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return n, err
+	}
+	return n, nil
+}
+
+// RestoreFrom restores every namespace and stream in the backup read from r,
+// replacing any stored with the same names, and returns the number of streams
+// restored. Streams restored before an error are kept. Only requests in the
+// default namespace may restore the store. Errors are returned as grpc
+// statuses.
+func (srv *Server) RestoreFrom(c context.Context, r io.Reader) (n int, err error) {
+	err = srv.checkDefault(c)
+	if err != nil {
+		return 0, err
+	}
+	br := schema.NewReader(r)
+	for {
+		rec, err := br.Read()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			if _, ok := status.FromError(err); !ok {
+				err = status.Error(codes.InvalidArgument, err.Error())
+			}
+			return n, err
+		}
+		if rec.Namespace != nil {
+			err = srv.restoreNamespace(rec.Namespace)
+			if err != nil {
+				err = status.Error(codes.Internal, err.Error())
+				return n, err
+			}
+			continue
+		}
+		err = srv.DB.RestoreStream(rec)
+		srv.cache.forget(rec.Stream.Config.Name)
+		if err != nil {
+			err = status.Error(codes.Internal, err.Error())
+			return n, err
+		}
+		n++
+	}
+}
+
+// restoreNamespace creates the namespace, or replaces its quotas if it exists.
+func (srv *Server) restoreNamespace(ns *store.Namespace) (err error) {
+	err = srv.DB.CreateNamespace(ns)
+	if _, ok := err.(*store.AlreadyExistsError); ok {
+		err = srv.DB.UpdateNamespace(ns)
+	}
+	return err
+}
+
+// Backup streams a backup of every stream in the store, in chunks, which can
+// be written to a file and later sent to Restore on any server.
+func (srv *Server) Backup(in *empty.Empty, bs seer.Seer_BackupServer) (err error) {
+	w := bufio.NewWriterSize(&chunkWriter{send: func(data []byte) error {
+		return bs.Send(&seer.BackupChunk{Data: data})
+	}}, chunkSize)
+	_, err = srv.BackupTo(bs.Context(), w)
+	if err != nil {
+		return err
+	}
+	err = w.Flush()
+	if err != nil {
+		err = status.Error(codes.Unavailable, err.Error())
+		return err
+	}
+	return nil
+}
+
+// Restore restores every stream in a backup sent in chunks, replacing any
+// stored streams with the same names.
+func (srv *Server) Restore(rs seer.Seer_RestoreServer) (err error) {
+	n, err := srv.RestoreFrom(rs.Context(), &chunkReader{recv: func() ([]byte, error) {
+		chunk, err := rs.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return chunk.Data, nil
+	}})
+	if err != nil {
+		return err
+	}
+	return rs.SendAndClose(&seer.RestoreResponse{Streams: int64(n)})
+}
+
+// chunkWriter sends writes as chunks of at most chunkSize bytes.
+type chunkWriter struct {
+	send func(data []byte) error
+}
+
+func (c *chunkWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		size := len(p)
+		if size > chunkSize {
+			size = chunkSize
+		}
+		err = c.send(p[:size])
+		if err != nil {
+			return n, err
+		}
+		n += size
+		p = p[size:]
+	}
+	return n, nil
+}
+
+// chunkReader reads the data of received chunks.
+type chunkReader struct {
+	recv func() ([]byte, error)
+	buf  []byte
+}
+
+func (c *chunkReader) Read(p []byte) (n int, err error) {
+	for len(c.buf) == 0 {
+		c.buf, err = c.recv()
+		if err != nil {
+			return 0, err
+		}
+	}
+	n = copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
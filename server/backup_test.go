@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBackupTo(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+	want, _ := srv.DB.GetStream("history")
+
+	buf := &bytes.Buffer{}
+	n, err := srv.BackupTo(context.Background(), buf)
+	if err != nil {
+		t.Fatal("unexpected error in BackupTo:", err)
+	}
+	if n != 4 {
+		t.Errorf("expected %v streams backed up, but there were %v", 4, n)
+	}
+
+	dst, err := server.New(server.Config{Backend: server.Memory})
+	if err != nil {
+		t.Fatal("unexpected error in server.New:", err)
+	}
+	n, err = dst.RestoreFrom(context.Background(), buf)
+	if err != nil {
+		t.Fatal("unexpected error in RestoreFrom:", err)
+	}
+	if n != 4 {
+		t.Errorf("expected %v streams restored, but there were %v", 4, n)
+	}
+
+	st, err := dst.DB.GetStream("history")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if st.Revision != want.Revision {
+		t.Errorf("expected revision %v, but it was %v", want.Revision, st.Revision)
+	}
+	e, _ := dst.DB.GetEvents("history", time.Time{}, time.Time{}, 0)
+	if len(e) != 25 {
+		t.Errorf("expected %v events, but there were %v", 25, len(e))
+	}
+}
+
+func TestRestoreFromErrs(t *testing.T) {
+	srv := setUp(t)
+
+	n, err := srv.RestoreFrom(context.Background(), bytes.NewReader([]byte("not a backup")))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+	}
+	if n != 0 {
+		t.Errorf("expected %v streams restored, but there were %v", 0, n)
+	}
+}
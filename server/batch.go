@@ -0,0 +1,273 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchSize is the most streams a batch request may name or select.
+const maxBatchSize = 1000
+
+// parallel calls fn with each index in [0, n), from at most one goroutine per
+// CPU at a time, and returns once every call has.
+func parallel(n int, fn func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	next := make(chan int)
+	wg := &sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// batchError converts an error to its batch result form.
+func batchError(err error) *seer.BatchError {
+	st, _ := status.FromError(err)
+	return &seer.BatchError{Code: int32(st.Code()), Message: st.Message()}
+}
+
+// batchKeys returns the keys of the streams in the scope that a batch request
+// names, or that match its label selector, in name order.
+func (srv *Server) batchKeys(sc *scope, names []string, selector string) (keys []string, err error) {
+	switch {
+	case len(names) > 0 && selector != "":
+		err = fmt.Errorf("only one of names or label_selector may be set")
+	case len(names) > maxBatchSize:
+		err = fmt.Errorf("at most %v names may be given, but there were %v", maxBatchSize, len(names))
+	case len(names) > 0:
+		keys = make([]string, len(names))
+		for i := range names {
+			keys[i] = sc.key(names[i])
+		}
+		return keys, nil
+	case selector == "":
+		err = fmt.Errorf("one of names or label_selector must be set")
+	}
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	sel, err := label.Parse(selector)
+	if err != nil {
+		err = fmt.Errorf("invalid label_selector: %v", err)
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	lst, total, err := srv.DB.ListStreams(&store.ListOptions{Prefix: sc.prefix, Selector: sel, Limit: maxBatchSize})
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	if total > maxBatchSize {
+		err = fmt.Errorf("label_selector matches %v streams, but at most %v may be batched", total, maxBatchSize)
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	keys = make([]string, len(lst))
+	for i := range lst {
+		keys[i] = lst[i].Config.Name
+	}
+	return keys, nil
+}
+
+// BatchGetForecast generates a forecast, as in GetForecast, for each of the
+// named streams, or each stream matching the label selector. The streams are
+// read from the store at once and forecast concurrently, and a stream that
+// can't be forecast has an error in place of its forecast.
+func (srv *Server) BatchGetForecast(c context.Context, in *seer.BatchGetForecastRequest) (b *seer.BatchGetForecastResponse, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	probs := in.Probabilities
+	if len(probs) == 0 {
+		probs = defaultProbabilities
+	}
+	window := forecastWindow(in.Aggregation, int(in.Window))
+	err = stream.ValidateForecast(int(in.N), stream.Aggregation(in.Aggregation), window, probs)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	keys, err := srv.batchKeys(sc, in.Names, in.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	streams, err := srv.DB.GetStreams(keys)
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	b = &seer.BatchGetForecastResponse{Results: make([]*seer.StreamForecast, len(keys))}
+	parallel(len(keys), func(i int) {
+		res := &seer.StreamForecast{Name: sc.name(keys[i])}
+		b.Results[i] = res
+		if streams[i] == nil {
+			nerr := &store.NotFoundError{Kind: "stream", Entity: keys[i]}
+			res.Error = batchError(status.Error(codes.NotFound, nerr.Error()))
+			return
+		}
+		f, ferr := srv.cachedForecast(keys[i], streams[i], 0, int(in.N), in.Aggregation, window, probs)
+		if ferr != nil {
+			res.Error = batchError(status.Error(codes.InvalidArgument, ferr.Error()))
+			return
+		}
+		res.Forecast = f
+	})
+	return b, nil
+}
+
+// BatchUpdateStreams applies the events of each update to its stream, as in
+// UpdateStream. The streams are read from the store at once, updated
+// concurrently, and saved in a single transaction. An update whose stream is
+// missing or was modified, or whose events are invalid, has an error in place
+// of its stream without failing the others, while any other store error fails
+// the whole batch, with no update applied. The events of each applied update
+// count towards the namespace's points per second quota.
+func (srv *Server) BatchUpdateStreams(c context.Context, in *seer.BatchUpdateStreamsRequest) (b *seer.BatchUpdateStreamsResponse, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(in.Updates) > maxBatchSize {
+		err = fmt.Errorf("at most %v updates may be given, but there were %v", maxBatchSize, len(in.Updates))
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	results := make([]*seer.StreamUpdate, len(in.Updates))
+	b = &seer.BatchUpdateStreamsResponse{Results: results}
+	fail := func(i int, err error) {
+		results[i].Error = batchError(err)
+	}
+
+	// Points are taken before the updates are applied, and refunded for those
+	// that weren't.
+	points := make([]int, len(in.Updates))
+	defer func() {
+		for i, res := range results {
+			if res.Stream == nil {
+				srv.refundPoints(sc, points[i])
+			}
+		}
+	}()
+	var (
+		keys []string
+		idx  []int
+	)
+	seen := make(map[string]bool, len(in.Updates))
+	for i, u := range in.Updates {
+		results[i] = &seer.StreamUpdate{Name: u.Name}
+		key := sc.key(u.Name)
+		if seen[key] {
+			fail(i, status.Errorf(codes.InvalidArgument, "stream %v is updated more than once in the batch", u.Name))
+			continue
+		}
+		seen[key] = true
+		if len(u.Event.GetValues()) == 0 {
+			fail(i, status.Errorf(codes.InvalidArgument, "update of stream %v must have at least one event", u.Name))
+			continue
+		}
+		perr := srv.takePoints(sc, len(u.Event.GetValues()))
+		if perr != nil {
+			fail(i, perr)
+			continue
+		}
+		points[i] = len(u.Event.GetValues())
+		keys = append(keys, key)
+		idx = append(idx, i)
+	}
+
+	streams, err := srv.DB.GetStreams(keys)
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	updates := make([]*store.Update, len(keys))
+	parallel(len(keys), func(j int) {
+		i := idx[j]
+		if streams[j] == nil {
+			nerr := &store.NotFoundError{Kind: "stream", Entity: keys[j]}
+			fail(i, status.Error(codes.NotFound, nerr.Error()))
+			return
+		}
+		ev := in.Updates[i].Event
+		t := eventTimes(ev)
+		uerr := streams[j].Update(ev.Values, t)
+		if uerr != nil {
+			fail(i, status.Error(codes.InvalidArgument, uerr.Error()))
+			return
+		}
+		events, _ := stream.Events(ev.Values, t)
+		updates[j] = &store.Update{Name: keys[j], Stream: streams[j], Events: events}
+	})
+
+	var (
+		valid []*store.Update
+		vidx  []int
+	)
+	for j, u := range updates {
+		if u != nil {
+			valid = append(valid, u)
+			vidx = append(vidx, idx[j])
+		}
+	}
+	errs, err := srv.DB.UpdateStreams(valid)
+	for _, u := range valid {
+		srv.cache.forget(u.Name)
+	}
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	for j, u := range valid {
+		i := vidx[j]
+		if errs[j] != nil {
+			fail(i, status.Error(updateCode(errs[j]), errs[j].Error()))
+			continue
+		}
+		results[i].Stream = sc.proto(u.Stream)
+	}
+	return b, nil
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBatchGetForecast(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	in := &seer.BatchGetForecastRequest{Names: []string{"history", "missing", "sales"}, N: 5, Probabilities: []float64{0.5}}
+	b, err := srv.BatchGetForecast(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in BatchGetForecast:", err)
+	}
+	if len(b.Results) != 3 {
+		t.Fatalf("expected %v results, but got %v", 3, len(b.Results))
+	}
+
+	// Results match forecasts of each stream on its own.
+	want, _ := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 5})
+	have := b.Results[0]
+	if have.Name != "history" || have.Error != nil || len(have.Forecast.Values) != 5 {
+		t.Fatalf("expected forecast of history, but got %v", have)
+	}
+	for i := range want.Values {
+		if want.Values[i] != have.Forecast.Values[i] {
+			t.Errorf("expected forecast %v, but got %v", want.Values, have.Forecast.Values)
+		}
+	}
+	if len(have.Forecast.Intervals) != 1 || have.Forecast.Intervals[0].Probability != 0.5 {
+		t.Errorf("expected the requested interval, but got %v", have.Forecast.Intervals)
+	}
+	if e := b.Results[1].Error; e == nil || codes.Code(e.Code) != codes.NotFound {
+		t.Errorf("expected not found error for missing, but got %v", e)
+	}
+	if b.Results[2].Name != "sales" || b.Results[2].Forecast == nil {
+		t.Errorf("expected forecast of sales, but got %v", b.Results[2])
+	}
+}
+
+func TestBatchGetForecastSelector(t *testing.T) {
+	srv := setUp(t)
+	for _, name := range []string{"signups", "churn", "revenue"} {
+		_, err := srv.CreateStream(context.Background(), &seer.CreateStreamRequest{
+			Stream: &seer.Stream{Name: name, Period: 3600, Labels: map[string]string{"team": "growth"}},
+		})
+		if err != nil {
+			t.Fatal("unexpected error in CreateStream:", err)
+		}
+	}
+
+	in := &seer.BatchGetForecastRequest{LabelSelector: "team=growth", N: 3}
+	b, err := srv.BatchGetForecast(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in BatchGetForecast:", err)
+	}
+	var names []string
+	for _, res := range b.Results {
+		names = append(names, res.Name)
+		if res.Forecast == nil || len(res.Forecast.Intervals) != 3 {
+			t.Errorf("expected forecast with default intervals, but got %v", res)
+		}
+	}
+	if fmt.Sprint(names) != fmt.Sprint([]string{"churn", "revenue", "signups"}) {
+		t.Errorf("expected labelled streams, but got %v", names)
+	}
+}
+
+func TestBatchGetForecastNamespace(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	c := inNamespace("growth")
+	err := createIn(c, srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	b, err := srv.BatchGetForecast(c, &seer.BatchGetForecastRequest{Names: []string{"sales", "visits"}, N: 3})
+	if err != nil {
+		t.Fatal("unexpected error in BatchGetForecast:", err)
+	}
+	if b.Results[0].Name != "sales" || b.Results[0].Forecast == nil {
+		t.Errorf("expected forecast of sales in namespace, but got %v", b.Results[0])
+	}
+	// Streams in the default namespace are not visible.
+	if b.Results[1].Error == nil {
+		t.Errorf("expected error for visits, but got %v", b.Results[1])
+	}
+}
+
+func TestBatchGetForecastErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name string
+		in   *seer.BatchGetForecastRequest
+	}{
+		{"no streams", &seer.BatchGetForecastRequest{N: 3}},
+		{"names and selector", &seer.BatchGetForecastRequest{Names: []string{"sales"}, LabelSelector: "team", N: 3}},
+		{"too many names", &seer.BatchGetForecastRequest{Names: make([]string, 1001), N: 3}},
+		{"bad selector", &seer.BatchGetForecastRequest{LabelSelector: "team in", N: 3}},
+		{"zero length", &seer.BatchGetForecastRequest{Names: []string{"sales"}}},
+		{"bad probabilities", &seer.BatchGetForecastRequest{Names: []string{"sales"}, N: 3, Probabilities: []float64{2}}},
+		{"window without aggregation", &seer.BatchGetForecastRequest{Names: []string{"sales"}, N: 3, Window: 4}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.BatchGetForecast(context.Background(), tc.in)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+			}
+		})
+	}
+}
+
+// hourlyEvent returns an event of n values at hourly times from start.
+func hourlyEvent(start time.Time, n int) *seer.Event {
+	ev := &seer.Event{Values: make([]float64, n), Times: make([]*timestamp.Timestamp, n)}
+	for i := range ev.Times {
+		ev.Values[i] = float64(i)
+		ev.Times[i], _ = ptypes.TimestampProto(start.Add(time.Duration(i) * time.Hour))
+	}
+	return ev
+}
+
+func TestBatchUpdateStreams(t *testing.T) {
+	srv := setUp(t)
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	unordered := hourlyEvent(start, 2)
+	unordered.Times[0], unordered.Times[1] = unordered.Times[1], unordered.Times[0]
+
+	in := &seer.BatchUpdateStreamsRequest{Updates: []*seer.UpdateStreamRequest{
+		{Name: "sales", Event: hourlyEvent(start, 3)},
+		{Name: "missing", Event: hourlyEvent(start, 3)},
+		{Name: "visits", Event: unordered},
+		{Name: "usage", Event: hourlyEvent(start, 1)},
+		{Name: "sales", Event: hourlyEvent(start, 1)},
+		{Name: "signups"},
+	}}
+	b, err := srv.BatchUpdateStreams(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in BatchUpdateStreams:", err)
+	}
+
+	want := []codes.Code{codes.OK, codes.NotFound, codes.InvalidArgument, codes.OK, codes.InvalidArgument, codes.InvalidArgument}
+	for i, res := range b.Results {
+		if res.Name != in.Updates[i].Name {
+			t.Errorf("expected result %v for %v, but it was for %v", i, in.Updates[i].Name, res.Name)
+		}
+		code := codes.OK
+		if res.Error != nil {
+			code = codes.Code(res.Error.Code)
+		}
+		if code != want[i] {
+			t.Errorf("expected code %v for update %v, but got %v", want[i], i, code)
+		}
+		if (res.Stream != nil) != (code == codes.OK) {
+			t.Errorf("expected a stream only for successful update %v, but got %v", i, res.Stream)
+		}
+	}
+
+	// Successful updates are saved.
+	s, _ := srv.DB.GetStream("sales")
+	if s.Revision != 1 || !s.Time.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected sales at revision 1, but got revision %v at %v", s.Revision, s.Time)
+	}
+	s, _ = srv.DB.GetStream("visits")
+	if s.Revision != 0 {
+		t.Errorf("expected visits to be unchanged, but it was at revision %v", s.Revision)
+	}
+}
+
+func TestBatchUpdateStreamsNamespace(t *testing.T) {
+	srv := setUpNamespace(t, 0, 10)
+	c := inNamespace("growth")
+	for _, name := range []string{"sales", "visits"} {
+		err := createIn(c, srv, name)
+		if err != nil {
+			t.Fatal("unexpected error in CreateStream:", err)
+		}
+	}
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	in := &seer.BatchUpdateStreamsRequest{Updates: []*seer.UpdateStreamRequest{
+		{Name: "sales", Event: hourlyEvent(start, 6)},
+		{Name: "visits", Event: hourlyEvent(start, 6)},
+	}}
+	b, err := srv.BatchUpdateStreams(c, in)
+	if err != nil {
+		t.Fatal("unexpected error in BatchUpdateStreams:", err)
+	}
+	if b.Results[0].Error != nil || b.Results[0].Stream.Name != "sales" {
+		t.Errorf("expected sales in namespace to update, but got %v", b.Results[0])
+	}
+	if e := b.Results[1].Error; e == nil || codes.Code(e.Code) != codes.ResourceExhausted {
+		t.Errorf("expected visits to exceed the points quota, but got %v", e)
+	}
+}
+
+func TestBatchUpdateStreamsRefund(t *testing.T) {
+	srv := setUpNamespace(t, 0, 10)
+	c := inNamespace("growth")
+	err := createIn(c, srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The missing stream's points are refunded, leaving room for sales.
+	in := &seer.BatchUpdateStreamsRequest{Updates: []*seer.UpdateStreamRequest{
+		{Name: "missing", Event: hourlyEvent(start, 6)},
+	}}
+	b, err := srv.BatchUpdateStreams(c, in)
+	if err != nil {
+		t.Fatal("unexpected error in BatchUpdateStreams:", err)
+	}
+	if e := b.Results[0].Error; e == nil || codes.Code(e.Code) != codes.NotFound {
+		t.Errorf("expected missing stream not to be found, but got %v", e)
+	}
+	in.Updates[0].Name = "sales"
+	b, err = srv.BatchUpdateStreams(c, in)
+	if err != nil {
+		t.Fatal("unexpected error in BatchUpdateStreams:", err)
+	}
+	if b.Results[0].Error != nil {
+		t.Errorf("expected sales to update, but got %v", b.Results[0].Error)
+	}
+}
+
+func TestBatchUpdateStreamsErrs(t *testing.T) {
+	srv := setUp(t)
+
+	in := &seer.BatchUpdateStreamsRequest{Updates: make([]*seer.UpdateStreamRequest, 1001)}
+	_, err := srv.BatchUpdateStreams(context.Background(), in)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+	}
+	_, err = srv.BatchUpdateStreams(inNamespace("missing"), &seer.BatchUpdateStreamsRequest{})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+}
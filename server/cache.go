@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"container/list"
+	"expvar"
+	"fmt"
+	"sync"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/stream"
+)
+
+// cacheStats counts forecast cache hits, misses and evictions. They are
+// published with expvar as forecast_cache.
+var cacheStats = expvar.NewMap("forecast_cache")
+
+// forecastKey identifies a forecast, by the stream's key, revision and last
+// event time, and the forecast requested, including the periods it skips. A
+// stream's revision and time change with every update, so forecasts of earlier
+// states are never returned.
+type forecastKey struct {
+	name     string
+	revision uint64
+	time     int64
+	skip     int
+	n        int
+	agg      seer.Aggregation
+	window   int
+	probs    string
+}
+
+// newForecastKey returns the key of a forecast of the stream stored under
+// name.
+func newForecastKey(name string, st *stream.Stream, skip, n int, agg seer.Aggregation, window int, probs []float64) forecastKey {
+	return forecastKey{
+		name:     name,
+		revision: st.Revision,
+		time:     st.Time.UnixNano(),
+		skip:     skip,
+		n:        n,
+		agg:      agg,
+		window:   window,
+		probs:    fmt.Sprint(probs),
+	}
+}
+
+// cacheEntry is a cached forecast, and its key.
+type cacheEntry struct {
+	key forecastKey
+	f   *seer.Forecast
+}
+
+// forecastCache is a least recently used cache of forecasts, holding at most
+// size forecasts. A nil cache holds none. Cached forecasts are shared, so must
+// not be modified.
+type forecastCache struct {
+	mu     sync.Mutex
+	size   int
+	order  *list.List
+	byName map[string]map[forecastKey]*list.Element
+}
+
+// newForecastCache returns a cache of size forecasts, or nil if size is not
+// positive.
+func newForecastCache(size int) *forecastCache {
+	if size <= 0 {
+		return nil
+	}
+	return &forecastCache{
+		size:   size,
+		order:  list.New(),
+		byName: make(map[string]map[forecastKey]*list.Element),
+	}
+}
+
+// get returns the cached forecast with the key, or nil if there is none.
+func (c *forecastCache) get(k forecastKey) (f *seer.Forecast) {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.byName[k.name][k]
+	if !ok {
+		cacheStats.Add("misses", 1)
+		return nil
+	}
+	cacheStats.Add("hits", 1)
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).f
+}
+
+// put caches the forecast with the key, evicting the least recently used
+// forecast if the cache is full.
+func (c *forecastCache) put(k forecastKey, f *seer.Forecast) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.byName[k.name][k]; ok {
+		el.Value.(*cacheEntry).f = f
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.order.Len() >= c.size {
+		c.remove(c.order.Back())
+		cacheStats.Add("evictions", 1)
+	}
+	if c.byName[k.name] == nil {
+		c.byName[k.name] = make(map[forecastKey]*list.Element)
+	}
+	c.byName[k.name][k] = c.order.PushFront(&cacheEntry{key: k, f: f})
+}
+
+// forget removes every cached forecast of the stream stored under name.
+func (c *forecastCache) forget(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, el := range c.byName[name] {
+		c.remove(el)
+	}
+}
+
+// remove removes the element from the cache. Callers must hold the lock.
+func (c *forecastCache) remove(el *list.Element) {
+	k := el.Value.(*cacheEntry).key
+	c.order.Remove(el)
+	delete(c.byName[k.name], k)
+	if len(c.byName[k.name]) == 0 {
+		delete(c.byName, k.name)
+	}
+}
+
+// cachedForecast returns the forecast of the stream stored under name, as
+// forecast does, from the server's cache if it holds it.
+func (srv *Server) cachedForecast(name string, st *stream.Stream, skip, n int, agg seer.Aggregation, window int, probs []float64) (f *seer.Forecast, err error) {
+	k := newForecastKey(name, st, skip, n, agg, window, probs)
+	f = srv.cache.get(k)
+	if f != nil {
+		return f, nil
+	}
+	f, err = forecast(st, skip, n, agg, window, probs)
+	if err != nil {
+		return nil, err
+	}
+	srv.cache.put(k, f)
+	return f, nil
+}
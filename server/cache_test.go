@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/server"
+)
+
+// cacheCount returns the forecast cache's count of the named metric.
+func cacheCount(name string) int64 {
+	v := expvar.Get("forecast_cache").(*expvar.Map).Get(name)
+	if v == nil {
+		return 0
+	}
+	return v.(*expvar.Int).Value()
+}
+
+func setUpCache(t *testing.T, size int) (srv *server.Server) {
+	srv, err := server.New(server.Config{Path: testPath(t), ForecastCacheSize: size})
+	if err != nil {
+		t.Fatal("unexpected error in server.New:", err)
+	}
+	historyStream(t, srv, 25)
+	return srv
+}
+
+func TestForecastCache(t *testing.T) {
+	srv := setUpCache(t, 2)
+	in := &seer.GetForecastRequest{Name: "history", N: 5}
+
+	hits, misses := cacheCount("hits"), cacheCount("misses")
+	first, err := srv.GetForecast(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in GetForecast:", err)
+	}
+	second, _ := srv.GetForecast(context.Background(), in)
+	if second != first {
+		t.Error("expected the second forecast to be cached, but it was not")
+	}
+	if cacheCount("hits") != hits+1 || cacheCount("misses") != misses+1 {
+		t.Errorf("expected %v hit and %v miss, but got %v and %v", 1, 1, cacheCount("hits")-hits, cacheCount("misses")-misses)
+	}
+
+	// Forecasts with other parameters are cached apart.
+	other, _ := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 3})
+	if len(other.Values) != 3 {
+		t.Errorf("expected %v values, but got %v", 3, len(other.Values))
+	}
+
+	// Updates invalidate the stream's forecasts.
+	_, err = srv.UpdateStream(context.Background(), &seer.UpdateStreamRequest{
+		Name:  "history",
+		Event: hourlyEvent(time.Date(2016, 1, 2, 1, 0, 0, 0, time.UTC), 1),
+	})
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	third, _ := srv.GetForecast(context.Background(), in)
+	if third == first || third.Times[0].Seconds == first.Times[0].Seconds {
+		t.Errorf("expected a forecast from the updated stream, but got %v", third.Times[0])
+	}
+}
+
+func TestForecastCacheEviction(t *testing.T) {
+	srv := setUpCache(t, 1)
+	evictions := cacheCount("evictions")
+
+	a, _ := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 5})
+	srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 3})
+	b, _ := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 5})
+	if a == b {
+		t.Error("expected the least recently used forecast to be evicted, but it was cached")
+	}
+	if cacheCount("evictions") != evictions+2 {
+		t.Errorf("expected %v evictions, but got %v", 2, cacheCount("evictions")-evictions)
+	}
+}
+
+func TestForecastCacheDelete(t *testing.T) {
+	srv := setUpCache(t, 10)
+	in := &seer.GetForecastRequest{Name: "history", N: 5}
+	first, _ := srv.GetForecast(context.Background(), in)
+
+	// A stream recreated at the same revision and time is forecast afresh.
+	_, err := srv.DeleteStream(context.Background(), &seer.DeleteStreamRequest{Name: "history"})
+	if err != nil {
+		t.Fatal("unexpected error in DeleteStream:", err)
+	}
+	historyStream(t, srv, 25)
+	second, _ := srv.GetForecast(context.Background(), in)
+	if second == first {
+		t.Error("expected the deleted stream's forecast to be forgotten, but it was cached")
+	}
+}
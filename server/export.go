@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cshenton/seer/export"
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Export writes the configuration, last event time and model of every stream
+// in the request's namespace, and with history its retained events, to w in
+// the export format, returning the number of streams written. Errors are
+// returned as grpc statuses.
+func (srv *Server) Export(c context.Context, w io.Writer, format string, history bool) (n int, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return 0, err
+	}
+	ew, err := export.NewWriter(w, format)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return 0, err
+	}
+
+	var werr error
+	err = srv.DB.Backup(func(r *store.Record) error {
+		if !strings.HasPrefix(r.Stream.Config.Name, sc.prefix) {
+			return nil
+		}
+		var events []*stream.Event
+		if history {
+			events = r.Events
+		}
+		s := export.New(r.Stream, events)
+		s.Name = sc.name(s.Name)
+		werr = ew.Write(s)
+		if werr != nil {
+			return werr
+		}
+		n++
+		return nil
+	})
+	if werr == nil && err == nil {
+		werr = ew.Flush()
+	}
+	if werr != nil {
+		if _, ok := status.FromError(werr); !ok {
+			werr = status.Error(codes.Unavailable, werr.Error())
+		}
+		return n, werr
+	}
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return n, err
+	}
+	return n, nil
+}
+
+// Import creates the streams read from r in the export format in the
+// request's namespace, with their events retained if the stream has a
+// retention policy, and returns the number of streams created. Streams created
+// before an error are kept. Errors are returned as grpc statuses.
+func (srv *Server) Import(c context.Context, r io.Reader, format string) (n int, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return 0, err
+	}
+	er, err := export.NewReader(r, format)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return 0, err
+	}
+
+	for {
+		s, err := er.Read()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			if _, ok := status.FromError(err); !ok {
+				err = status.Error(codes.InvalidArgument, err.Error())
+			}
+			return n, err
+		}
+		st, events, err := s.Stream()
+		if err != nil {
+			err = status.Error(codes.InvalidArgument, fmt.Sprintf("stream %v: %v", s.Name, err))
+			return n, err
+		}
+		st.Config.Name = sc.key(s.Name)
+		err = srv.createStream(sc, st)
+		if err != nil {
+			return n, err
+		}
+		if len(events) > 0 && st.Config.Retention != nil {
+			err = srv.DB.UpdateStream(st.Config.Name, st, events...)
+			if err != nil {
+				err = status.Error(updateCode(err), err.Error())
+				return n, err
+			}
+		}
+		n++
+	}
+}
+
+// exportFormat returns the export format name of the requested format.
+func exportFormat(f seer.ExportFormat) string {
+	return strings.ToLower(f.String())
+}
+
+// ExportStreams streams the configuration, last event time and model of every
+// stream in the request's namespace, and optionally its retained events, in
+// chunks.
+func (srv *Server) ExportStreams(in *seer.ExportStreamsRequest, es seer.Seer_ExportStreamsServer) (err error) {
+	w := bufio.NewWriterSize(&chunkWriter{send: func(data []byte) error {
+		return es.Send(&seer.ExportChunk{Data: data})
+	}}, chunkSize)
+	_, err = srv.Export(es.Context(), w, exportFormat(in.Format), in.History)
+	if err != nil {
+		return err
+	}
+	err = w.Flush()
+	if err != nil {
+		err = status.Error(codes.Unavailable, err.Error())
+		return err
+	}
+	return nil
+}
+
+// ImportStreams creates the streams sent in chunks, in the format of the
+// first chunk, in the request's namespace.
+func (srv *Server) ImportStreams(is seer.Seer_ImportStreamsServer) (err error) {
+	first, err := is.Recv()
+	if err == io.EOF {
+		first = &seer.ImportStreamsRequest{}
+	} else if err != nil {
+		return err
+	}
+
+	n, err := srv.Import(is.Context(), &chunkReader{
+		recv: func() ([]byte, error) {
+			in, err := is.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return in.Data, nil
+		},
+		buf: first.Data,
+	}, exportFormat(first.Format))
+	if err != nil {
+		return err
+	}
+	return is.SendAndClose(&seer.ImportStreamsResponse{Streams: int64(n)})
+}
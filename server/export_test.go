@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/export"
+	"github.com/cshenton/seer/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExportImport(t *testing.T) {
+	tt := []struct {
+		name    string
+		format  string
+		history bool
+		events  int
+	}{
+		{"json", export.JSON, false, 0},
+		{"json with history", export.JSON, true, 25},
+		{"csv", export.CSV, false, 0},
+		{"csv with history", export.CSV, true, 25},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := setUp(t)
+			historyStream(t, srv, 25)
+			want, _ := srv.DB.GetStream("history")
+
+			buf := &bytes.Buffer{}
+			n, err := srv.Export(context.Background(), buf, tc.format, tc.history)
+			if err != nil {
+				t.Fatal("unexpected error in Export:", err)
+			}
+			if n != 4 {
+				t.Errorf("expected %v streams exported, but there were %v", 4, n)
+			}
+
+			dst, _ := server.New(server.Config{Backend: server.Memory})
+			n, err = dst.Import(context.Background(), buf, tc.format)
+			if err != nil {
+				t.Fatal("unexpected error in Import:", err)
+			}
+			if n != 4 {
+				t.Errorf("expected %v streams imported, but there were %v", 4, n)
+			}
+
+			st, err := dst.DB.GetStream("history")
+			if err != nil {
+				t.Fatal("unexpected error in GetStream:", err)
+			}
+			if !st.Time.Equal(want.Time) {
+				t.Errorf("expected last event time %v, but got %v", want.Time, st.Time)
+			}
+			if st.Config.Retention == nil || st.Config.Retention.Count != 1000 {
+				t.Errorf("expected retention policy, but got %v", st.Config.Retention)
+			}
+			e, _ := dst.DB.GetEvents("history", time.Time{}, time.Time{}, 0)
+			if len(e) != tc.events {
+				t.Errorf("expected %v events, but there were %v", tc.events, len(e))
+			}
+		})
+	}
+}
+
+func TestImportErrs(t *testing.T) {
+	tt := []struct {
+		name   string
+		format string
+		data   string
+		code   codes.Code
+	}{
+		{"bad format", "xml", "", codes.InvalidArgument},
+		{"bad data", export.JSON, "not json", codes.InvalidArgument},
+		{"bad config", export.CSV, "name,period\ntest,-1\n", codes.InvalidArgument},
+		{"existing stream", export.CSV, "name,period\nsales,3600\n", codes.AlreadyExists},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := setUp(t)
+			_, err := srv.Import(context.Background(), strings.NewReader(tc.data), tc.format)
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// janitorStats counts the janitor's sweeps, the streams it expired and
+// archived, and the streams it failed to expire. They are published with
+// expvar as janitor.
+var janitorStats = expvar.NewMap("janitor")
+
+// expiredPageSize is the number of streams read at a time when looking for
+// expired streams.
+const expiredPageSize = 1000
+
+// expired returns the streams with the prefix that have expired at now, in
+// name order, without their models.
+func (srv *Server) expired(prefix string, now time.Time) (s []*stream.Stream, err error) {
+	opts := &store.ListOptions{Prefix: prefix, Limit: expiredPageSize}
+	for {
+		lst, _, err := srv.DB.ListStreams(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range lst {
+			if st.Expired(now) {
+				s = append(s, st)
+			}
+		}
+		if len(lst) < expiredPageSize {
+			return s, nil
+		}
+		opts.After = store.CursorOf(lst[len(lst)-1])
+	}
+}
+
+// Sweep deletes every stream that has expired at now, and returns the number
+// deleted. If archive is set, each stream is first written, with its events
+// and snapshots, to a backup file in that directory, which can be restored.
+// A stream updated after it was found to be expired is kept. Sweep continues
+// past streams it fails to expire, and returns the first such error.
+func (srv *Server) Sweep(now time.Time, archive string) (n int, err error) {
+	janitorStats.Add("sweeps", 1)
+	lst, err := srv.expired("", now)
+	if err != nil {
+		janitorStats.Add("errors", 1)
+		return 0, err
+	}
+	for _, st := range lst {
+		ok, serr := srv.expire(st.Config.Name, now, archive)
+		if serr != nil {
+			janitorStats.Add("errors", 1)
+			if err == nil {
+				err = serr
+			}
+			continue
+		}
+		if ok {
+			janitorStats.Add("expired", 1)
+			n++
+		}
+	}
+	return n, err
+}
+
+// expire archives, if archive is set, and deletes the named stream if it is
+// still expired at now, and reports whether it was deleted.
+func (srv *Server) expire(name string, now time.Time, archive string) (ok bool, err error) {
+	r, err := srv.DB.GetRecord(name)
+	if _, missing := err.(*store.NotFoundError); missing {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !r.Stream.Expired(now) {
+		return false, nil
+	}
+	if archive != "" {
+		err = archiveRecord(r, now, archive)
+		if err != nil {
+			return false, err
+		}
+		janitorStats.Add("archived", 1)
+	}
+
+	err = srv.DB.ExpireStream(name, r.Stream.Revision)
+	srv.cache.forget(name)
+	switch err.(type) {
+	case nil:
+		return true, nil
+	case *store.NotFoundError, *store.ConflictError:
+		return false, nil
+	}
+	return false, err
+}
+
+// archiveRecord writes the record to a backup file in the archive directory,
+// named for its stream and the time it expired.
+func archiveRecord(r *store.Record, now time.Time, archive string) (err error) {
+	name := fmt.Sprintf("%v.%v.backup", url.PathEscape(r.Stream.Config.Name), now.UTC().Format("20060102T150405Z"))
+	f, err := os.Create(filepath.Join(archive, name))
+	if err != nil {
+		return err
+	}
+	w := schema.NewWriter(f)
+	err = w.Write(r)
+	if err == nil {
+		err = w.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ListExpiredStreams lists the streams with the prefix that a janitor sweep
+// at the requested time would delete, without deleting them.
+func (srv *Server) ListExpiredStreams(c context.Context, in *seer.ListExpiredStreamsRequest) (s *seer.ListExpiredStreamsResponse, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if in.AsOf != nil {
+		now, err = ptypes.Timestamp(in.AsOf)
+		if err != nil {
+			err = status.Error(codes.InvalidArgument, err.Error())
+			return nil, err
+		}
+	}
+
+	lst, err := srv.expired(sc.key(in.Prefix), now)
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	s = &seer.ListExpiredStreamsResponse{Streams: make([]*seer.Stream, len(lst))}
+	for i := range lst {
+		s.Streams[i] = sc.proto(lst[i])
+	}
+	return s, nil
+}
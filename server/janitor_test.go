@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/server"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// lastEvent is the time of the last event in a history stream of 25 events.
+var lastEvent = time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+
+// expiringStream gives the history stream a ttl of a day.
+func expiringStream(t *testing.T, srv *server.Server) {
+	historyStream(t, srv, 25)
+	s, _ := srv.DB.GetStream("history")
+	s.Config.TTL = 24 * time.Hour
+	err := srv.DB.UpdateStream("history", s)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+}
+
+func TestCreateStreamTTL(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name string
+		ttl  time.Duration
+		code codes.Code
+	}{
+		{"with ttl", time.Hour, codes.OK},
+		{"negative ttl", -time.Hour, codes.InvalidArgument},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &seer.CreateStreamRequest{
+				Stream: &seer.Stream{Name: "ephemeral", Period: 3600, Ttl: ptypes.DurationProto(tc.ttl)},
+			}
+			s, err := srv.CreateStream(context.Background(), in)
+			if status.Code(err) != tc.code {
+				t.Fatalf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+			if err != nil {
+				return
+			}
+			ttl, _ := ptypes.Duration(s.Ttl)
+			if ttl != tc.ttl {
+				t.Errorf("expected ttl %v, but got %v", tc.ttl, ttl)
+			}
+		})
+	}
+}
+
+func TestListExpiredStreams(t *testing.T) {
+	srv := setUp(t)
+	expiringStream(t, srv)
+
+	tt := []struct {
+		name  string
+		asOf  time.Time
+		count int
+	}{
+		{"before ttl", lastEvent.Add(time.Hour), 0},
+		{"after ttl", lastEvent.Add(25 * time.Hour), 1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			asOf, _ := ptypes.TimestampProto(tc.asOf)
+			s, err := srv.ListExpiredStreams(context.Background(), &seer.ListExpiredStreamsRequest{AsOf: asOf})
+			if err != nil {
+				t.Fatal("unexpected error in ListExpiredStreams:", err)
+			}
+			if len(s.Streams) != tc.count {
+				t.Errorf("expected %v streams, but got %v", tc.count, len(s.Streams))
+			}
+		})
+	}
+
+	// Listing does not delete streams.
+	_, err := srv.DB.GetStream("history")
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
+
+func TestListExpiredStreamsNamespace(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	expiringStream(t, srv)
+
+	asOf, _ := ptypes.TimestampProto(lastEvent.Add(25 * time.Hour))
+	s, err := srv.ListExpiredStreams(inNamespace("growth"), &seer.ListExpiredStreamsRequest{AsOf: asOf})
+	if err != nil {
+		t.Fatal("unexpected error in ListExpiredStreams:", err)
+	}
+	if len(s.Streams) != 0 {
+		t.Errorf("expected no streams in namespace, but got %v", s.Streams)
+	}
+	_, err = srv.ListExpiredStreams(inNamespace("missing"), &seer.ListExpiredStreamsRequest{})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestSweep(t *testing.T) {
+	srv := setUp(t)
+	expiringStream(t, srv)
+
+	n, err := srv.Sweep(lastEvent.Add(time.Hour), "")
+	if err != nil || n != 0 {
+		t.Errorf("expected no streams to expire, but %v did with error %v", n, err)
+	}
+	n, err = srv.Sweep(lastEvent.Add(25*time.Hour), "")
+	if err != nil {
+		t.Fatal("unexpected error in Sweep:", err)
+	}
+	if n != 1 {
+		t.Errorf("expected %v stream to expire, but %v did", 1, n)
+	}
+	_, err = srv.DB.GetStream("history")
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+
+	// Streams without a ttl never expire.
+	_, err = srv.DB.GetStream("sales")
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
+
+func TestSweepArchive(t *testing.T) {
+	srv := setUp(t)
+	expiringStream(t, srv)
+	dir, err := ioutil.TempDir(os.TempDir(), "archive")
+	if err != nil {
+		t.Fatal("failed to create archive dir")
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = srv.Sweep(lastEvent.Add(25*time.Hour), dir)
+	if err != nil {
+		t.Fatal("unexpected error in Sweep:", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "history.*.backup"))
+	if len(files) != 1 {
+		t.Fatalf("expected %v archive, but there were %v", 1, len(files))
+	}
+
+	// Archives restore the stream with its events.
+	f, _ := os.Open(files[0])
+	defer f.Close()
+	n, err := srv.RestoreFrom(context.Background(), f)
+	if err != nil || n != 1 {
+		t.Fatalf("expected %v stream restored, but got %v with error %v", 1, n, err)
+	}
+	ev, err := srv.GetEvents(context.Background(), &seer.GetEventsRequest{Name: "history"})
+	if err != nil {
+		t.Fatal("unexpected error in GetEvents:", err)
+	}
+	if len(ev.Event.Values) != 25 {
+		t.Errorf("expected %v events, but got %v", 25, len(ev.Event.Values))
+	}
+}
+
+func TestSweepArchiveErrs(t *testing.T) {
+	srv := setUp(t)
+	expiringStream(t, srv)
+
+	_, err := srv.Sweep(lastEvent.Add(25*time.Hour), filepath.Join(os.TempDir(), "notadir", "archive"))
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+
+	// Streams that fail to archive are kept.
+	_, err = srv.DB.GetStream("history")
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
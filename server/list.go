@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+)
+
+// Page sizes for ListStreams, the default is used if no page size is given.
+const (
+	defaultStreamPageSize = 100
+	maxStreamPageSize     = 1000
+)
+
+// pageToken is the decoded form of a ListStreams page token, the request it
+// continues and the position of the last stream listed.
+type pageToken struct {
+	Prefix     string      `json:"p,omitempty"`
+	Selector   string      `json:"s,omitempty"`
+	Order      store.Order `json:"o,omitempty"`
+	Descending bool        `json:"d,omitempty"`
+	Name       string      `json:"n"`
+	Time       time.Time   `json:"t"`
+}
+
+// listOptions returns the store list options of the request, for streams in
+// the namespace with the prefix, without a limit.
+func listOptions(in *seer.ListStreamsRequest, namespace string) (opts *store.ListOptions, err error) {
+	opts = &store.ListOptions{
+		Prefix:     namespace + in.Prefix,
+		Order:      store.Order(in.Order),
+		Descending: in.Descending,
+	}
+	if opts.Order != store.ByName && opts.Order != store.ByLastEvent {
+		err = fmt.Errorf("order must be one of %v or %v, but was %v", seer.ListOrder_BY_NAME, seer.ListOrder_BY_LAST_EVENT_TIME, in.Order)
+		return nil, err
+	}
+	opts.Selector, err = label.Parse(in.LabelSelector)
+	if err != nil {
+		err = fmt.Errorf("invalid label_selector: %v", err)
+		return nil, err
+	}
+	if in.PageToken == "" {
+		return opts, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(in.PageToken)
+	tok := &pageToken{}
+	if err == nil {
+		err = json.Unmarshal(data, tok)
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid page_token %q", in.PageToken)
+		return nil, err
+	}
+	if tok.Prefix != opts.Prefix || tok.Selector != opts.Selector.String() || tok.Order != opts.Order || tok.Descending != opts.Descending {
+		err = fmt.Errorf("page_token is for a different prefix, label_selector or order")
+		return nil, err
+	}
+	opts.After = &store.Cursor{Name: tok.Name, Time: tok.Time}
+	return opts, nil
+}
+
+// nextPageToken returns the token for the page following the cursor.
+func nextPageToken(opts *store.ListOptions, c *store.Cursor) string {
+	data, _ := json.Marshal(&pageToken{
+		Prefix:     opts.Prefix,
+		Selector:   opts.Selector.String(),
+		Order:      opts.Order,
+		Descending: opts.Descending,
+		Name:       c.Name,
+		Time:       c.Time,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
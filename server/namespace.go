@@ -0,0 +1,314 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// namespaceKey is the metadata key that makes a stream request in a namespace.
+const namespaceKey = "seer-namespace"
+
+// namespacePattern matches valid namespace names, which are at most 63
+// lowercase alphanumerics or '-', beginning and ending with an alphanumeric.
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// scope is the namespace a stream request is made in, nil for the default
+// namespace, and the prefix of the names its streams are stored under.
+type scope struct {
+	ns     *store.Namespace
+	prefix string
+}
+
+// scopeOf returns the scope of a stream request, from its metadata, or a
+// NotFound status if its namespace does not exist.
+func (srv *Server) scopeOf(c context.Context) (sc *scope, err error) {
+	sc = &scope{}
+	md, _ := metadata.FromIncomingContext(c)
+	names := md[namespaceKey]
+	if len(names) == 0 || names[0] == "" {
+		return sc, nil
+	}
+	sc.ns, err = srv.DB.GetNamespace(names[0])
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	sc.prefix = sc.ns.Name + "/"
+	return sc, nil
+}
+
+// key returns the name the named stream is stored under.
+func (sc *scope) key(name string) string {
+	return sc.prefix + name
+}
+
+// name returns the name of the stream stored under key, without the namespace
+// prefix.
+func (sc *scope) name(key string) string {
+	return strings.TrimPrefix(key, sc.prefix)
+}
+
+// proto converts a stream in the scope to its protocol buffer message, named
+// without the namespace prefix.
+func (sc *scope) proto(st *stream.Stream) (s *seer.Stream) {
+	s = streamProto(st)
+	s.Name = sc.name(s.Name)
+	return s
+}
+
+// createStream creates a stream in the scope. Creates are serialised with
+// each other and with namespace changes, so that a namespace's stream quota
+// holds, and streams in the default namespace are not named as if in another.
+func (srv *Server) createStream(sc *scope, st *stream.Stream) (err error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	name := st.Config.Name
+	err = srv.checkName(sc, name)
+	if err != nil {
+		return err
+	}
+	err = srv.checkQuota(sc)
+	if err != nil {
+		return err
+	}
+	err = srv.DB.CreateStream(name, st)
+	srv.cache.forget(name)
+	if err != nil {
+		err = status.Error(codes.AlreadyExists, err.Error())
+		return err
+	}
+	return nil
+}
+
+// checkDefault returns a PermissionDenied status if the request is not in the
+// default namespace, for requests on the whole store.
+func (srv *Server) checkDefault(c context.Context) (err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return err
+	}
+	if sc.ns != nil {
+		err = status.Errorf(codes.PermissionDenied, "namespace %v can't access the whole store, so only requests in the default namespace may", sc.ns.Name)
+		return err
+	}
+	return nil
+}
+
+// checkName returns an InvalidArgument status if a stream in the scope can't
+// be stored at name, because the scope is the default namespace and name is in
+// another. Callers must hold srv.mu.
+func (srv *Server) checkName(sc *scope, name string) (err error) {
+	if sc.ns != nil {
+		return nil
+	}
+	if i := strings.Index(name, "/"); i >= 0 {
+		if _, err := srv.DB.GetNamespace(name[:i]); err == nil {
+			err = status.Errorf(codes.InvalidArgument, "stream name %v is in namespace %v, so only requests in it may use it", name, name[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// checkQuota returns a ResourceExhausted status if the scope's namespace has
+// no room for another stream. Callers must hold srv.mu.
+func (srv *Server) checkQuota(sc *scope) (err error) {
+	if sc.ns == nil || sc.ns.MaxStreams <= 0 {
+		return nil
+	}
+	n, err := srv.countStreams(sc.prefix)
+	if err != nil {
+		return err
+	}
+	if n >= sc.ns.MaxStreams {
+		err = status.Errorf(codes.ResourceExhausted, "namespace %v is limited to %v streams", sc.ns.Name, sc.ns.MaxStreams)
+		return err
+	}
+	return nil
+}
+
+// countStreams returns the number of streams with names beginning with the
+// prefix.
+func (srv *Server) countStreams(prefix string) (n int, err error) {
+	_, n, err = srv.DB.ListStreams(&store.ListOptions{Prefix: prefix, Limit: 1})
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return 0, err
+	}
+	return n, nil
+}
+
+// takePoints spends n points of the scope's points per second quota, or
+// returns a ResourceExhausted status if its namespace has sent too many.
+func (srv *Server) takePoints(sc *scope, n int) (err error) {
+	if sc.ns == nil || sc.ns.MaxPointsPerSecond <= 0 {
+		return nil
+	}
+	if !srv.limits.take(sc.ns.Name, sc.ns.MaxPointsPerSecond, n) {
+		err = status.Errorf(codes.ResourceExhausted, "namespace %v is limited to %v points per second", sc.ns.Name, sc.ns.MaxPointsPerSecond)
+		return err
+	}
+	return nil
+}
+
+// refundPoints returns n points taken with takePoints to the scope's quota,
+// for updates that weren't applied.
+func (srv *Server) refundPoints(sc *scope, n int) {
+	if sc.ns == nil || sc.ns.MaxPointsPerSecond <= 0 || n == 0 {
+		return
+	}
+	srv.limits.give(sc.ns.Name, sc.ns.MaxPointsPerSecond, n)
+}
+
+// namespaceFromProto validates a namespace message and converts it.
+func namespaceFromProto(in *seer.Namespace) (ns *store.Namespace, err error) {
+	if in == nil {
+		err = fmt.Errorf("namespace must be set")
+		return nil, err
+	}
+	if !namespacePattern.MatchString(in.Name) {
+		err = fmt.Errorf("namespace name %q is invalid, it must be at most 63 lowercase alphanumerics or '-', beginning and ending with an alphanumeric", in.Name)
+		return nil, err
+	}
+	if in.MaxStreams < 0 || in.MaxPointsPerSecond < 0 {
+		err = fmt.Errorf("namespace quotas must not be negative")
+		return nil, err
+	}
+	ns = &store.Namespace{
+		Name:               in.Name,
+		MaxStreams:         int(in.MaxStreams),
+		MaxPointsPerSecond: in.MaxPointsPerSecond,
+	}
+	return ns, nil
+}
+
+// namespaceProto converts a namespace to its protocol buffer message.
+func namespaceProto(ns *store.Namespace) (n *seer.Namespace) {
+	return &seer.Namespace{
+		Name:               ns.Name,
+		MaxStreams:         int64(ns.MaxStreams),
+		MaxPointsPerSecond: ns.MaxPointsPerSecond,
+	}
+}
+
+// CreateNamespace creates the provided namespace. It fails if streams in the
+// default namespace are already named as if in it.
+func (srv *Server) CreateNamespace(c context.Context, in *seer.CreateNamespaceRequest) (n *seer.Namespace, err error) {
+	ns, err := namespaceFromProto(in.Namespace)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if _, err := srv.DB.GetNamespace(ns.Name); err == nil {
+		err = status.Errorf(codes.AlreadyExists, "namespace %v already exists", ns.Name)
+		return nil, err
+	}
+	count, err := srv.countStreams(ns.Name + "/")
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		err = status.Errorf(codes.FailedPrecondition, "%v streams are already named %v/..., so must be renamed or deleted first", count, ns.Name)
+		return nil, err
+	}
+	err = srv.DB.CreateNamespace(ns)
+	if err != nil {
+		err = status.Error(codes.AlreadyExists, err.Error())
+		return nil, err
+	}
+	return namespaceProto(ns), nil
+}
+
+// GetNamespace returns the requested namespace.
+func (srv *Server) GetNamespace(c context.Context, in *seer.GetNamespaceRequest) (n *seer.Namespace, err error) {
+	ns, err := srv.DB.GetNamespace(in.Name)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	return namespaceProto(ns), nil
+}
+
+// UpdateNamespace replaces the quotas of a namespace. Lowering a stream quota
+// below the namespace's number of streams only prevents new streams.
+func (srv *Server) UpdateNamespace(c context.Context, in *seer.UpdateNamespaceRequest) (n *seer.Namespace, err error) {
+	ns, err := namespaceFromProto(in.Namespace)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	err = srv.DB.UpdateNamespace(ns)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	return namespaceProto(ns), nil
+}
+
+// DeleteNamespace deletes a namespace, which must have no streams.
+func (srv *Server) DeleteNamespace(c context.Context, in *seer.DeleteNamespaceRequest) (em *empty.Empty, err error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	count, err := srv.countStreams(in.Name + "/")
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		err = status.Errorf(codes.FailedPrecondition, "namespace %v still has %v streams", in.Name, count)
+		return nil, err
+	}
+	err = srv.DB.DeleteNamespace(in.Name)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	srv.limits.forget(in.Name)
+	return &empty.Empty{}, nil
+}
+
+// ListNamespaces returns every namespace, in name order.
+func (srv *Server) ListNamespaces(c context.Context, in *empty.Empty) (l *seer.ListNamespacesResponse, err error) {
+	all, err := srv.DB.ListNamespaces()
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	l = &seer.ListNamespacesResponse{Namespaces: make([]*seer.Namespace, len(all))}
+	for i := range all {
+		l.Namespaces[i] = namespaceProto(all[i])
+	}
+	return l, nil
+}
@@ -0,0 +1,332 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/cshenton/seer/export"
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// inNamespace returns a context for requests made in the named namespace.
+func inNamespace(name string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("seer-namespace", name))
+}
+
+// setUpNamespace returns a server with a namespace "growth", limited to the
+// provided quotas.
+func setUpNamespace(t *testing.T, streams int64, points float64) (srv *server.Server) {
+	srv = setUp(t)
+	in := &seer.CreateNamespaceRequest{
+		Namespace: &seer.Namespace{Name: "growth", MaxStreams: streams, MaxPointsPerSecond: points},
+	}
+	_, err := srv.CreateNamespace(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in CreateNamespace:", err)
+	}
+	return srv
+}
+
+func createIn(c context.Context, srv *server.Server, name string) (err error) {
+	in := &seer.CreateStreamRequest{Stream: &seer.Stream{Name: name, Period: 3600}}
+	_, err = srv.CreateStream(c, in)
+	return err
+}
+
+func TestNamespaces(t *testing.T) {
+	srv := setUpNamespace(t, 10, 0)
+	c := context.Background()
+
+	ns, err := srv.GetNamespace(c, &seer.GetNamespaceRequest{Name: "growth"})
+	if err != nil {
+		t.Fatal("unexpected error in GetNamespace:", err)
+	}
+	if ns.MaxStreams != 10 {
+		t.Errorf("expected max streams %v, but got %v", 10, ns.MaxStreams)
+	}
+
+	up := &seer.UpdateNamespaceRequest{Namespace: &seer.Namespace{Name: "growth", MaxPointsPerSecond: 100}}
+	_, err = srv.UpdateNamespace(c, up)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateNamespace:", err)
+	}
+	_, err = srv.CreateNamespace(c, &seer.CreateNamespaceRequest{Namespace: &seer.Namespace{Name: "billing"}})
+	if err != nil {
+		t.Fatal("unexpected error in CreateNamespace:", err)
+	}
+
+	l, err := srv.ListNamespaces(c, &empty.Empty{})
+	if err != nil {
+		t.Fatal("unexpected error in ListNamespaces:", err)
+	}
+	if len(l.Namespaces) != 2 {
+		t.Fatalf("expected %v namespaces, but got %v", 2, len(l.Namespaces))
+	}
+	if l.Namespaces[0].Name != "billing" || l.Namespaces[1].MaxPointsPerSecond != 100 {
+		t.Errorf("expected billing then updated growth, but got %v", l.Namespaces)
+	}
+
+	_, err = srv.DeleteNamespace(c, &seer.DeleteNamespaceRequest{Name: "billing"})
+	if err != nil {
+		t.Fatal("unexpected error in DeleteNamespace:", err)
+	}
+	_, err = srv.GetNamespace(c, &seer.GetNamespaceRequest{Name: "billing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestNamespacesErrs(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	c := context.Background()
+	err := createIn(c, srv, "billing/sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+	err = createIn(inNamespace("growth"), srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	tt := []struct {
+		name string
+		code codes.Code
+		call func() error
+	}{
+		{"bad name", codes.InvalidArgument, func() error {
+			_, err := srv.CreateNamespace(c, &seer.CreateNamespaceRequest{Namespace: &seer.Namespace{Name: "Bad/Name"}})
+			return err
+		}},
+		{"negative quota", codes.InvalidArgument, func() error {
+			_, err := srv.CreateNamespace(c, &seer.CreateNamespaceRequest{Namespace: &seer.Namespace{Name: "ops", MaxStreams: -1}})
+			return err
+		}},
+		{"duplicate", codes.AlreadyExists, func() error {
+			_, err := srv.CreateNamespace(c, &seer.CreateNamespaceRequest{Namespace: &seer.Namespace{Name: "growth"}})
+			return err
+		}},
+		{"existing streams", codes.FailedPrecondition, func() error {
+			_, err := srv.CreateNamespace(c, &seer.CreateNamespaceRequest{Namespace: &seer.Namespace{Name: "billing"}})
+			return err
+		}},
+		{"update missing", codes.NotFound, func() error {
+			_, err := srv.UpdateNamespace(c, &seer.UpdateNamespaceRequest{Namespace: &seer.Namespace{Name: "ops"}})
+			return err
+		}},
+		{"delete missing", codes.NotFound, func() error {
+			_, err := srv.DeleteNamespace(c, &seer.DeleteNamespaceRequest{Name: "ops"})
+			return err
+		}},
+		{"delete with streams", codes.FailedPrecondition, func() error {
+			_, err := srv.DeleteNamespace(c, &seer.DeleteNamespaceRequest{Name: "growth"})
+			return err
+		}},
+		{"unknown namespace", codes.NotFound, func() error {
+			return createIn(inNamespace("ops"), srv, "sales")
+		}},
+		{"default namespace collision", codes.InvalidArgument, func() error {
+			return createIn(c, srv, "growth/visits")
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestNamespaceStreams(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	c := inNamespace("growth")
+
+	err := createIn(c, srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+	s, err := srv.GetStream(c, &seer.GetStreamRequest{Name: "sales"})
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if s.Name != "sales" {
+		t.Errorf("expected name %v, but got %v", "sales", s.Name)
+	}
+
+	l, err := srv.ListStreams(c, &seer.ListStreamsRequest{PageSize: 10})
+	if err != nil {
+		t.Fatal("unexpected error in ListStreams:", err)
+	}
+	if len(l.Streams) != 1 || l.Streams[0].Name != "sales" {
+		t.Errorf("expected only the namespace's sales stream, but got %v", l.Streams)
+	}
+
+	// The default namespace sees every stream, by its full name.
+	_, err = srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "growth/sales"})
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+	_, err = srv.GetStream(c, &seer.GetStreamRequest{Name: "visits"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestNamespaceQuotas(t *testing.T) {
+	srv := setUpNamespace(t, 1, 2)
+	c := inNamespace("growth")
+
+	err := createIn(c, srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+	err = createIn(c, srv, "visits")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected code %v, but got %v", codes.ResourceExhausted, status.Code(err))
+	}
+
+	update := func(n int) error {
+		ev := &seer.Event{}
+		for i := 0; i < n; i++ {
+			ts, _ := ptypes.TimestampProto(time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC))
+			ev.Times = append(ev.Times, ts)
+			ev.Values = append(ev.Values, float64(i))
+		}
+		_, err := srv.UpdateStream(c, &seer.UpdateStreamRequest{Name: "sales", Event: ev})
+		return err
+	}
+	err = update(2)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	err = update(2)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected code %v, but got %v", codes.ResourceExhausted, status.Code(err))
+	}
+	err = update(3)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected code %v, but got %v", codes.ResourceExhausted, status.Code(err))
+	}
+}
+
+func TestBackupRestoreNamespaces(t *testing.T) {
+	srv := setUpNamespace(t, 5, 0)
+	err := createIn(inNamespace("growth"), srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	bs := &backupServer{}
+	err = srv.Backup(&empty.Empty{}, bs)
+	if err != nil {
+		t.Fatal("unexpected error in Backup:", err)
+	}
+
+	dst, _ := server.New(server.Config{Backend: server.Memory})
+	rs := &restoreServer{data: bs.data, size: len(bs.data)}
+	err = dst.Restore(rs)
+	if err != nil {
+		t.Fatal("unexpected error in Restore:", err)
+	}
+	if rs.resp.Streams != 4 {
+		t.Errorf("expected %v streams restored, but there were %v", 4, rs.resp.Streams)
+	}
+	ns, err := dst.GetNamespace(context.Background(), &seer.GetNamespaceRequest{Name: "growth"})
+	if err != nil {
+		t.Fatal("unexpected error in GetNamespace:", err)
+	}
+	if ns.MaxStreams != 5 {
+		t.Errorf("expected max streams %v, but got %v", 5, ns.MaxStreams)
+	}
+	_, err = dst.GetStream(inNamespace("growth"), &seer.GetStreamRequest{Name: "sales"})
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
+
+func TestNamespaceExportImport(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	c := inNamespace("growth")
+	err := createIn(c, srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	es := &exportServer{requestStream: requestStream{c: c}}
+	err = srv.ExportStreams(&seer.ExportStreamsRequest{Format: seer.ExportFormat_CSV}, es)
+	if err != nil {
+		t.Fatal("unexpected error in ExportStreams:", err)
+	}
+	if strings.Contains(string(es.data), "visits") || !strings.Contains(string(es.data), "\nsales,") {
+		t.Errorf("expected only the namespace's sales stream, but got %q", es.data)
+	}
+
+	dst, _ := server.New(server.Config{Backend: server.Memory})
+	in := &seer.CreateNamespaceRequest{Namespace: &seer.Namespace{Name: "growth", MaxStreams: 1}}
+	_, err = dst.CreateNamespace(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in CreateNamespace:", err)
+	}
+	is := &importServer{requestStream: requestStream{c: c}, format: seer.ExportFormat_CSV, data: es.data, size: 16}
+	err = dst.ImportStreams(is)
+	if err != nil {
+		t.Fatal("unexpected error in ImportStreams:", err)
+	}
+	_, err = dst.GetStream(c, &seer.GetStreamRequest{Name: "sales"})
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+
+	// Imports are created like any other stream in the namespace.
+	data := "name,period\nvisits,3600\n"
+	_, err = dst.Import(c, strings.NewReader(data), export.CSV)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected code %v, but got %v", codes.ResourceExhausted, status.Code(err))
+	}
+	data = "name,period\ngrowth/visits,3600\n"
+	_, err = dst.Import(context.Background(), strings.NewReader(data), export.CSV)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestNamespaceBackupErrs(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	c := inNamespace("growth")
+
+	err := srv.Backup(&empty.Empty{}, &backupServer{requestStream: requestStream{c: c}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected code %v, but got %v", codes.PermissionDenied, status.Code(err))
+	}
+	_, err = srv.RestoreFrom(c, strings.NewReader(""))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected code %v, but got %v", codes.PermissionDenied, status.Code(err))
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// patchConfig applies the fields of in named by paths to the config of the
+// stream in the scope, and returns the validated result, named by its key.
+func patchConfig(sc *scope, st *stream.Stream, in *seer.Stream, paths []string) (conf *stream.Config, err error) {
+	if in == nil {
+		err = fmt.Errorf("stream must be set")
+		return nil, err
+	}
+	if len(paths) == 0 {
+		err = fmt.Errorf("update_mask must have at least one path")
+		return nil, err
+	}
+
+	p := sc.proto(st)
+	for _, path := range paths {
+		switch path {
+		case "period":
+			p.Period = in.Period
+		case "min":
+			p.Min = in.Min
+		case "max":
+			p.Max = in.Max
+		case "domain":
+			p.Domain = in.Domain
+		case "retention":
+			p.Retention = in.Retention
+		case "snapshots":
+			p.Snapshots = in.Snapshots
+		case "labels":
+			p.Labels = in.Labels
+		case "ttl":
+			p.Ttl = in.Ttl
+		case "name":
+			p.Name = in.Name
+		default:
+			err = fmt.Errorf("update_mask path %q is not a stream field that can be patched", path)
+			return nil, err
+		}
+	}
+	conf, err = streamConfig(p)
+	if err != nil {
+		return nil, err
+	}
+	conf.Name = sc.key(conf.Name)
+	return conf, nil
+}
+
+// PatchStream applies the fields of the provided stream named by the update
+// mask to the requested stream, keeping its model state where the change
+// allows it, and reports how its model was changed. A new name moves the
+// stream, with its events and snapshots, as RenameStream does, before the
+// other fields are applied, and is moved back if they can't be.
+func (srv *Server) PatchStream(c context.Context, in *seer.PatchStreamRequest) (p *seer.PatchStreamResponse, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	conf, err := patchConfig(sc, st, in.Stream, in.UpdateMask.GetPaths())
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	from := st.Config.Name
+	if conf.Name != from {
+		err = srv.renameStream(sc, from, conf.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m := st.Reconfigure(conf)
+	err = srv.DB.UpdateStream(conf.Name, st)
+	srv.cache.forget(conf.Name)
+	if err != nil {
+		code := updateCode(err)
+		if conf.Name != from {
+			rerr := srv.renameStream(sc, conf.Name, from)
+			if rerr != nil {
+				err = fmt.Errorf("%v, and the stream was left at name %v: %v", err, sc.name(conf.Name), rerr)
+			}
+		}
+		err = status.Error(code, err.Error())
+		return nil, err
+	}
+
+	p = &seer.PatchStreamResponse{
+		Stream:    sc.proto(st),
+		Migration: seer.Migration(m),
+	}
+	return p, nil
+}
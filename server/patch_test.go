@@ -0,0 +1,187 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPatchStream(t *testing.T) {
+	tt := []struct {
+		name      string
+		stream    string
+		patch     *seer.Stream
+		paths     []string
+		migration seer.Migration
+	}{
+		{"labels", "history", &seer.Stream{Labels: map[string]string{"team": "growth"}}, []string{"labels"}, seer.Migration_IN_PLACE},
+		{"bounds", "history", &seer.Stream{Min: 1, Max: 10, Domain: seer.Domain_CONTINUOUS_INTERVAL}, []string{"min", "max", "domain"}, seer.Migration_IN_PLACE},
+		{"period", "history", &seer.Stream{Period: 86400}, []string{"period"}, seer.Migration_TRANSFORMED},
+		{"period without events", "sales", &seer.Stream{Period: 86400}, []string{"period"}, seer.Migration_REINITIALISED},
+		{"ttl", "history", &seer.Stream{Ttl: ptypes.DurationProto(time.Hour)}, []string{"ttl"}, seer.Migration_IN_PLACE},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := setUp(t)
+			historyStream(t, srv, 25)
+			old, _ := srv.DB.GetStream(tc.stream)
+
+			in := &seer.PatchStreamRequest{
+				Name:       tc.stream,
+				Stream:     tc.patch,
+				UpdateMask: &field_mask.FieldMask{Paths: tc.paths},
+			}
+			p, err := srv.PatchStream(context.Background(), in)
+			if err != nil {
+				t.Fatal("unexpected error in PatchStream:", err)
+			}
+			if p.Migration != tc.migration {
+				t.Errorf("expected migration %v, but it was %v", tc.migration, p.Migration)
+			}
+			if p.Stream.Revision != old.Revision+1 {
+				t.Errorf("expected revision %v, but it was %v", old.Revision+1, p.Stream.Revision)
+			}
+
+			// Fields outside the mask are left as they were.
+			st, _ := srv.DB.GetStream(tc.stream)
+			if (st.Config.Retention == nil) != (old.Config.Retention == nil) {
+				t.Error("expected retention to be unchanged, but it was", st.Config.Retention)
+			}
+			if st.Time != old.Time {
+				t.Errorf("expected last event time %v, but it was %v", old.Time, st.Time)
+			}
+		})
+	}
+}
+
+func TestPatchStreamName(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+	old, _ := srv.DB.GetStream("history")
+
+	in := &seer.PatchStreamRequest{
+		Name:       "history",
+		Stream:     &seer.Stream{Name: "revenue", Labels: map[string]string{"team": "growth"}},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"name", "labels"}},
+	}
+	p, err := srv.PatchStream(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in PatchStream:", err)
+	}
+	if p.Stream.Name != "revenue" || p.Migration != seer.Migration_IN_PLACE {
+		t.Errorf("expected revenue patched in place, but got %v with migration %v", p.Stream.Name, p.Migration)
+	}
+
+	s, err := srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "revenue"})
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if s.Labels["team"] != "growth" || s.Revision != old.Revision+1 {
+		t.Errorf("expected labels at revision %v, but got %v at %v", old.Revision+1, s.Labels, s.Revision)
+	}
+	_, err = srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+	ev, err := srv.GetEvents(context.Background(), &seer.GetEventsRequest{Name: "revenue"})
+	if err != nil {
+		t.Fatal("unexpected error in GetEvents:", err)
+	}
+	if len(ev.Event.Values) != 25 {
+		t.Errorf("expected %v events, but there were %v", 25, len(ev.Event.Values))
+	}
+}
+
+func TestPatchStreamNameConflict(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+	srv.DB = &racyStore{srv.DB}
+
+	in := &seer.PatchStreamRequest{
+		Name:       "history",
+		Stream:     &seer.Stream{Name: "revenue", Labels: map[string]string{"team": "growth"}},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"name", "labels"}},
+	}
+	_, err := srv.PatchStream(context.Background(), in)
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("expected code %v, but got %v", codes.Aborted, status.Code(err))
+	}
+
+	// The rename is undone, so the patch can be retried by the old name.
+	_, err = srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "revenue"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+	s, err := srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if len(s.Labels) != 0 {
+		t.Errorf("expected no labels, but got %v", s.Labels)
+	}
+	ev, err := srv.GetEvents(context.Background(), &seer.GetEventsRequest{Name: "history"})
+	if err != nil {
+		t.Fatal("unexpected error in GetEvents:", err)
+	}
+	if len(ev.Event.Values) != 25 {
+		t.Errorf("expected %v events, but there were %v", 25, len(ev.Event.Values))
+	}
+}
+
+func TestPatchStreamErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name   string
+		stream string
+		patch  *seer.Stream
+		paths  []string
+		code   codes.Code
+	}{
+		{"not found", "missing", &seer.Stream{Period: 60}, []string{"period"}, codes.NotFound},
+		{"no stream", "sales", nil, []string{"period"}, codes.InvalidArgument},
+		{"no paths", "sales", &seer.Stream{Period: 60}, nil, codes.InvalidArgument},
+		{"unknown path", "sales", &seer.Stream{Period: 60}, []string{"revision"}, codes.InvalidArgument},
+		{"invalid name", "sales", &seer.Stream{Name: "ab"}, []string{"name"}, codes.InvalidArgument},
+		{"existing name", "sales", &seer.Stream{Name: "visits"}, []string{"name"}, codes.AlreadyExists},
+		{"invalid period", "sales", &seer.Stream{Period: 0.5}, []string{"period"}, codes.InvalidArgument},
+		{"invalid label", "sales", &seer.Stream{Labels: map[string]string{"": "x"}}, []string{"labels"}, codes.InvalidArgument},
+		{"negative ttl", "sales", &seer.Stream{Ttl: ptypes.DurationProto(-time.Hour)}, []string{"ttl"}, codes.InvalidArgument},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &seer.PatchStreamRequest{
+				Name:       tc.stream,
+				Stream:     tc.patch,
+				UpdateMask: &field_mask.FieldMask{Paths: tc.paths},
+			}
+			_, err := srv.PatchStream(context.Background(), in)
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// limiter limits the rate points are sent to each namespace, with a token
+// bucket per namespace that refills at its rate, and holds a second of points.
+type limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// bucket is the state of a namespace's token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take removes n tokens from the namespace's bucket, refilled at rate per
+// second, and reports whether it held them. Requests of more than a second of
+// points are never allowed.
+func (l *limiter) take(ns string, rate float64, n int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+	b, ok := l.buckets[ns]
+	if !ok {
+		b = &bucket{tokens: rate, last: now}
+		l.buckets[ns] = b
+	}
+	b.tokens = math.Min(rate, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if float64(n) > b.tokens {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// give returns n tokens to the namespace's bucket, refilled at rate per
+// second, for points taken by requests that weren't applied.
+func (l *limiter) give(ns string, rate float64, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[ns]
+	if !ok {
+		return
+	}
+	b.tokens = math.Min(rate, b.tokens+float64(n))
+}
+
+// forget removes the namespace's bucket.
+func (l *limiter) forget(ns string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, ns)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"time"
+
+	"github.com/cshenton/seer/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rebuildBatch is the number of events replayed between progress reports.
+const rebuildBatch = 1000
+
+// Rebuild replaces the named stream with a fresh stream updated with its
+// retained events, with the provided config, or if nil its existing one.
+// Progress is called with the number of events replayed and the total after
+// each batch. The rebuilt stream only replaces the stored stream if it was not
+// updated during the rebuild. Errors are returned as grpc statuses.
+func (srv *Server) Rebuild(name string, conf *stream.Config, progress func(done, total int) error) (st *stream.Stream, err error) {
+	old, err := srv.DB.GetStream(name)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	if conf == nil {
+		conf = old.Config
+	}
+	if conf.Name != name {
+		err = status.Errorf(codes.InvalidArgument, "config name %v does not match stream name %v", conf.Name, name)
+		return nil, err
+	}
+	if old.Config.Retention == nil {
+		err = status.Error(codes.FailedPrecondition, "stream has no retention policy, so has no events to rebuild from")
+		return nil, err
+	}
+
+	events, err := srv.DB.GetEvents(name, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+
+	st = stream.NewWithConfig(conf)
+	st.Revision = old.Revision
+	var perr error
+	err = st.Replay(events, rebuildBatch, func(n int) error {
+		perr = progress(n, len(events))
+		return perr
+	})
+	if perr != nil {
+		return nil, perr
+	}
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	err = srv.DB.UpdateStream(name, st)
+	srv.cache.forget(name)
+	if err != nil {
+		err = status.Error(updateCode(err), err.Error())
+		return nil, err
+	}
+	return st, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRebuild(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+	old, _ := srv.DB.GetStream("history")
+
+	var done, total int
+	st, err := srv.Rebuild("history", nil, func(d, tot int) error {
+		done, total = d, tot
+		return nil
+	})
+	if err != nil {
+		t.Fatal("unexpected error in Rebuild:", err)
+	}
+	if done != 25 || total != 25 {
+		t.Errorf("expected progress of %v of %v, but got %v of %v", 25, 25, done, total)
+	}
+	if st.Revision != old.Revision+1 {
+		t.Errorf("expected revision %v, but it was %v", old.Revision+1, st.Revision)
+	}
+
+	// Replaying the full history reproduces the model.
+	st, _ = srv.DB.GetStream("history")
+	_, want, _, _ := old.Forecast(3, nil)
+	_, have, _, _ := st.Forecast(3, nil)
+	for i := range want {
+		if want[i] != have[i] {
+			t.Errorf("expected forecast %v, but got %v", want, have)
+		}
+	}
+}
+
+func TestRebuildConfig(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	conf, _ := stream.NewConfig("history", 3600, 0, 0, int(stream.ContinuousRight))
+	conf.Retention = &stream.Retention{Count: 10}
+	_, err := srv.Rebuild("history", conf, func(d, tot int) error { return nil })
+	if err != nil {
+		t.Fatal("unexpected error in Rebuild:", err)
+	}
+
+	st, _ := srv.DB.GetStream("history")
+	if st.Config.Domain != stream.ContinuousRight {
+		t.Errorf("expected domain %v, but it was %v", stream.ContinuousRight, st.Config.Domain)
+	}
+	e, _ := srv.DB.GetEvents("history", time.Time{}, time.Time{}, 0)
+	if len(e) != 10 {
+		t.Errorf("expected %v retained events, but there were %v", 10, len(e))
+	}
+}
+
+func TestRebuildErrs(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	other, _ := stream.NewConfig("other", 3600, 0, 0, 0)
+	daily, _ := stream.NewConfig("history", 86400, 0, 0, 0)
+	daily.Retention = &stream.Retention{}
+	stop := status.Error(codes.Canceled, "stop")
+
+	tt := []struct {
+		name     string
+		stream   string
+		conf     *stream.Config
+		progress error
+		code     codes.Code
+	}{
+		{"not a stream", "notastream", nil, nil, codes.NotFound},
+		{"no retention", "sales", nil, nil, codes.FailedPrecondition},
+		{"name mismatch", "history", other, nil, codes.InvalidArgument},
+		{"new period", "history", daily, nil, codes.InvalidArgument},
+		{"progress error", "history", nil, stop, codes.Canceled},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.Rebuild(tc.stream, tc.conf, func(d, tot int) error { return tc.progress })
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+
+	st, _ := srv.DB.GetStream("history")
+	if st.Revision != 1 {
+		t.Errorf("expected failed rebuilds to leave revision %v, but it was %v", 1, st.Revision)
+	}
+}
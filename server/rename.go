@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"context"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// copyCode returns the status code for an error from the store's RenameStream
+// or CloneStream.
+func copyCode(err error) codes.Code {
+	switch err.(type) {
+	case *store.NotFoundError:
+		return codes.NotFound
+	case *store.AlreadyExistsError:
+		return codes.AlreadyExists
+	}
+	return codes.Internal
+}
+
+// RenameStream moves a stream, with its events and snapshots, to a new name in
+// the same namespace.
+func (srv *Server) RenameStream(c context.Context, in *seer.RenameStreamRequest) (s *seer.Stream, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	err = stream.ValidateName(in.NewName)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	to := sc.key(in.NewName)
+	err = srv.renameStream(sc, sc.key(in.Name), to)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(to)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	return sc.proto(st), nil
+}
+
+// renameStream moves the stream stored at from to to, in the scope. Renames
+// are serialised with creates, so that streams in the default namespace are
+// not named as if in another.
+func (srv *Server) renameStream(sc *scope, from, to string) (err error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	err = srv.checkName(sc, to)
+	if err != nil {
+		return err
+	}
+	err = srv.DB.RenameStream(from, to)
+	srv.cache.forget(from)
+	srv.cache.forget(to)
+	if err != nil {
+		err = status.Error(copyCode(err), err.Error())
+		return err
+	}
+	return nil
+}
+
+// CloneStream copies a stream, with its model state, events and snapshots, to
+// a new stream in the same namespace, which counts towards its stream quota.
+func (srv *Server) CloneStream(c context.Context, in *seer.CloneStreamRequest) (s *seer.Stream, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	err = stream.ValidateName(in.Destination)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	dst := sc.key(in.Destination)
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	err = srv.checkName(sc, dst)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.checkQuota(sc)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.DB.CloneStream(sc.key(in.Source), dst)
+	srv.cache.forget(dst)
+	if err != nil {
+		err = status.Error(copyCode(err), err.Error())
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(dst)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	return sc.proto(st), nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cshenton/seer/seer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRenameStream(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	s, err := srv.RenameStream(context.Background(), &seer.RenameStreamRequest{Name: "history", NewName: "archive"})
+	if err != nil {
+		t.Fatal("unexpected error in RenameStream:", err)
+	}
+	if s.Name != "archive" {
+		t.Errorf("expected name %v, but got %v", "archive", s.Name)
+	}
+	ev, err := srv.GetEvents(context.Background(), &seer.GetEventsRequest{Name: "archive"})
+	if err != nil {
+		t.Fatal("unexpected error in GetEvents:", err)
+	}
+	if len(ev.Event.Values) != 25 {
+		t.Errorf("expected %v events, but got %v", 25, len(ev.Event.Values))
+	}
+	_, err = srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v, but got %v", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestCloneStream(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	s, err := srv.CloneStream(context.Background(), &seer.CloneStreamRequest{Source: "history", Destination: "what-if"})
+	if err != nil {
+		t.Fatal("unexpected error in CloneStream:", err)
+	}
+	if s.Name != "what-if" || s.Revision != 0 {
+		t.Errorf("expected what-if at revision 0, but got %v at %v", s.Name, s.Revision)
+	}
+
+	// The clone forecasts as its source did.
+	in := &seer.GetForecastRequest{Name: "history", N: 3}
+	want, _ := srv.GetForecast(context.Background(), in)
+	in.Name = "what-if"
+	have, err := srv.GetForecast(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in GetForecast:", err)
+	}
+	for i := range want.Values {
+		if want.Values[i] != have.Values[i] {
+			t.Errorf("expected forecast %v, but got %v", want.Values, have.Values)
+		}
+	}
+}
+
+func TestRenameCloneStreamNamespace(t *testing.T) {
+	srv := setUpNamespace(t, 1, 0)
+	c := inNamespace("growth")
+	err := createIn(c, srv, "sales")
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	s, err := srv.RenameStream(c, &seer.RenameStreamRequest{Name: "sales", NewName: "revenue"})
+	if err != nil {
+		t.Fatal("unexpected error in RenameStream:", err)
+	}
+	if s.Name != "revenue" {
+		t.Errorf("expected name %v, but got %v", "revenue", s.Name)
+	}
+	_, err = srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "growth/revenue"})
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+
+	_, err = srv.CloneStream(c, &seer.CloneStreamRequest{Source: "revenue", Destination: "what-if"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected code %v, but got %v", codes.ResourceExhausted, status.Code(err))
+	}
+}
+
+func TestRenameCloneStreamErrs(t *testing.T) {
+	srv := setUpNamespace(t, 0, 0)
+	c := context.Background()
+
+	tt := []struct {
+		name string
+		code codes.Code
+		call func() error
+	}{
+		{"rename missing", codes.NotFound, func() error {
+			_, err := srv.RenameStream(c, &seer.RenameStreamRequest{Name: "missing", NewName: "other"})
+			return err
+		}},
+		{"rename to existing", codes.AlreadyExists, func() error {
+			_, err := srv.RenameStream(c, &seer.RenameStreamRequest{Name: "sales", NewName: "visits"})
+			return err
+		}},
+		{"rename to invalid", codes.InvalidArgument, func() error {
+			_, err := srv.RenameStream(c, &seer.RenameStreamRequest{Name: "sales", NewName: "s"})
+			return err
+		}},
+		{"rename into namespace", codes.InvalidArgument, func() error {
+			_, err := srv.RenameStream(c, &seer.RenameStreamRequest{Name: "sales", NewName: "growth/sales"})
+			return err
+		}},
+		{"clone missing", codes.NotFound, func() error {
+			_, err := srv.CloneStream(c, &seer.CloneStreamRequest{Source: "missing", Destination: "other"})
+			return err
+		}},
+		{"clone to existing", codes.AlreadyExists, func() error {
+			_, err := srv.CloneStream(c, &seer.CloneStreamRequest{Source: "sales", Destination: "visits"})
+			return err
+		}},
+		{"clone into namespace", codes.InvalidArgument, func() error {
+			_, err := srv.CloneStream(c, &seer.CloneStreamRequest{Source: "sales", Destination: "growth/sales"})
+			return err
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
@@ -19,9 +19,12 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/cshenton/seer/label"
 	"github.com/cshenton/seer/seer"
+	"github.com/cshenton/seer/store"
 	"github.com/cshenton/seer/stream"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
@@ -30,24 +33,72 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// CreateStream creates the provided stream.
-func (srv *Server) CreateStream(c context.Context, in *seer.CreateStreamRequest) (s *seer.Stream, err error) {
-	st, err := stream.New(
-		in.Stream.Name,
-		in.Stream.Period,
-		in.Stream.Min,
-		in.Stream.Max,
-		int(in.Stream.Domain),
-	)
+// maxSamples bounds the number of values a single SampleForecast may generate.
+const maxSamples = 1000000
+
+// maxForecastWindows bounds the number of windows a forecast given by an end
+// time may have.
+const maxForecastWindows = 100000
+
+// Page sizes for GetEvents, the default is used if no page size is given.
+const (
+	defaultEventPageSize = 100
+	maxEventPageSize     = 10000
+)
+
+// streamConfig validates and converts a stream's protocol buffer message to
+// its configuration.
+func streamConfig(in *seer.Stream) (conf *stream.Config, err error) {
+	conf, err = stream.NewConfig(in.Name, in.Period, in.Min, in.Max, int(in.Domain))
 	if err != nil {
-		err = status.Error(codes.InvalidArgument, err.Error())
 		return nil, err
 	}
-	err = srv.DB.CreateStream(in.Stream.Name, st)
+	if r := in.Retention; r != nil {
+		var age time.Duration
+		if r.Age != nil {
+			age, err = ptypes.Duration(r.Age)
+			if err != nil {
+				return nil, err
+			}
+		}
+		conf.Retention, err = stream.NewRetention(int(r.Count), age)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p := in.Snapshots; p != nil {
+		var interval time.Duration
+		if p.Interval != nil {
+			interval, err = ptypes.Duration(p.Interval)
+			if err != nil {
+				return nil, err
+			}
+		}
+		conf.Snapshots, err = stream.NewSnapshotPolicy(int(p.Count), interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = label.Validate(in.Labels)
 	if err != nil {
-		err = status.Error(codes.AlreadyExists, err.Error())
 		return nil, err
 	}
+	conf.Labels = in.Labels
+	if in.Ttl != nil {
+		conf.TTL, err = ptypes.Duration(in.Ttl)
+		if err != nil {
+			return nil, err
+		}
+		err = stream.ValidateTTL(conf.TTL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return conf, nil
+}
+
+// streamProto converts a stream to its protocol buffer message.
+func streamProto(st *stream.Stream) (s *seer.Stream) {
 	t, _ := ptypes.TimestampProto(st.Time)
 	s = &seer.Stream{
 		Name:          st.Config.Name,
@@ -56,69 +107,124 @@ func (srv *Server) CreateStream(c context.Context, in *seer.CreateStreamRequest)
 		Domain:        seer.Domain(st.Config.Domain),
 		Min:           st.Config.Min,
 		Max:           st.Config.Max,
+		Revision:      st.Revision,
+		Labels:        st.Config.Labels,
 	}
-	return s, nil
+	if r := st.Config.Retention; r != nil {
+		s.Retention = &seer.Retention{
+			Count: int64(r.Count),
+			Age:   ptypes.DurationProto(r.Age),
+		}
+	}
+	if p := st.Config.Snapshots; p != nil {
+		s.Snapshots = &seer.SnapshotPolicy{
+			Count:    int64(p.Count),
+			Interval: ptypes.DurationProto(p.Interval),
+		}
+	}
+	if st.Config.TTL > 0 {
+		s.Ttl = ptypes.DurationProto(st.Config.TTL)
+	}
+	return s
+}
+
+// eventTimes converts the event's timestamps.
+func eventTimes(ev *seer.Event) (t []time.Time) {
+	t = make([]time.Time, len(ev.Times))
+	for i := range t {
+		ts, _ := ptypes.Timestamp(ev.Times[i])
+		t[i] = ts
+	}
+	return t
+}
+
+// updateCode returns the status code for an error from the store's
+// UpdateStream. A conflict means the stream was updated concurrently, and the
+// client may retry, otherwise the stream was deleted mid request.
+func updateCode(err error) codes.Code {
+	if _, ok := err.(*store.ConflictError); ok {
+		return codes.Aborted
+	}
+	return codes.NotFound
+}
+
+// CreateStream creates the provided stream, in the request's namespace.
+func (srv *Server) CreateStream(c context.Context, in *seer.CreateStreamRequest) (s *seer.Stream, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := streamConfig(in.Stream)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	conf.Name = sc.key(conf.Name)
+	st := stream.NewWithConfig(conf)
+	err = srv.createStream(sc, st)
+	if err != nil {
+		return nil, err
+	}
+	return sc.proto(st), nil
 }
 
 // GetStream retrieves and returns the requested stream.
 func (srv *Server) GetStream(c context.Context, in *seer.GetStreamRequest) (s *seer.Stream, err error) {
-	st, err := srv.DB.GetStream(in.Name)
+	sc, err := srv.scopeOf(c)
 	if err != nil {
-		err = status.Error(codes.NotFound, err.Error())
 		return nil, err
 	}
-	t, _ := ptypes.TimestampProto(st.Time)
-	s = &seer.Stream{
-		Name:          st.Config.Name,
-		Period:        st.Config.Period,
-		LastEventTime: t,
-		Domain:        seer.Domain(st.Config.Domain),
-		Min:           st.Config.Min,
-		Max:           st.Config.Max,
+	st, err := srv.DB.GetStream(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
 	}
-	return s, nil
+	return sc.proto(st), nil
 }
 
-// UpdateStream applies an adaptive filter update using the provided events.
+// UpdateStream applies an adaptive filter update using the provided events, and
+// retains them if the stream has a retention policy. The events count towards
+// the namespace's points per second quota.
 func (srv *Server) UpdateStream(c context.Context, in *seer.UpdateStreamRequest) (s *seer.Stream, err error) {
-	st, err := srv.DB.GetStream(in.Name)
+	sc, err := srv.scopeOf(c)
 	if err != nil {
-		err = status.Error(codes.NotFound, err.Error())
 		return nil, err
 	}
-	t := make([]time.Time, len(in.Event.Times))
-	for i := range t {
-		ts, _ := ptypes.Timestamp(in.Event.Times[i])
-		t[i] = ts
+	err = srv.takePoints(sc, len(in.Event.GetValues()))
+	if err != nil {
+		return nil, err
 	}
+	st, err := srv.DB.GetStream(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	t := eventTimes(in.Event)
 
 	err = st.Update(in.Event.Values, t)
 	if err != nil {
 		err = status.Error(codes.InvalidArgument, err.Error())
 		return nil, err
 	}
-	err = srv.DB.UpdateStream(in.Name, st)
+	events, _ := stream.Events(in.Event.Values, t)
+	err = srv.DB.UpdateStream(sc.key(in.Name), st, events...)
+	srv.cache.forget(sc.key(in.Name))
 	if err != nil {
-		// requires a delete to occur mid request
-		err = status.Error(codes.NotFound, err.Error())
+		err = status.Error(updateCode(err), err.Error())
 		return nil, err
 	}
 
-	ts, _ := ptypes.TimestampProto(st.Time)
-	s = &seer.Stream{
-		Name:          st.Config.Name,
-		Period:        st.Config.Period,
-		LastEventTime: ts,
-		Domain:        seer.Domain(st.Config.Domain),
-		Min:           st.Config.Min,
-		Max:           st.Config.Max,
-	}
-	return s, nil
+	return sc.proto(st), nil
 }
 
 // DeleteStream removes the requested stream.
 func (srv *Server) DeleteStream(c context.Context, in *seer.DeleteStreamRequest) (em *empty.Empty, err error) {
-	err = srv.DB.DeleteStream(in.Name)
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.DB.DeleteStream(sc.key(in.Name))
+	srv.cache.forget(sc.key(in.Name))
 	if err != nil {
 		err = status.Error(codes.NotFound, err.Error())
 		return nil, err
@@ -126,44 +232,153 @@ func (srv *Server) DeleteStream(c context.Context, in *seer.DeleteStreamRequest)
 	return &empty.Empty{}, nil
 }
 
-// ListStreams returns a paged set of streams.
+// ListStreams returns a page of streams, with the requested prefix, in the
+// requested order. Pages are continued with the returned page token, or for
+// older clients, by page number.
 func (srv *Server) ListStreams(c context.Context, in *seer.ListStreamsRequest) (s *seer.ListStreamsResponse, err error) {
-	lst, err := srv.DB.ListStreams(int(in.PageNumber), int(in.PageSize))
+	size := int(in.PageSize)
+	if size == 0 {
+		size = defaultStreamPageSize
+	}
+	if size < 0 || size > maxStreamPageSize {
+		err = fmt.Errorf("page_size must be between 0 and %v, but was %v", maxStreamPageSize, size)
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	sc, err := srv.scopeOf(c)
 	if err != nil {
-		err = status.Error(codes.NotFound, err.Error())
 		return nil, err
 	}
-	ls := make([]*seer.Stream, len(lst))
-	for i := range lst {
-		st := lst[i]
-		ts, _ := ptypes.TimestampProto(st.Time)
-		ls[i] = &seer.Stream{
-			Name:          st.Config.Name,
-			Period:        st.Config.Period,
-			LastEventTime: ts,
-			Domain:        seer.Domain(st.Config.Domain),
-			Min:           st.Config.Min,
-			Max:           st.Config.Max,
-		}
+	opts, err := listOptions(in, sc.prefix)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	// Numbered pages are read from the start of the list.
+	skip := 0
+	if in.PageToken == "" && in.PageNumber > 1 {
+		skip = (int(in.PageNumber) - 1) * size
+	}
+	opts.Limit = skip + size + 1
+
+	lst, total, err := srv.DB.ListStreams(opts)
+	if err != nil {
+		err = status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	s = &seer.ListStreamsResponse{
-		Streams: ls,
+	if skip > len(lst) {
+		skip = len(lst)
+	}
+	lst = lst[skip:]
+
+	s = &seer.ListStreamsResponse{TotalSize: int64(total)}
+	if len(lst) > size {
+		lst = lst[:size]
+		s.NextPageToken = nextPageToken(opts, store.CursorOf(lst[size-1]))
+	}
+	s.Streams = make([]*seer.Stream, len(lst))
+	for i := range lst {
+		s.Streams[i] = sc.proto(lst[i])
 	}
 	return s, nil
 }
 
-// GetForecast generates a forecast from a stream from its current time.
+// GetForecast generates a forecast from a stream from its current time, or
+// from the first period at or after the requested start time. If an
+// aggregation is requested, each forecast value is the sum or mean of a
+// window of periods.
 func (srv *Server) GetForecast(c context.Context, in *seer.GetForecastRequest) (f *seer.Forecast, err error) {
-	st, err := srv.DB.GetStream(in.Name)
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(sc.key(in.Name))
 	if err != nil {
 		err = status.Error(codes.NotFound, err.Error())
 		return nil, err
 	}
-	times, values, intervals, err := st.Forecast(int(in.N), []float64{0.8, 0.9, 0.95})
+	skip, n, err := forecastSpan(st, in)
 	if err != nil {
 		err = status.Error(codes.InvalidArgument, err.Error())
 		return nil, err
 	}
+	f, err = srv.cachedForecast(sc.key(in.Name), st, skip, n, in.Aggregation, int(in.Window), defaultProbabilities)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	return f, nil
+}
+
+// forecastSpan returns the number of periods a forecast request skips before
+// its first window, and the number of windows it asks for, which is given by
+// either its n or its end time.
+func forecastSpan(st *stream.Stream, in *seer.GetForecastRequest) (skip, n int, err error) {
+	n = int(in.N)
+	if in.StartTime == nil && in.EndTime == nil {
+		return 0, n, nil
+	}
+	if st.Time.IsZero() {
+		err = fmt.Errorf("stream has no events, so can't be forecast from a time")
+		return 0, 0, err
+	}
+	if in.StartTime != nil {
+		start, err := ptypes.Timestamp(in.StartTime)
+		if err != nil {
+			return 0, 0, err
+		}
+		skip = st.Skip(start)
+	}
+	if in.EndTime == nil {
+		return skip, n, nil
+	}
+	if in.N != 0 {
+		err = fmt.Errorf("only one of n and end_time may be given")
+		return 0, 0, err
+	}
+	end, err := ptypes.Timestamp(in.EndTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	window := forecastWindow(in.Aggregation, int(in.Window))
+	if window <= 0 {
+		err = fmt.Errorf("window must be greater than 0")
+		return 0, 0, err
+	}
+	n = st.Windows(skip, window, end)
+	if n <= 0 {
+		err = fmt.Errorf("end_time must leave at least one whole window after start_time")
+		return 0, 0, err
+	}
+	if n > maxForecastWindows {
+		err = fmt.Errorf("end_time gives %v windows, but at most %v may be forecast", n, maxForecastWindows)
+		return 0, 0, err
+	}
+	return skip, n, nil
+}
+
+// forecastWindow returns the window of a forecast request, which is a single
+// period if unset without an aggregation.
+func forecastWindow(agg seer.Aggregation, window int) int {
+	if agg == seer.Aggregation_NONE && window == 0 {
+		return 1
+	}
+	return window
+}
+
+// defaultProbabilities are the probabilities of the intervals in a forecast,
+// unless the request gives its own.
+var defaultProbabilities = []float64{0.8, 0.9, 0.95}
+
+// forecast generates n forecast values after skipping skip periods from the
+// stream's current time, each the sum or mean of a window of periods if an
+// aggregation is given, with intervals of the provided probabilities.
+func forecast(st *stream.Stream, skip, n int, agg seer.Aggregation, window int, probs []float64) (f *seer.Forecast, err error) {
+	times, values, intervals, err := st.ForecastAggregateFrom(skip, n, stream.Aggregation(agg), forecastWindow(agg, window), probs)
+	if err != nil {
+		return nil, err
+	}
 
 	protoTimes := make([]*timestamp.Timestamp, len(times))
 	for i := range times {
@@ -185,3 +400,220 @@ func (srv *Server) GetForecast(c context.Context, in *seer.GetForecastRequest) (
 	}
 	return f, nil
 }
+
+// RefitStream learns the stream's model parameters from the provided history,
+// or if none is provided, from the stream's retained events. The stream's
+// random walk variance is kept, and held fixed in the fit.
+func (srv *Server) RefitStream(c context.Context, in *seer.RefitStreamRequest) (s *seer.Stream, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+
+	var (
+		vals []float64
+		t    []time.Time
+	)
+	if in.Event != nil && len(in.Event.Values) > 0 {
+		vals = in.Event.Values
+		t = eventTimes(in.Event)
+	} else {
+		if st.Config.Retention == nil {
+			err = status.Error(codes.FailedPrecondition, "stream has no retention policy, so history must be provided")
+			return nil, err
+		}
+		events, err := srv.DB.GetEvents(sc.key(in.Name), time.Time{}, time.Time{}, 0)
+		if err != nil {
+			err = status.Error(codes.NotFound, err.Error())
+			return nil, err
+		}
+		for _, e := range events {
+			vals = append(vals, e.Value)
+			t = append(t, e.Time)
+		}
+	}
+
+	err = st.Refit(vals, t)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	err = srv.DB.UpdateStream(sc.key(in.Name), st)
+	srv.cache.forget(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(updateCode(err), err.Error())
+		return nil, err
+	}
+
+	return sc.proto(st), nil
+}
+
+// SampleForecast draws sample paths from a stream's forecast distribution.
+func (srv *Server) SampleForecast(c context.Context, in *seer.SampleForecastRequest) (s *seer.Samples, err error) {
+	if int64(in.N)*int64(in.NumPaths) > maxSamples {
+		err = fmt.Errorf("n * num_paths must be at most %v, but was %v", maxSamples, int64(in.N)*int64(in.NumPaths))
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+	times, paths, err := st.Sample(int(in.N), int(in.NumPaths), in.Seed)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	protoTimes := make([]*timestamp.Timestamp, len(times))
+	for i := range times {
+		protoTimes[i], _ = ptypes.TimestampProto(times[i])
+	}
+
+	protoPaths := make([]*seer.Path, len(paths))
+	for i := range paths {
+		protoPaths[i] = &seer.Path{Values: paths[i]}
+	}
+	s = &seer.Samples{
+		Times: protoTimes,
+		Paths: protoPaths,
+	}
+	return s, nil
+}
+
+// GetEvents returns a page of a stream's retained events in the requested time
+// range. The page token is the time of the first event in the next page.
+func (srv *Server) GetEvents(c context.Context, in *seer.GetEventsRequest) (ev *seer.GetEventsResponse, err error) {
+	size := int(in.PageSize)
+	if size == 0 {
+		size = defaultEventPageSize
+	}
+	if size < 0 || size > maxEventPageSize {
+		err = fmt.Errorf("page_size must be between 0 and %v, but was %v", maxEventPageSize, size)
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	var from, to time.Time
+	if in.StartTime != nil {
+		from, err = ptypes.Timestamp(in.StartTime)
+	}
+	if err == nil && in.EndTime != nil {
+		to, err = ptypes.Timestamp(in.EndTime)
+	}
+	if err == nil && in.PageToken != "" {
+		from, err = time.Parse(time.RFC3339Nano, in.PageToken)
+		if err != nil {
+			err = fmt.Errorf("invalid page_token %q", in.PageToken)
+		}
+	}
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	events, err := srv.DB.GetEvents(sc.key(in.Name), from, to, size+1)
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+
+	ev = &seer.GetEventsResponse{Event: &seer.Event{}}
+	if len(events) > size {
+		ev.NextPageToken = events[size].Time.Format(time.RFC3339Nano)
+		events = events[:size]
+	}
+	for _, e := range events {
+		ts, _ := ptypes.TimestampProto(e.Time)
+		ev.Event.Times = append(ev.Event.Times, ts)
+		ev.Event.Values = append(ev.Event.Values, e.Value)
+	}
+	return ev, nil
+}
+
+// RebuildStream rebuilds a stream from its retained events, optionally with a
+// new configuration, and streams the progress of the rebuild. The final
+// message contains the rebuilt stream.
+func (srv *Server) RebuildStream(in *seer.RebuildStreamRequest, rs seer.Seer_RebuildStreamServer) (err error) {
+	sc, err := srv.scopeOf(rs.Context())
+	if err != nil {
+		return err
+	}
+	var conf *stream.Config
+	if in.Stream != nil {
+		if in.Stream.Name == "" {
+			in.Stream.Name = in.Name
+		}
+		conf, err = streamConfig(in.Stream)
+		if err != nil {
+			err = status.Error(codes.InvalidArgument, err.Error())
+			return err
+		}
+		conf.Name = sc.key(conf.Name)
+	}
+
+	p := &seer.RebuildProgress{}
+	st, err := srv.Rebuild(sc.key(in.Name), conf, func(done, total int) error {
+		p.EventsReplayed = int64(done)
+		p.EventsTotal = int64(total)
+		return rs.Send(p)
+	})
+	if err != nil {
+		return err
+	}
+	p.Stream = sc.proto(st)
+	return rs.Send(p)
+}
+
+// rollbackCode returns the status code for an error from the store's
+// RollbackStream. A stream whose period has changed since its snapshot can't
+// be rolled back until it is changed back.
+func rollbackCode(err error) codes.Code {
+	switch err.(type) {
+	case *store.NotFoundError:
+		return codes.NotFound
+	case *store.PeriodError:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// RollbackStream restores a stream's model and time from its latest snapshot
+// at or before the requested time, keeping its current config, and discards
+// later snapshots and retained events, so that corrected events can be sent.
+func (srv *Server) RollbackStream(c context.Context, in *seer.RollbackStreamRequest) (s *seer.Stream, err error) {
+	if in.Time == nil {
+		err = status.Error(codes.InvalidArgument, "time must be set")
+		return nil, err
+	}
+	to, err := ptypes.Timestamp(in.Time)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	st, err := srv.DB.RollbackStream(sc.key(in.Name), to)
+	srv.cache.forget(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(rollbackCode(err), err.Error())
+		return nil, err
+	}
+	return sc.proto(st), nil
+}
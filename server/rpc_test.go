@@ -19,19 +19,29 @@ package server_test
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 
 	"github.com/cshenton/seer/seer"
 	"github.com/cshenton/seer/server"
+	"github.com/cshenton/seer/store"
 	"github.com/cshenton/seer/stream"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func setUp(t *testing.T) (srv *server.Server) {
-	srv, err := server.New(testPath(t))
+	srv, err := server.New(server.Config{Path: testPath(t)})
 	if err != nil {
 		t.Fatal("unexpected error in server.New:", err)
 	}
@@ -147,6 +157,42 @@ func TestGetStreamErrs(t *testing.T) {
 	}
 }
 
+// racyStore updates every stream it gets, as if a concurrent request did.
+type racyStore struct {
+	store.StreamStore
+}
+
+func (r *racyStore) GetStream(name string) (s *stream.Stream, err error) {
+	s, err = r.StreamStore.GetStream(name)
+	if err != nil {
+		return nil, err
+	}
+	other, _ := r.StreamStore.GetStream(name)
+	r.StreamStore.UpdateStream(name, other)
+	return s, nil
+}
+
+func TestUpdateStreamConflict(t *testing.T) {
+	srv := setUp(t)
+	srv.DB = &racyStore{srv.DB}
+
+	tm, _ := ptypes.TimestampProto(time.Now())
+	in := &seer.UpdateStreamRequest{
+		Name: "sales",
+		Event: &seer.Event{
+			Values: []float64{3.15},
+			Times:  []*timestamp.Timestamp{tm},
+		},
+	}
+	s, err := srv.UpdateStream(context.Background(), in)
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("expected code %v, but got %v", codes.Aborted, status.Code(err))
+	}
+	if s != nil {
+		t.Error("expected nil stream, but it was", s)
+	}
+}
+
 func TestDeleteStream(t *testing.T) {
 	srv := setUp(t)
 
@@ -288,22 +334,152 @@ func TestListStreams(t *testing.T) {
 	}
 }
 
-func TestListStreamsErrs(t *testing.T) {
+func TestListStreamsPages(t *testing.T) {
 	srv := setUp(t)
 
-	var pageNum int32 = 10
-	var pageSize int32 = 5
+	var names []string
+	in := &seer.ListStreamsRequest{PageSize: 2}
+	for i := 0; ; i++ {
+		s, err := srv.ListStreams(context.Background(), in)
+		if err != nil {
+			t.Fatal("unexpected error in ListStreams:", err)
+		}
+		if s.TotalSize != 3 {
+			t.Errorf("expected total size %v, but it was %v", 3, s.TotalSize)
+		}
+		for _, st := range s.Streams {
+			names = append(names, st.Name)
+		}
+		if s.NextPageToken == "" {
+			break
+		}
+		if i > 3 {
+			t.Fatal("expected pages to end, but they did not")
+		}
+		in.PageToken = s.NextPageToken
+	}
+	if len(names) != 3 || names[0] != "sales" || names[2] != "visits" {
+		t.Errorf("expected all streams in name order, but got %v", names)
+	}
+}
 
-	in := &seer.ListStreamsRequest{
-		PageSize:   pageSize,
-		PageNumber: pageNum,
+func TestListStreamsOrder(t *testing.T) {
+	srv := setUp(t)
+	for i, name := range []string{"visits", "sales"} {
+		tm, _ := ptypes.TimestampProto(time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC))
+		srv.UpdateStream(context.Background(), &seer.UpdateStreamRequest{
+			Name:  name,
+			Event: &seer.Event{Values: []float64{1}, Times: []*timestamp.Timestamp{tm}},
+		})
 	}
-	s, err := srv.ListStreams(context.Background(), in)
-	if err == nil {
-		t.Error("expected error, but it was nil")
+
+	tt := []struct {
+		name string
+		in   *seer.ListStreamsRequest
+		want []string
+	}{
+		{"by last event time", &seer.ListStreamsRequest{Order: seer.ListOrder_BY_LAST_EVENT_TIME}, []string{"usage", "visits", "sales"}},
+		{"most recent first", &seer.ListStreamsRequest{Order: seer.ListOrder_BY_LAST_EVENT_TIME, Descending: true}, []string{"sales", "visits", "usage"}},
+		{"prefix", &seer.ListStreamsRequest{Prefix: "s"}, []string{"sales"}},
+		{"empty", &seer.ListStreamsRequest{Prefix: "none"}, []string{}},
+		{"past the last page", &seer.ListStreamsRequest{PageSize: 5, PageNumber: 10}, []string{}},
 	}
-	if s != nil {
-		t.Error("expected nil streamlist, but got ", s)
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := srv.ListStreams(context.Background(), tc.in)
+			if err != nil {
+				t.Fatal("unexpected error in ListStreams:", err)
+			}
+			names := []string{}
+			for _, st := range s.Streams {
+				names = append(names, st.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tc.want) {
+				t.Errorf("expected streams %v, but got %v", tc.want, names)
+			}
+		})
+	}
+}
+
+func TestListStreamsLabels(t *testing.T) {
+	srv := setUp(t)
+	for _, name := range []string{"signups", "churn", "revenue"} {
+		_, err := srv.CreateStream(context.Background(), &seer.CreateStreamRequest{
+			Stream: &seer.Stream{
+				Name:   name,
+				Period: 3600,
+				Labels: map[string]string{"team": "growth", "tier": name[:1]},
+			},
+		})
+		if err != nil {
+			t.Fatal("unexpected error in CreateStream:", err)
+		}
+	}
+
+	var names []string
+	in := &seer.ListStreamsRequest{PageSize: 2, LabelSelector: "team=growth, tier!=r"}
+	for i := 0; ; i++ {
+		s, err := srv.ListStreams(context.Background(), in)
+		if err != nil {
+			t.Fatal("unexpected error in ListStreams:", err)
+		}
+		if s.TotalSize != 2 {
+			t.Errorf("expected total size %v, but it was %v", 2, s.TotalSize)
+		}
+		for _, st := range s.Streams {
+			names = append(names, st.Name)
+			if st.Labels["team"] != "growth" {
+				t.Errorf("expected labels of stream %v, but got %v", st.Name, st.Labels)
+			}
+		}
+		if s.NextPageToken == "" {
+			break
+		}
+		if i > 3 {
+			t.Fatal("expected pages to end, but they did not")
+		}
+		in.PageToken = s.NextPageToken
+		in.LabelSelector = "tier!=r,team==growth"
+	}
+	if fmt.Sprint(names) != fmt.Sprint([]string{"churn", "signups"}) {
+		t.Errorf("expected labelled streams, but got %v", names)
+	}
+
+	_, err := srv.CreateStream(context.Background(), &seer.CreateStreamRequest{
+		Stream: &seer.Stream{Name: "bad", Period: 3600, Labels: map[string]string{"bad key": "v"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code %v for invalid labels, but got %v", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestListStreamsErrs(t *testing.T) {
+	srv := setUp(t)
+	first, _ := srv.ListStreams(context.Background(), &seer.ListStreamsRequest{PageSize: 1})
+
+	tt := []struct {
+		name string
+		in   *seer.ListStreamsRequest
+	}{
+		{"negative page size", &seer.ListStreamsRequest{PageSize: -1}},
+		{"large page size", &seer.ListStreamsRequest{PageSize: 1000000}},
+		{"bad order", &seer.ListStreamsRequest{Order: 7}},
+		{"bad token", &seer.ListStreamsRequest{PageToken: "notatoken"}},
+		{"token for another prefix", &seer.ListStreamsRequest{PageToken: first.NextPageToken, Prefix: "s"}},
+		{"token for another order", &seer.ListStreamsRequest{PageToken: first.NextPageToken, Descending: true}},
+		{"token for another selector", &seer.ListStreamsRequest{PageToken: first.NextPageToken, LabelSelector: "team"}},
+		{"bad selector", &seer.ListStreamsRequest{LabelSelector: "team in (growth"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := srv.ListStreams(context.Background(), tc.in)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+			}
+			if s != nil {
+				t.Error("expected nil streamlist, but got ", s)
+			}
+		})
 	}
 }
 
@@ -378,3 +554,780 @@ func TestGetForecastErrs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetForecastAggregate(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name   string
+		agg    seer.Aggregation
+		window int32
+	}{
+		{"none", seer.Aggregation_NONE, 0},
+		{"sum", seer.Aggregation_SUM, 24},
+		{"mean", seer.Aggregation_MEAN, 24},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &seer.GetForecastRequest{
+				Name:        "sales",
+				N:           7,
+				Aggregation: tc.agg,
+				Window:      tc.window,
+			}
+			f, err := srv.GetForecast(context.Background(), in)
+			if err != nil {
+				t.Fatal("unexpected error in GetForecast:", err)
+			}
+			if len(f.Values) != 7 {
+				t.Errorf("expected %v values, but got %v", 7, len(f.Values))
+			}
+		})
+	}
+}
+
+func TestGetForecastAggregateErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name   string
+		agg    seer.Aggregation
+		window int32
+	}{
+		{"missing window", seer.Aggregation_SUM, 0},
+		{"window without aggregation", seer.Aggregation_NONE, 24},
+		{"unknown aggregation", seer.Aggregation(9), 24},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &seer.GetForecastRequest{
+				Name:        "sales",
+				N:           7,
+				Aggregation: tc.agg,
+				Window:      tc.window,
+			}
+			f, err := srv.GetForecast(context.Background(), in)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if f != nil {
+				t.Error("expected nil forecast, but got", f)
+			}
+		})
+	}
+}
+
+func TestGetForecastSpan(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 24)
+	day := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+	stamp := func(h int) *timestamp.Timestamp {
+		ts, _ := ptypes.TimestampProto(day.Add(time.Duration(h) * time.Hour))
+		return ts
+	}
+	full, err := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 24})
+	if err != nil {
+		t.Fatal("unexpected error in GetForecast:", err)
+	}
+
+	tt := []struct {
+		name   string
+		in     *seer.GetForecastRequest
+		offset int
+		n      int
+	}{
+		{"start and n", &seer.GetForecastRequest{StartTime: stamp(9), N: 4}, 9, 4},
+		{"start and end", &seer.GetForecastRequest{StartTime: stamp(9), EndTime: stamp(17)}, 9, 9},
+		{"end only", &seer.GetForecastRequest{EndTime: stamp(5)}, 0, 6},
+		{"past start", &seer.GetForecastRequest{StartTime: stamp(-5), N: 2}, 0, 2},
+		{"windows", &seer.GetForecastRequest{StartTime: stamp(9), EndTime: stamp(17), Aggregation: seer.Aggregation_SUM, Window: 4}, -1, 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.in.Name = "history"
+			f, err := srv.GetForecast(context.Background(), tc.in)
+			if err != nil {
+				t.Fatal("unexpected error in GetForecast:", err)
+			}
+			if len(f.Values) != tc.n || len(f.Times) != tc.n {
+				t.Fatalf("expected %v values and times, but got %v and %v", tc.n, len(f.Values), len(f.Times))
+			}
+			if tc.offset < 0 {
+				if f.Times[tc.n-1].Seconds != stamp(16).Seconds {
+					t.Errorf("expected last window to end at %v, but got %v", stamp(16), f.Times[tc.n-1])
+				}
+				return
+			}
+			for i := range f.Values {
+				if f.Times[i].Seconds != full.Times[tc.offset+i].Seconds {
+					t.Errorf("expected time %v at %v, but got %v", full.Times[tc.offset+i], i, f.Times[i])
+				}
+				if math.Abs(f.Values[i]-full.Values[tc.offset+i]) > 1e-6 {
+					t.Errorf("expected value %v at %v, but got %v", full.Values[tc.offset+i], i, f.Values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetForecastSpanErrs(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 24)
+	day := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+	stamp := func(h int) *timestamp.Timestamp {
+		ts, _ := ptypes.TimestampProto(day.Add(time.Duration(h) * time.Hour))
+		return ts
+	}
+
+	tt := []struct {
+		name string
+		in   *seer.GetForecastRequest
+	}{
+		{"n and end", &seer.GetForecastRequest{Name: "history", StartTime: stamp(9), EndTime: stamp(17), N: 4}},
+		{"end before start", &seer.GetForecastRequest{Name: "history", StartTime: stamp(9), EndTime: stamp(5)}},
+		{"end within a window", &seer.GetForecastRequest{Name: "history", StartTime: stamp(9), EndTime: stamp(10), Aggregation: seer.Aggregation_SUM, Window: 4}},
+		{"missing window", &seer.GetForecastRequest{Name: "history", EndTime: stamp(10), Aggregation: seer.Aggregation_SUM}},
+		{"too long", &seer.GetForecastRequest{Name: "history", EndTime: stamp(24 * 365 * 20)}},
+		{"no events", &seer.GetForecastRequest{Name: "sales", StartTime: stamp(9), N: 4}},
+		{"bad start", &seer.GetForecastRequest{Name: "history", StartTime: &timestamp.Timestamp{Nanos: -1}, N: 4}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := srv.GetForecast(context.Background(), tc.in)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected %v, but got %v", codes.InvalidArgument, err)
+			}
+			if f != nil {
+				t.Error("expected nil forecast, but got", f)
+			}
+		})
+	}
+}
+
+func TestRefitStream(t *testing.T) {
+	srv := setUp(t)
+
+	n := 20
+	values := make([]float64, n)
+	times := make([]*timestamp.Timestamp, n)
+	for i := range times {
+		values[i] = float64(i % 5)
+		times[i], _ = ptypes.TimestampProto(time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC))
+	}
+	in := &seer.RefitStreamRequest{
+		Name: "sales",
+		Event: &seer.Event{
+			Values: values,
+			Times:  times,
+		},
+	}
+	s, err := srv.RefitStream(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in RefitStream:", err)
+	}
+	if s.Name != "sales" {
+		t.Errorf("expected name %v, but got %v", "sales", s.Name)
+	}
+
+	st, _ := srv.DB.GetStream("sales")
+	if st.Model.Deterministic.Params == nil {
+		t.Error("expected fitted params to be stored, but they were nil")
+	}
+}
+
+func TestRefitStreamWalk(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+	old, _ := srv.DB.GetStream("history")
+
+	_, err := srv.RefitStream(context.Background(), &seer.RefitStreamRequest{Name: "history"})
+	if err != nil {
+		t.Fatal("unexpected error in RefitStream:", err)
+	}
+	st, _ := srv.DB.GetStream("history")
+	if st.Model.RCE.Walk() != old.Model.RCE.Walk() {
+		t.Errorf("expected walk %v, but got %v", old.Model.RCE.Walk(), st.Model.RCE.Walk())
+	}
+}
+
+func TestRefitStreamErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tm, _ := ptypes.TimestampProto(time.Now())
+	tt := []struct {
+		name  string
+		event *seer.Event
+	}{
+		{"notastream", &seer.Event{Values: []float64{1}, Times: []*timestamp.Timestamp{tm}}},
+		{"sales", &seer.Event{Values: []float64{1}, Times: []*timestamp.Timestamp{tm}}},
+		{"sales", nil},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &seer.RefitStreamRequest{
+				Name:  tc.name,
+				Event: tc.event,
+			}
+			s, err := srv.RefitStream(context.Background(), in)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if s != nil {
+				t.Error("expected nil stream, but it was", s)
+			}
+		})
+	}
+}
+
+// historyStream creates a stream which retains its events, and updates it
+// with n hourly events.
+func historyStream(t *testing.T, srv *server.Server, n int) {
+	_, err := srv.CreateStream(context.Background(), &seer.CreateStreamRequest{
+		Stream: &seer.Stream{
+			Name:      "history",
+			Period:    3600,
+			Retention: &seer.Retention{Count: 1000},
+		},
+	})
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	values := make([]float64, n)
+	times := make([]*timestamp.Timestamp, n)
+	for i := range times {
+		values[i] = float64(i % 5)
+		times[i], _ = ptypes.TimestampProto(time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC))
+	}
+	_, err = srv.UpdateStream(context.Background(), &seer.UpdateStreamRequest{
+		Name:  "history",
+		Event: &seer.Event{Values: values, Times: times},
+	})
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+}
+
+func TestCreateStreamRetention(t *testing.T) {
+	srv := setUp(t)
+
+	in := &seer.CreateStreamRequest{
+		Stream: &seer.Stream{
+			Name:      "retained",
+			Period:    3600,
+			Retention: &seer.Retention{Count: 10, Age: ptypes.DurationProto(time.Hour)},
+		},
+	}
+	s, err := srv.CreateStream(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+	if s.Retention == nil || s.Retention.Count != 10 {
+		t.Errorf("expected retention count %v, but got %v", 10, s.Retention)
+	}
+
+	in.Stream.Name = "negative"
+	in.Stream.Retention.Count = -1
+	_, err = srv.CreateStream(context.Background(), in)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestRefitStreamHistory(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 20)
+
+	_, err := srv.RefitStream(context.Background(), &seer.RefitStreamRequest{Name: "history"})
+	if err != nil {
+		t.Fatal("unexpected error in RefitStream:", err)
+	}
+
+	st, _ := srv.DB.GetStream("history")
+	if st.Model.Deterministic.Params == nil {
+		t.Error("expected fitted params to be stored, but they were nil")
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	var (
+		values []float64
+		pages  int
+	)
+	in := &seer.GetEventsRequest{Name: "history", PageSize: 10}
+	for {
+		ev, err := srv.GetEvents(context.Background(), in)
+		if err != nil {
+			t.Fatal("unexpected error in GetEvents:", err)
+		}
+		values = append(values, ev.Event.Values...)
+		pages++
+		if ev.NextPageToken == "" {
+			break
+		}
+		in.PageToken = ev.NextPageToken
+	}
+
+	if pages != 3 {
+		t.Errorf("expected %v pages, but there were %v", 3, pages)
+	}
+	if len(values) != 25 {
+		t.Fatalf("expected %v events, but there were %v", 25, len(values))
+	}
+	for i := range values {
+		if values[i] != float64(i%5) {
+			t.Errorf("expected value %v at %v, but got %v", float64(i%5), i, values[i])
+		}
+	}
+}
+
+func TestGetEventsErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name string
+		in   *seer.GetEventsRequest
+		code codes.Code
+	}{
+		{"not a stream", &seer.GetEventsRequest{Name: "notastream"}, codes.NotFound},
+		{"negative page size", &seer.GetEventsRequest{Name: "sales", PageSize: -1}, codes.InvalidArgument},
+		{"large page size", &seer.GetEventsRequest{Name: "sales", PageSize: 1e6}, codes.InvalidArgument},
+		{"bad page token", &seer.GetEventsRequest{Name: "sales", PageToken: "notatoken"}, codes.InvalidArgument},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.GetEvents(context.Background(), tc.in)
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestRollbackStream(t *testing.T) {
+	srv := setUp(t)
+	_, err := srv.CreateStream(context.Background(), &seer.CreateStreamRequest{
+		Stream: &seer.Stream{
+			Name:      "snapshots",
+			Period:    3600,
+			Snapshots: &seer.SnapshotPolicy{Count: 24, Interval: ptypes.DurationProto(time.Hour)},
+		},
+	})
+	if err != nil {
+		t.Fatal("unexpected error in CreateStream:", err)
+	}
+
+	times := make([]*timestamp.Timestamp, 5)
+	for i := range times {
+		times[i], _ = ptypes.TimestampProto(time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC))
+		_, err = srv.UpdateStream(context.Background(), &seer.UpdateStreamRequest{
+			Name:  "snapshots",
+			Event: &seer.Event{Values: []float64{float64(i)}, Times: times[i : i+1]},
+		})
+		if err != nil {
+			t.Fatal("unexpected error in UpdateStream:", err)
+		}
+	}
+
+	// Config changes made after the snapshot are kept.
+	_, err = srv.PatchStream(context.Background(), &seer.PatchStreamRequest{
+		Name:       "snapshots",
+		Stream:     &seer.Stream{Labels: map[string]string{"team": "ops"}},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		t.Fatal("unexpected error in PatchStream:", err)
+	}
+
+	s, err := srv.RollbackStream(context.Background(), &seer.RollbackStreamRequest{Name: "snapshots", Time: times[2]})
+	if err != nil {
+		t.Fatal("unexpected error in RollbackStream:", err)
+	}
+	if !proto.Equal(s.LastEventTime, times[2]) {
+		t.Errorf("expected last event time %v, but got %v", times[2], s.LastEventTime)
+	}
+	if s.Snapshots == nil || s.Snapshots.Count != 24 {
+		t.Errorf("expected snapshot policy count %v, but got %v", 24, s.Snapshots)
+	}
+	if s.Labels["team"] != "ops" {
+		t.Errorf("expected labels to be kept, but got %v", s.Labels)
+	}
+
+	// Snapshots at another period can't be rolled back to.
+	_, err = srv.PatchStream(context.Background(), &seer.PatchStreamRequest{
+		Name:       "snapshots",
+		Stream:     &seer.Stream{Period: 86400},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"period"}},
+	})
+	if err != nil {
+		t.Fatal("unexpected error in PatchStream:", err)
+	}
+	_, err = srv.RollbackStream(context.Background(), &seer.RollbackStreamRequest{Name: "snapshots", Time: times[1]})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected code %v, but got %v", codes.FailedPrecondition, status.Code(err))
+	}
+}
+
+func TestRollbackStreamErrs(t *testing.T) {
+	srv := setUp(t)
+	tm, _ := ptypes.TimestampProto(time.Now())
+
+	tt := []struct {
+		name string
+		in   *seer.RollbackStreamRequest
+		code codes.Code
+	}{
+		{"no time", &seer.RollbackStreamRequest{Name: "sales"}, codes.InvalidArgument},
+		{"not a stream", &seer.RollbackStreamRequest{Name: "notastream", Time: tm}, codes.NotFound},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.RollbackStream(context.Background(), tc.in)
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
+
+// rebuildServer records the progress sent by RebuildStream.
+type rebuildServer struct {
+	grpc.ServerStream
+	sent []seer.RebuildProgress
+}
+
+func (r *rebuildServer) Send(p *seer.RebuildProgress) error {
+	r.sent = append(r.sent, *p)
+	return nil
+}
+
+func (r *rebuildServer) Context() context.Context {
+	return context.Background()
+}
+
+func TestRebuildStream(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	in := &seer.RebuildStreamRequest{
+		Name:   "history",
+		Stream: &seer.Stream{Period: 3600, Domain: seer.Domain_CONTINUOUS_RIGHT, Retention: &seer.Retention{}},
+	}
+	rs := &rebuildServer{}
+	err := srv.RebuildStream(in, rs)
+	if err != nil {
+		t.Fatal("unexpected error in RebuildStream:", err)
+	}
+
+	if len(rs.sent) != 2 {
+		t.Fatalf("expected %v messages, but there were %v", 2, len(rs.sent))
+	}
+	last := rs.sent[len(rs.sent)-1]
+	if last.EventsReplayed != 25 || last.EventsTotal != 25 {
+		t.Errorf("expected %v of %v events replayed, but got %v of %v", 25, 25, last.EventsReplayed, last.EventsTotal)
+	}
+	if last.Stream == nil || last.Stream.Domain != seer.Domain_CONTINUOUS_RIGHT {
+		t.Errorf("expected rebuilt stream in final message, but got %v", last.Stream)
+	}
+}
+
+func TestRebuildStreamErrs(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	tt := []struct {
+		name string
+		in   *seer.RebuildStreamRequest
+		code codes.Code
+	}{
+		{"not a stream", &seer.RebuildStreamRequest{Name: "notastream"}, codes.NotFound},
+		{"bad config", &seer.RebuildStreamRequest{Name: "history", Stream: &seer.Stream{Period: 0}}, codes.InvalidArgument},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := srv.RebuildStream(tc.in, &rebuildServer{})
+			if status.Code(err) != tc.code {
+				t.Errorf("expected code %v, but got %v", tc.code, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestSampleForecast(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name  string
+		n     int32
+		paths int32
+	}{
+		{"visits", 1, 1},
+		{"usage", 35, 20},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, _ := ptypes.TimestampProto(time.Now())
+			uin := &seer.UpdateStreamRequest{
+				Name: tc.name,
+				Event: &seer.Event{
+					Values: []float64{3.15},
+					Times:  []*timestamp.Timestamp{tm},
+				},
+			}
+			_, err := srv.UpdateStream(context.Background(), uin)
+			if err != nil {
+				t.Fatal("unexpected error in UpdateStream:", err)
+			}
+			in := &seer.SampleForecastRequest{
+				Name:     tc.name,
+				N:        tc.n,
+				NumPaths: tc.paths,
+				Seed:     42,
+			}
+			s, err := srv.SampleForecast(context.Background(), in)
+			if err != nil {
+				t.Fatal("unexpected error in SampleForecast:", err)
+			}
+			if len(s.Times) != int(tc.n) {
+				t.Errorf("expected %v times, but got %v", tc.n, len(s.Times))
+			}
+			if len(s.Paths) != int(tc.paths) {
+				t.Fatalf("expected %v paths, but got %v", tc.paths, len(s.Paths))
+			}
+			if len(s.Paths[0].Values) != int(tc.n) {
+				t.Errorf("expected %v values, but got %v", tc.n, len(s.Paths[0].Values))
+			}
+		})
+	}
+}
+
+func TestSampleForecastErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name  string
+		n     int32
+		paths int32
+	}{
+		{"notastream", 10, 10},
+		{"sales", -5, 10},
+		{"sales", 10, 0},
+		{"sales", 10000, 10000},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &seer.SampleForecastRequest{
+				Name:     tc.name,
+				N:        tc.n,
+				NumPaths: tc.paths,
+			}
+			s, err := srv.SampleForecast(context.Background(), in)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if s != nil {
+				t.Error("expected nil samples, but got", s)
+			}
+		})
+	}
+}
+
+// requestStream is a server stream whose requests are made in the context c,
+// or the default namespace if it is nil.
+type requestStream struct {
+	grpc.ServerStream
+	c context.Context
+}
+
+func (r *requestStream) Context() context.Context {
+	if r.c == nil {
+		return context.Background()
+	}
+	return r.c
+}
+
+// backupServer records the chunks sent by Backup.
+type backupServer struct {
+	requestStream
+	data []byte
+	sent int
+}
+
+func (b *backupServer) Send(c *seer.BackupChunk) error {
+	b.data = append(b.data, c.Data...)
+	b.sent++
+	return nil
+}
+
+// restoreServer sends a backup to Restore in chunks, and records its response.
+type restoreServer struct {
+	requestStream
+	data []byte
+	size int
+	resp *seer.RestoreResponse
+}
+
+func (r *restoreServer) Recv() (*seer.BackupChunk, error) {
+	if len(r.data) == 0 {
+		return nil, io.EOF
+	}
+	n := r.size
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	c := &seer.BackupChunk{Data: r.data[:n]}
+	r.data = r.data[n:]
+	return c, nil
+}
+
+func (r *restoreServer) SendAndClose(resp *seer.RestoreResponse) error {
+	r.resp = resp
+	return nil
+}
+
+func TestBackupRestore(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 25)
+
+	bs := &backupServer{}
+	err := srv.Backup(&empty.Empty{}, bs)
+	if err != nil {
+		t.Fatal("unexpected error in Backup:", err)
+	}
+	if bs.sent != 1 {
+		t.Errorf("expected %v chunk, but there were %v", 1, bs.sent)
+	}
+
+	tt := []struct {
+		name string
+		size int
+	}{
+		{"single chunk", len(bs.data)},
+		{"small chunks", 7},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, _ := server.New(server.Config{Backend: server.Memory})
+			rs := &restoreServer{data: bs.data, size: tc.size}
+			err := dst.Restore(rs)
+			if err != nil {
+				t.Fatal("unexpected error in Restore:", err)
+			}
+			if rs.resp.Streams != 4 {
+				t.Errorf("expected %v streams restored, but there were %v", 4, rs.resp.Streams)
+			}
+			_, err = dst.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+			if err != nil {
+				t.Error("unexpected error in GetStream:", err)
+			}
+		})
+	}
+}
+
+func TestRestoreErrs(t *testing.T) {
+	srv := setUp(t)
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not a backup", []byte("not a backup")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := srv.Restore(&restoreServer{data: tc.data, size: 4})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected code %v, but got %v", codes.InvalidArgument, status.Code(err))
+			}
+		})
+	}
+}
+
+// exportServer records the chunks sent by ExportStreams.
+type exportServer struct {
+	requestStream
+	data []byte
+}
+
+func (e *exportServer) Send(c *seer.ExportChunk) error {
+	e.data = append(e.data, c.Data...)
+	return nil
+}
+
+// importServer sends streams to ImportStreams in chunks, and records its
+// response.
+type importServer struct {
+	requestStream
+	format seer.ExportFormat
+	data   []byte
+	size   int
+	resp   *seer.ImportStreamsResponse
+}
+
+func (i *importServer) Recv() (*seer.ImportStreamsRequest, error) {
+	if len(i.data) == 0 {
+		return nil, io.EOF
+	}
+	n := i.size
+	if n > len(i.data) {
+		n = len(i.data)
+	}
+	in := &seer.ImportStreamsRequest{Format: i.format, Data: i.data[:n]}
+	i.data = i.data[n:]
+	return in, nil
+}
+
+func (i *importServer) SendAndClose(resp *seer.ImportStreamsResponse) error {
+	i.resp = resp
+	return nil
+}
+
+func TestExportImportStreams(t *testing.T) {
+	tt := []struct {
+		name   string
+		format seer.ExportFormat
+	}{
+		{"json", seer.ExportFormat_JSON},
+		{"csv", seer.ExportFormat_CSV},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := setUp(t)
+			historyStream(t, srv, 25)
+
+			es := &exportServer{}
+			err := srv.ExportStreams(&seer.ExportStreamsRequest{Format: tc.format, History: true}, es)
+			if err != nil {
+				t.Fatal("unexpected error in ExportStreams:", err)
+			}
+
+			dst, _ := server.New(server.Config{Backend: server.Memory})
+			is := &importServer{format: tc.format, data: es.data, size: 16}
+			err = dst.ImportStreams(is)
+			if err != nil {
+				t.Fatal("unexpected error in ImportStreams:", err)
+			}
+			if is.resp.Streams != 4 {
+				t.Errorf("expected %v streams imported, but there were %v", 4, is.resp.Streams)
+			}
+			_, err = dst.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+			if err != nil {
+				t.Error("unexpected error in GetStream:", err)
+			}
+		})
+	}
+}
+
+func TestImportStreamsErrs(t *testing.T) {
+	srv := setUp(t)
+
+	is := &importServer{format: seer.ExportFormat_CSV, data: []byte("name,period\nsales,3600\n"), size: 4}
+	err := srv.ImportStreams(is)
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected code %v, but got %v", codes.AlreadyExists, status.Code(err))
+	}
+}
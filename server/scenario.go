@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cshenton/seer/seer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxScenarioEvents bounds the number of hypothetical values a single
+// ScenarioForecast may apply.
+const maxScenarioEvents = 10000
+
+// ScenarioForecast applies hypothetical events to a copy of the stream, and
+// forecasts from the result as GetForecast would. Nothing is written to the
+// store, and scenario forecasts are never cached, as they don't follow from
+// the stream's revision.
+func (srv *Server) ScenarioForecast(c context.Context, in *seer.ScenarioForecastRequest) (f *seer.Forecast, err error) {
+	sc, err := srv.scopeOf(c)
+	if err != nil {
+		return nil, err
+	}
+	n := len(in.Event.GetValues())
+	if n == 0 {
+		err = fmt.Errorf("event must have at least one value")
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	if n > maxScenarioEvents {
+		err = fmt.Errorf("event may have at most %v values, but had %v", maxScenarioEvents, n)
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	st, err := srv.DB.GetStream(sc.key(in.Name))
+	if err != nil {
+		err = status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+
+	// The store decodes a new stream on every read, so updating it leaves the
+	// stored stream untouched.
+	err = st.Update(in.Event.Values, eventTimes(in.Event))
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	fin := &seer.GetForecastRequest{
+		Name:        in.Name,
+		N:           in.N,
+		Aggregation: in.Aggregation,
+		Window:      in.Window,
+		StartTime:   in.StartTime,
+		EndTime:     in.EndTime,
+	}
+	skip, fn, err := forecastSpan(st, fin)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	f, err = forecast(st, skip, fn, in.Aggregation, int(in.Window), defaultProbabilities)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package server_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/seer"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestScenarioForecast(t *testing.T) {
+	srv := setUpCache(t, 10)
+	ev := hourlyEvent(time.Date(2016, 1, 2, 1, 0, 0, 0, time.UTC), 3)
+	for i := range ev.Values {
+		ev.Values[i] = 20
+	}
+	before, _ := srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+	base, err := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 5})
+	if err != nil {
+		t.Fatal("unexpected error in GetForecast:", err)
+	}
+
+	f, err := srv.ScenarioForecast(context.Background(), &seer.ScenarioForecastRequest{Name: "history", Event: ev, N: 5})
+	if err != nil {
+		t.Fatal("unexpected error in ScenarioForecast:", err)
+	}
+	if len(f.Values) != 5 {
+		t.Fatalf("expected %v values, but got %v", 5, len(f.Values))
+	}
+	if f.Times[0].Seconds != base.Times[3].Seconds {
+		t.Errorf("expected the scenario forecast to start at %v, but got %v", base.Times[3], f.Times[0])
+	}
+
+	// The scenario forecast is the forecast the stream would give after the
+	// events, but the stream and its cached forecast are unchanged.
+	_, err = srv.CloneStream(context.Background(), &seer.CloneStreamRequest{Source: "history", Destination: "planned"})
+	if err != nil {
+		t.Fatal("unexpected error in CloneStream:", err)
+	}
+	_, err = srv.UpdateStream(context.Background(), &seer.UpdateStreamRequest{Name: "planned", Event: ev})
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	planned, _ := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "planned", N: 5})
+	for i := range f.Values {
+		if math.Abs(f.Values[i]-planned.Values[i]) > 1e-6 {
+			t.Errorf("expected value %v at %v, but got %v", planned.Values[i], i, f.Values[i])
+		}
+	}
+
+	after, _ := srv.GetStream(context.Background(), &seer.GetStreamRequest{Name: "history"})
+	if after.Revision != before.Revision || after.LastEventTime.Seconds != before.LastEventTime.Seconds {
+		t.Errorf("expected the stream to be unchanged, but it was %v", after)
+	}
+	again, _ := srv.GetForecast(context.Background(), &seer.GetForecastRequest{Name: "history", N: 5})
+	if again != base {
+		t.Error("expected the stream's forecast to remain cached, but it was not")
+	}
+}
+
+func TestScenarioForecastSpan(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 24)
+	in := &seer.ScenarioForecastRequest{
+		Name:      "history",
+		Event:     hourlyEvent(time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC), 3),
+		StartTime: &timestamp.Timestamp{Seconds: time.Date(2016, 1, 2, 9, 0, 0, 0, time.UTC).Unix()},
+		EndTime:   &timestamp.Timestamp{Seconds: time.Date(2016, 1, 2, 17, 0, 0, 0, time.UTC).Unix()},
+	}
+	f, err := srv.ScenarioForecast(context.Background(), in)
+	if err != nil {
+		t.Fatal("unexpected error in ScenarioForecast:", err)
+	}
+	if len(f.Values) != 9 || f.Times[0].Seconds != in.StartTime.Seconds || f.Times[8].Seconds != in.EndTime.Seconds {
+		t.Errorf("expected %v values from %v to %v, but got %v", 9, in.StartTime, in.EndTime, f.Times)
+	}
+}
+
+func TestScenarioForecastErrs(t *testing.T) {
+	srv := setUp(t)
+	historyStream(t, srv, 24)
+	start := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name string
+		in   *seer.ScenarioForecastRequest
+		code codes.Code
+	}{
+		{"missing stream", &seer.ScenarioForecastRequest{Name: "missing", Event: hourlyEvent(start, 3), N: 5}, codes.NotFound},
+		{"no event", &seer.ScenarioForecastRequest{Name: "history", N: 5}, codes.InvalidArgument},
+		{"too many values", &seer.ScenarioForecastRequest{Name: "history", Event: hourlyEvent(start, 10001), N: 5}, codes.InvalidArgument},
+		{"past events", &seer.ScenarioForecastRequest{Name: "history", Event: hourlyEvent(start.Add(-5*time.Hour), 3), N: 5}, codes.InvalidArgument},
+		{"bad n", &seer.ScenarioForecastRequest{Name: "history", Event: hourlyEvent(start, 3), N: -1}, codes.InvalidArgument},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := srv.ScenarioForecast(context.Background(), tc.in)
+			if status.Code(err) != tc.code {
+				t.Errorf("expected %v, but got %v", tc.code, err)
+			}
+			if f != nil {
+				t.Error("expected nil forecast, but got", f)
+			}
+		})
+	}
+}
@@ -18,20 +18,62 @@
 package server
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/cshenton/seer/store"
 	"github.com/cshenton/seer/store/bolt"
+	"github.com/cshenton/seer/store/memory"
+	"github.com/cshenton/seer/store/sqlite"
+)
+
+// Backends that may be selected to store streams.
+const (
+	Bolt   = "bolt"
+	Memory = "memory"
+	SQLite = "sqlite"
 )
 
-// Server fulfills the protocol buffer's SeerServer interface.
+// Config determines the store backing a Server. Path is the bolt or sqlite
+// database file, or for the memory backend the optional snapshot file, which
+// is written every SnapshotInterval. ForecastCacheSize is the number of
+// forecasts the server caches, or if not positive, forecasts are not cached.
+type Config struct {
+	Backend           string
+	Path              string
+	SnapshotInterval  time.Duration
+	ForecastCacheSize int
+}
+
+// Server fulfills the protocol buffer's SeerServer interface. Stream creation
+// and namespace changes are serialised by mu, the points sent to each
+// namespace are limited by limits, and recent forecasts are held in cache.
 type Server struct {
 	DB store.StreamStore
+
+	mu     sync.Mutex
+	limits limiter
+	cache  *forecastCache
 }
 
-// New creates a database connection and returns a Server.
-func New(path string) (srv *Server, err error) {
-	db, err := bolt.New(path)
+// New creates a store from the config and returns a Server using it. The
+// backend defaults to bolt.
+func New(conf Config) (srv *Server, err error) {
+	var db store.StreamStore
+
+	switch conf.Backend {
+	case Bolt, "":
+		db, err = bolt.New(conf.Path)
+	case Memory:
+		db, err = memory.New(conf.Path, conf.SnapshotInterval)
+	case SQLite:
+		db, err = sqlite.New(conf.Path)
+	default:
+		err = fmt.Errorf("backend must be one of %v, %v or %v, but was %v", Bolt, Memory, SQLite, conf.Backend)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return &Server{DB: db}, nil
+	return &Server{DB: db, cache: newForecastCache(conf.ForecastCacheSize)}, nil
 }
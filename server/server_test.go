@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cshenton/seer/server"
 )
@@ -34,15 +35,40 @@ func testPath(t *testing.T) string {
 }
 
 func TestNew(t *testing.T) {
-	_, err := server.New(testPath(t))
-	if err != nil {
-		t.Fatal("unexpected error in server.New:", err)
+	tt := []struct {
+		name string
+		conf server.Config
+	}{
+		{"default", server.Config{Path: testPath(t)}},
+		{"bolt", server.Config{Backend: server.Bolt, Path: testPath(t)}},
+		{"memory", server.Config{Backend: server.Memory}},
+		{"sqlite", server.Config{Backend: server.SQLite, Path: testPath(t)}},
+		{"memory with snapshots", server.Config{Backend: server.Memory, Path: testPath(t), SnapshotInterval: time.Minute}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := server.New(tc.conf)
+			if err != nil {
+				t.Fatal("unexpected error in server.New:", err)
+			}
+		})
 	}
 }
 
 func TestNewErrs(t *testing.T) {
-	_, err := server.New("/$$$NOPE!!")
-	if err == nil {
-		t.Error("expected error, but it was nil")
+	tt := []struct {
+		name string
+		conf server.Config
+	}{
+		{"bad path", server.Config{Path: "/$$$NOPE!!"}},
+		{"unknown backend", server.Config{Backend: "postgres"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := server.New(tc.conf)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
 	}
 }
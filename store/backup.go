@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package store
+
+import (
+	"context"
+
+	"github.com/cshenton/seer/stream"
+)
+
+// Record is a stream with its retained events and snapshots, as backed up and
+// restored. Backups also hold a record for each namespace, with only the
+// namespace set, which stores do not restore.
+type Record struct {
+	Stream    *stream.Stream
+	Events    []*stream.Event
+	Snapshots []*stream.Stream
+	Namespace *Namespace
+}
+
+// Rename names the record's stream, and the streams in its snapshots, name.
+func (r *Record) Rename(name string) {
+	r.Stream.Config.Name = name
+	for _, s := range r.Snapshots {
+		s.Config.Name = name
+	}
+}
+
+// Backup calls fn with the record of every stream in the current context
+// store.
+func Backup(c context.Context, fn func(r *Record) error) (err error) {
+	return streamFromContext(c).Backup(fn)
+}
+
+// RestoreStream saves the record's stream using the current context store.
+func RestoreStream(c context.Context, r *Record) (err error) {
+	return streamFromContext(c).RestoreStream(r)
+}
+
+// GetRecord returns the record of the named stream using the current context
+// store.
+func GetRecord(c context.Context, name string) (r *Record, err error) {
+	return streamFromContext(c).GetRecord(name)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package store_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/memory"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/store/sqlite"
+	"github.com/cshenton/seer/stream"
+)
+
+func TestBackup(t *testing.T) {
+	c := setUp(t)
+
+	n := 0
+	err := store.Backup(c, func(r *store.Record) error {
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Error("unexpected error in Backup:", err)
+	}
+	if n != 3 {
+		t.Errorf("expected %v records, but there were %v", 3, n)
+	}
+}
+
+func TestRestoreStream(t *testing.T) {
+	c := setUp(t)
+
+	s, _ := stream.New("test", 3600, 0, 0, 0)
+	err := store.RestoreStream(c, &store.Record{Stream: s})
+	if err != nil {
+		t.Error("unexpected error in RestoreStream:", err)
+	}
+	_, err = store.GetStream(c, "test")
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
+
+func TestGetRecord(t *testing.T) {
+	c := setUp(t)
+
+	r, err := store.GetRecord(c, "sales")
+	if err != nil {
+		t.Error("unexpected error in GetRecord:", err)
+	}
+	if r.Stream.Config.Name != "sales" {
+		t.Errorf("expected stream %v, but it was %v", "sales", r.Stream.Config.Name)
+	}
+}
+
+func TestRestoreAcrossStores(t *testing.T) {
+	c := setUp(t)
+	s, _ := store.GetStream(c, "sales")
+	s.Config.Retention = &stream.Retention{}
+	times := []time.Time{time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.Update([]float64{1}, times)
+	events, _ := stream.Events([]float64{1}, times)
+	store.UpdateStream(c, "sales", s, events...)
+
+	buf := &bytes.Buffer{}
+	w := schema.NewWriter(buf)
+	err := store.Backup(c, w.Write)
+	if err != nil {
+		t.Fatal("unexpected error in Backup:", err)
+	}
+	w.Close()
+
+	mem, _ := memory.New("", 0)
+	defer mem.Close()
+	lite, err := sqlite.New(testPath(t))
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	defer lite.Close()
+
+	tt := []struct {
+		name string
+		db   store.StreamStore
+	}{
+		{"memory", mem},
+		{"sqlite", lite},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := schema.NewReader(bytes.NewReader(buf.Bytes()))
+			for {
+				rec, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal("unexpected error in Read:", err)
+				}
+				err = tc.db.RestoreStream(rec)
+				if err != nil {
+					t.Fatal("unexpected error in RestoreStream:", err)
+				}
+			}
+
+			st, err := tc.db.GetStream("sales")
+			if err != nil {
+				t.Fatal("unexpected error in GetStream:", err)
+			}
+			if st.Revision != s.Revision {
+				t.Errorf("expected revision %v, but it was %v", s.Revision, st.Revision)
+			}
+			e, _ := tc.db.GetEvents("sales", time.Time{}, time.Time{}, 0)
+			if len(e) != 1 {
+				t.Errorf("expected %v events, but there were %v", 1, len(e))
+			}
+			l, _, _ := tc.db.ListStreams(&store.ListOptions{})
+			if len(l) != 3 {
+				t.Errorf("expected %v streams, but there were %v", 3, len(l))
+			}
+		})
+	}
+}
+
+func TestRecordRename(t *testing.T) {
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	snap, _ := stream.New("sales", 3600, 0, 0, 0)
+	r := &store.Record{Stream: s, Snapshots: []*stream.Stream{snap}}
+
+	r.Rename("revenue")
+	if s.Config.Name != "revenue" || snap.Config.Name != "revenue" {
+		t.Errorf("expected names %v, but got %v and %v", "revenue", s.Config.Name, snap.Config.Name)
+	}
+}
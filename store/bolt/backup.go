@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bolt
+
+import (
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+
+	// Avoid namespace conflicts
+	blt "github.com/boltdb/bolt"
+)
+
+// Backup calls fn with the record of every stream, in name order, from a
+// single read transaction, so the backup is consistent while the store is in
+// use. It stops at the first error from fn.
+func (b *Store) Backup(fn func(r *store.Record) error) (err error) {
+	err = b.View(func(tx *blt.Tx) error {
+		c := tx.Bucket(streamBucket).Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			r, err := readRecord(tx, string(k))
+			if err != nil {
+				return err
+			}
+			err = fn(r)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return err
+}
+
+// GetRecord returns the record of the stream at name, with its events and
+// snapshots, or an error if no such stream exists.
+func (b *Store) GetRecord(name string) (r *store.Record, err error) {
+	err = b.View(func(tx *blt.Tx) error {
+		r, err = readRecord(tx, name)
+		return err
+	})
+
+	return r, err
+}
+
+// readRecord reads the stream at name, and its events and snapshots.
+func readRecord(tx *blt.Tx, name string) (r *store.Record, err error) {
+	key := []byte(name)
+	val := tx.Bucket(streamBucket).Get(key)
+	if val == nil {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	s, _, err := decodeStream(name, val)
+	if err != nil {
+		return nil, err
+	}
+	r = &store.Record{Stream: s}
+
+	if eb := tx.Bucket(eventBucket).Bucket(key); eb != nil {
+		eb.ForEach(func(k, v []byte) error {
+			r.Events = append(r.Events, decodeEvent(k, v))
+			return nil
+		})
+	}
+	if sb := tx.Bucket(snapshotBucket).Bucket(key); sb != nil {
+		err = sb.ForEach(func(k, v []byte) error {
+			snap, _, err := decodeStream(name, v)
+			if err != nil {
+				return err
+			}
+			r.Snapshots = append(r.Snapshots, snap)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// RestoreStream saves the record's stream at its name, with its events and
+// snapshots, replacing any stream stored there.
+func (b *Store) RestoreStream(r *store.Record) (err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		err := deleteStream(tx, r.Stream.Config.Name)
+		if err != nil {
+			return err
+		}
+		return putRecord(tx, r)
+	})
+
+	return err
+}
+
+// putRecord saves the record's stream at its name, with its events and
+// snapshots, where no stream is stored.
+func putRecord(tx *blt.Tx, r *store.Record) (err error) {
+	name := []byte(r.Stream.Config.Name)
+	val, err := schema.Marshal(r.Stream)
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(streamBucket).Put(name, val)
+	if err != nil {
+		return err
+	}
+	err = indexLabels(tx, string(name), nil, r.Stream.Config.Labels)
+	if err != nil {
+		return err
+	}
+
+	if len(r.Events) > 0 {
+		eb, err := tx.Bucket(eventBucket).CreateBucket(name)
+		if err != nil {
+			return err
+		}
+		for _, e := range r.Events {
+			err = eb.Put(eventKey(e.Time), eventValue(e))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(r.Snapshots) > 0 {
+		sb, err := tx.Bucket(snapshotBucket).CreateBucket(name)
+		if err != nil {
+			return err
+		}
+		for _, s := range r.Snapshots {
+			val, err := schema.Marshal(s)
+			if err != nil {
+				return err
+			}
+			err = sb.Put(eventKey(s.Time), val)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
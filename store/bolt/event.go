@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+
+	// Avoid namespace conflicts
+	blt "github.com/boltdb/bolt"
+)
+
+// eventBucket is the key for the bucket holding each stream's event bucket.
+var eventBucket = []byte("events")
+
+// eventKey encodes a time as a key that sorts in time order, by flipping the
+// sign bit of its unix nanoseconds.
+func eventKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano())^(1<<63))
+	return k
+}
+
+// keyTime decodes a key encoded by eventKey.
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)^(1<<63))).UTC()
+}
+
+// eventValue encodes an event's value.
+func eventValue(e *stream.Event) []byte {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, math.Float64bits(e.Value))
+	return v
+}
+
+// decodeEvent decodes an event stored at key k.
+func decodeEvent(k, v []byte) (e *stream.Event) {
+	return &stream.Event{
+		Time:  keyTime(k),
+		Value: math.Float64frombits(binary.BigEndian.Uint64(v)),
+	}
+}
+
+// putEvents adds the events to the stream's history, then discards events
+// outside its retention policy. If the stream has no policy, its history is
+// removed.
+func putEvents(tx *blt.Tx, name string, s *stream.Stream, events []*stream.Event) (err error) {
+	bk := tx.Bucket(eventBucket)
+	r := s.Config.Retention
+	if r == nil {
+		if bk.Bucket([]byte(name)) == nil {
+			return nil
+		}
+		return bk.DeleteBucket([]byte(name))
+	}
+
+	eb, err := bk.CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		err = eb.Put(eventKey(e.Time), eventValue(e))
+		if err != nil {
+			return err
+		}
+	}
+
+	var expired [][]byte
+	c := eb.Cursor()
+	if cutoff := r.Cutoff(s.Time); !cutoff.IsZero() {
+		end := eventKey(cutoff)
+		for k, _ := c.First(); k != nil && bytes.Compare(k, end) < 0; k, _ = c.Next() {
+			expired = append(expired, append([]byte{}, k...))
+		}
+	}
+	if r.Count > 0 {
+		k, _ := c.Last()
+		for i := 1; k != nil && i < r.Count; i++ {
+			k, _ = c.Prev()
+		}
+		if k != nil {
+			for k, _ = c.Prev(); k != nil; k, _ = c.Prev() {
+				expired = append(expired, append([]byte{}, k...))
+			}
+		}
+	}
+	for _, k := range expired {
+		err = eb.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEvents returns up to limit of the stream's retained events in [from, to),
+// in time order, where a zero from or to is unbounded, and a non-positive
+// limit returns all of them. It returns an error if no stream exists at name.
+func (b *Store) GetEvents(name string, from, to time.Time, limit int) (e []*stream.Event, err error) {
+	err = b.View(func(tx *blt.Tx) error {
+		if tx.Bucket(streamBucket).Get([]byte(name)) == nil {
+			return &store.NotFoundError{Kind: "stream", Entity: name}
+		}
+		eb := tx.Bucket(eventBucket).Bucket([]byte(name))
+		if eb == nil {
+			return nil
+		}
+
+		c := eb.Cursor()
+		k, v := c.First()
+		if !from.IsZero() {
+			k, v = c.Seek(eventKey(from))
+		}
+		var end []byte
+		if !to.IsZero() {
+			end = eventKey(to)
+		}
+		for ; k != nil; k, v = c.Next() {
+			if end != nil && bytes.Compare(k, end) >= 0 {
+				break
+			}
+			if limit > 0 && len(e) >= limit {
+				break
+			}
+			e = append(e, decodeEvent(k, v))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bolt
+
+import (
+	"bytes"
+
+	"github.com/cshenton/seer/label"
+
+	// Avoid namespace conflicts
+	blt "github.com/boltdb/bolt"
+)
+
+// labelBucket is the key for the label index bucket, which has an empty value
+// for each label of each stream, at its labelKey.
+var labelBucket = []byte("labels")
+
+// labelKey returns the index key of a label of the named stream, its key,
+// value and stream name, each followed by a zero byte, which label keys and
+// values cannot contain.
+func labelKey(key, value, name string) []byte {
+	return []byte(key + "\x00" + value + "\x00" + name + "\x00")
+}
+
+// indexLabels updates the label index of the stream at name from its old to
+// its new labels.
+func indexLabels(tx *blt.Tx, name string, old, new map[string]string) (err error) {
+	bk := tx.Bucket(labelBucket)
+	for k, v := range old {
+		if nv, ok := new[k]; ok && nv == v {
+			continue
+		}
+		err = bk.Delete(labelKey(k, v, name))
+		if err != nil {
+			return err
+		}
+	}
+	for k, v := range new {
+		if ov, ok := old[k]; ok && ov == v {
+			continue
+		}
+		err = bk.Put(labelKey(k, v, name), []byte{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storedLabels returns the labels of the stream stored at name, or nil if
+// there is no stream, or it cannot be decoded.
+func storedLabels(tx *blt.Tx, name string) (labels map[string]string) {
+	val := tx.Bucket(streamBucket).Get([]byte(name))
+	if val == nil {
+		return nil
+	}
+	s, err := decodeMeta(name, val)
+	if err != nil {
+		return nil
+	}
+	return s.Config.Labels
+}
+
+// selectNames returns the names of the streams indexed with labels that meet
+// the selector's =, in and exists requirements, and false if it has none. The
+// names may include deleted streams, and streams that fail its other
+// requirements, so must be checked.
+func selectNames(tx *blt.Tx, sel label.Selector) (names map[string]bool, ok bool) {
+	c := tx.Bucket(labelBucket).Cursor()
+	for _, r := range sel {
+		var prefixes [][]byte
+		switch r.Operator {
+		case label.Equals, label.In:
+			for _, v := range r.Values {
+				prefixes = append(prefixes, []byte(r.Key+"\x00"+v+"\x00"))
+			}
+		case label.Exists:
+			prefixes = append(prefixes, []byte(r.Key+"\x00"))
+		default:
+			continue
+		}
+
+		found := make(map[string]bool)
+		for _, p := range prefixes {
+			for k, _ := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+				parts := bytes.Split(k, []byte{0})
+				name := string(parts[len(parts)-2])
+				if !ok || names[name] {
+					found[name] = true
+				}
+			}
+		}
+		names, ok = found, true
+	}
+	return names, ok
+}
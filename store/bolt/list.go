@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bolt
+
+import (
+	"bytes"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+
+	// Avoid namespace conflicts
+	blt "github.com/boltdb/bolt"
+)
+
+// ListStreams returns the streams selected by the options, without their
+// models, and the total number of streams with the prefix and matching labels.
+// Streams listed in name order are read from the cursor's position, while
+// those listed by last event time, or by label, are sorted from all streams
+// with the prefix, or those found in the label index.
+func (b *Store) ListStreams(opts *store.ListOptions) (s []*stream.Stream, total int, err error) {
+	prefix := []byte(opts.Prefix)
+	err = b.View(func(tx *blt.Tx) error {
+		bk := tx.Bucket(streamBucket)
+		c := bk.Cursor()
+
+		if names, ok := selectNames(tx, opts.Selector); ok {
+			var all []*stream.Stream
+			for name := range names {
+				v := bk.Get([]byte(name))
+				if v == nil || !bytes.HasPrefix([]byte(name), prefix) {
+					continue
+				}
+				st, err := decodeMeta(name, v)
+				if err != nil {
+					return err
+				}
+				all = append(all, st)
+			}
+			s, total = opts.Page(all)
+			return nil
+		}
+
+		if opts.Order == store.ByLastEvent || len(opts.Selector) > 0 {
+			var all []*stream.Stream
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				st, err := decodeMeta(string(k), v)
+				if err != nil {
+					return err
+				}
+				all = append(all, st)
+			}
+			s, total = opts.Page(all)
+			return nil
+		}
+
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			total++
+		}
+		k, v := start(c, opts)
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = step(c, opts) {
+			if opts.Limit > 0 && len(s) >= opts.Limit {
+				break
+			}
+			st, err := decodeMeta(string(k), v)
+			if err != nil {
+				return err
+			}
+			s = append(s, st)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return s, total, nil
+}
+
+// start positions the cursor at the first stream listed in name order.
+func start(c *blt.Cursor, opts *store.ListOptions) (k, v []byte) {
+	prefix := []byte(opts.Prefix)
+	var after []byte
+	if opts.After != nil {
+		after = []byte(opts.After.Name)
+	}
+
+	if !opts.Descending {
+		if after == nil || bytes.Compare(after, prefix) < 0 {
+			return c.Seek(prefix)
+		}
+		k, v = c.Seek(after)
+		if bytes.Equal(k, after) {
+			k, v = c.Next()
+		}
+		return k, v
+	}
+
+	// Descending lists start before the lesser of the cursor and the end of
+	// the prefix range.
+	end := prefixEnd(prefix)
+	if after != nil && (end == nil || bytes.Compare(after, end) < 0) {
+		end = after
+	}
+	if end == nil {
+		return c.Last()
+	}
+	k, _ = c.Seek(end)
+	if k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+// step moves the cursor to the next stream listed in name order.
+func step(c *blt.Cursor, opts *store.ListOptions) (k, v []byte) {
+	if opts.Descending {
+		return c.Prev()
+	}
+	return c.Next()
+}
+
+// prefixEnd returns the least key greater than every key with the prefix, or
+// nil if there is none.
+func prefixEnd(prefix []byte) (end []byte) {
+	end = append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bolt
+
+import (
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+
+	// Avoid namespace conflicts
+	blt "github.com/boltdb/bolt"
+)
+
+// namespaceBucket is the key for the namespace bucket.
+var namespaceBucket = []byte("namespaces")
+
+// decodeNamespace decodes the namespace stored at name.
+func decodeNamespace(name string, val []byte) (ns *store.Namespace, err error) {
+	ns, err = schema.UnmarshalNamespace(val)
+	if err != nil {
+		return nil, &store.CorruptDataError{Kind: "namespace", Entity: name, Err: err}
+	}
+	return ns, nil
+}
+
+// CreateNamespace saves the provided namespace, or returns an error if a
+// namespace with its name already exists.
+func (b *Store) CreateNamespace(ns *store.Namespace) (err error) {
+	val, err := schema.MarshalNamespace(ns)
+	if err != nil {
+		return err
+	}
+	err = b.Update(func(tx *blt.Tx) error {
+		bk := tx.Bucket(namespaceBucket)
+
+		if bk.Get([]byte(ns.Name)) != nil {
+			return &store.AlreadyExistsError{Kind: "namespace", Entity: ns.Name}
+		}
+		return bk.Put([]byte(ns.Name), val)
+	})
+
+	return err
+}
+
+// GetNamespace returns the namespace stored at name, or an error if it does
+// not exist.
+func (b *Store) GetNamespace(name string) (ns *store.Namespace, err error) {
+	err = b.View(func(tx *blt.Tx) error {
+		val := tx.Bucket(namespaceBucket).Get([]byte(name))
+		if val == nil {
+			return &store.NotFoundError{Kind: "namespace", Entity: name}
+		}
+		ns, err = decodeNamespace(name, val)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// UpdateNamespace overwrites the stored namespace with the provided one, or
+// returns an error if it does not exist.
+func (b *Store) UpdateNamespace(ns *store.Namespace) (err error) {
+	val, err := schema.MarshalNamespace(ns)
+	if err != nil {
+		return err
+	}
+	err = b.Update(func(tx *blt.Tx) error {
+		bk := tx.Bucket(namespaceBucket)
+
+		if bk.Get([]byte(ns.Name)) == nil {
+			return &store.NotFoundError{Kind: "namespace", Entity: ns.Name}
+		}
+		return bk.Put([]byte(ns.Name), val)
+	})
+
+	return err
+}
+
+// DeleteNamespace deletes the namespace stored at name, or returns an error if
+// it does not exist.
+func (b *Store) DeleteNamespace(name string) (err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		bk := tx.Bucket(namespaceBucket)
+
+		if bk.Get([]byte(name)) == nil {
+			return &store.NotFoundError{Kind: "namespace", Entity: name}
+		}
+		return bk.Delete([]byte(name))
+	})
+
+	return err
+}
+
+// ListNamespaces returns every namespace, in name order.
+func (b *Store) ListNamespaces() (ns []*store.Namespace, err error) {
+	err = b.View(func(tx *blt.Tx) error {
+		return tx.Bucket(namespaceBucket).ForEach(func(k, v []byte) error {
+			n, err := decodeNamespace(string(k), v)
+			if err != nil {
+				return err
+			}
+			ns = append(ns, n)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bolt
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+
+	// Avoid namespace conflicts
+	blt "github.com/boltdb/bolt"
+)
+
+// snapshotBucket is the key for the bucket holding each stream's snapshot
+// bucket, which maps stream times to encoded streams.
+var snapshotBucket = []byte("snapshots")
+
+// putSnapshot keeps the stored stream old, encoded as val, as a snapshot if
+// one is due under the policy, then discards the oldest snapshots beyond the
+// policy's count. If there is no policy, the stream's snapshots are removed.
+func putSnapshot(tx *blt.Tx, name string, p *stream.SnapshotPolicy, old *stream.Stream, val []byte) (err error) {
+	bk := tx.Bucket(snapshotBucket)
+	if p == nil {
+		if bk.Bucket([]byte(name)) == nil {
+			return nil
+		}
+		return bk.DeleteBucket([]byte(name))
+	}
+
+	sb, err := bk.CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return err
+	}
+	c := sb.Cursor()
+	var latest time.Time
+	if k, _ := c.Last(); k != nil {
+		latest = keyTime(k)
+	}
+	if !p.Due(old.Time, latest) {
+		return nil
+	}
+	err = sb.Put(eventKey(old.Time), append([]byte{}, val...))
+	if err != nil {
+		return err
+	}
+
+	var expired [][]byte
+	k, _ := c.Last()
+	for i := 1; k != nil && i < p.Count; i++ {
+		k, _ = c.Prev()
+	}
+	if k != nil {
+		for k, _ = c.Prev(); k != nil; k, _ = c.Prev() {
+			expired = append(expired, append([]byte{}, k...))
+		}
+	}
+	for _, k := range expired {
+		err = sb.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAfter deletes the keys of the bucket after key, if it exists.
+func deleteAfter(bk *blt.Bucket, key []byte) (err error) {
+	if bk == nil {
+		return nil
+	}
+	var after [][]byte
+	c := bk.Cursor()
+	for k, _ := c.Seek(key); k != nil; k, _ = c.Next() {
+		if bytes.Compare(k, key) > 0 {
+			after = append(after, append([]byte{}, k...))
+		}
+	}
+	for _, k := range after {
+		err = bk.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RollbackStream restores the model and time of the stream at name from its
+// latest snapshot at or before to, keeping its current config, and discards
+// its later snapshots and events. It returns an error if no stream exists at
+// name, it has no such snapshot, or the snapshot is at another period. The
+// restored stream takes the next revision. If the stream is not after to, it
+// is unchanged.
+func (b *Store) RollbackStream(name string, to time.Time) (s *stream.Stream, err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		bk := tx.Bucket(streamBucket)
+
+		val := bk.Get([]byte(name))
+		if val == nil {
+			return &store.NotFoundError{Kind: "stream", Entity: name}
+		}
+		cur, _, err := decodeStream(name, val)
+		if err != nil {
+			return err
+		}
+		if !cur.Time.After(to) {
+			s = cur
+			return nil
+		}
+
+		sb := tx.Bucket(snapshotBucket).Bucket([]byte(name))
+		if sb == nil {
+			return &store.NotFoundError{Kind: "snapshot", Entity: name}
+		}
+		key := eventKey(to)
+		c := sb.Cursor()
+		k, v := c.Seek(key)
+		if k == nil {
+			k, v = c.Last()
+		} else if bytes.Compare(k, key) > 0 {
+			k, v = c.Prev()
+		}
+		if k == nil {
+			return &store.NotFoundError{Kind: "snapshot", Entity: name}
+		}
+
+		snap, _, err := decodeStream(name, v)
+		if err != nil {
+			return err
+		}
+		s, err = store.Rollback(cur, snap)
+		if err != nil {
+			return err
+		}
+		val, err = schema.Marshal(s)
+		if err != nil {
+			return err
+		}
+		err = bk.Put([]byte(name), val)
+		if err != nil {
+			return err
+		}
+
+		err = deleteAfter(sb, k)
+		if err != nil {
+			return err
+		}
+		return deleteAfter(tx.Bucket(eventBucket).Bucket([]byte(name)), eventKey(s.Time))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
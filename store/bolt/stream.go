@@ -19,8 +19,8 @@ package bolt
 
 import (
 	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
 	"github.com/cshenton/seer/stream"
-	"github.com/vmihailenco/msgpack"
 
 	// Avoid namespace conflicts
 	blt "github.com/boltdb/bolt"
@@ -29,37 +29,66 @@ import (
 // streamBucket is the key for the stream bucket.
 var streamBucket = []byte("streams")
 
-// streamInit idempotently sets up the store to be ready to store streams.
+// streamInit idempotently sets up the store to be ready to store streams, with
+// their events, snapshots and label index, and namespaces.
 func (b *Store) streamInit() {
 	b.Update(func(tx *blt.Tx) error {
 		tx.CreateBucketIfNotExists(streamBucket)
+		tx.CreateBucketIfNotExists(eventBucket)
+		tx.CreateBucketIfNotExists(snapshotBucket)
+		tx.CreateBucketIfNotExists(labelBucket)
+		tx.CreateBucketIfNotExists(namespaceBucket)
 		return nil
 	})
 }
 
+// decodeStream decodes the stream stored at name, and whether it was stored
+// at an old schema version.
+func decodeStream(name string, val []byte) (s *stream.Stream, old bool, err error) {
+	s, v, err := schema.Unmarshal(val)
+	if err != nil {
+		return nil, false, &store.CorruptDataError{Kind: "stream", Entity: name, Err: err}
+	}
+	return s, v < schema.Version, nil
+}
+
+// decodeMeta decodes the stream stored at name, without its model.
+func decodeMeta(name string, val []byte) (s *stream.Stream, err error) {
+	s, err = schema.UnmarshalMeta(val)
+	if err != nil {
+		return nil, &store.CorruptDataError{Kind: "stream", Entity: name, Err: err}
+	}
+	return s, nil
+}
+
 // CreateStream saves the provided stream at name, returns an error if a
 // stream already exists at that address.
 func (b *Store) CreateStream(name string, s *stream.Stream) (err error) {
+	val, err := schema.Marshal(s)
+	if err != nil {
+		return err
+	}
 	err = b.Update(func(tx *blt.Tx) error {
 		bk := tx.Bucket(streamBucket)
 
-		val := bk.Get([]byte(name))
-		if val != nil {
+		if bk.Get([]byte(name)) != nil {
 			return &store.AlreadyExistsError{Kind: "stream", Entity: name}
 		}
-
-		val, _ = msgpack.Marshal(s)
 		err := bk.Put([]byte(name), val)
-		return err
+		if err != nil {
+			return err
+		}
+		return indexLabels(tx, name, nil, s.Config.Labels)
 	})
 
 	return err
 }
 
 // GetStream returns the stream stored at name, or an error if the stream does
-// not exist, or has corrupted data.
+// not exist, or has corrupted data. Streams stored at an old schema version
+// are upgraded to the current version.
 func (b *Store) GetStream(name string) (s *stream.Stream, err error) {
-	s = &stream.Stream{}
+	var old bool
 
 	err = b.View(func(tx *blt.Tx) error {
 		bk := tx.Bucket(streamBucket)
@@ -68,85 +97,251 @@ func (b *Store) GetStream(name string) (s *stream.Stream, err error) {
 		if val == nil {
 			return &store.NotFoundError{Kind: "stream", Entity: name}
 		}
-		err = msgpack.Unmarshal(val, s)
+		s, old, err = decodeStream(name, val)
 		return err
 	})
+	if err != nil {
+		return nil, err
+	}
 
+	if old {
+		err = b.upgradeStream(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// GetStreams returns the streams stored at names, in order, from a single
+// read transaction, with nil where no stream is stored. Streams stored at an
+// old schema version are decoded, but not upgraded.
+func (b *Store) GetStreams(names []string) (s []*stream.Stream, err error) {
+	s = make([]*stream.Stream, len(names))
+	err = b.View(func(tx *blt.Tx) error {
+		bk := tx.Bucket(streamBucket)
+		for i, name := range names {
+			val := bk.Get([]byte(name))
+			if val == nil {
+				continue
+			}
+			s[i], _, err = decodeStream(name, val)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return s, nil
 }
 
-// DeleteStream deletes the stream stored at name, or returns an error if
-// no such stream exists.
-func (b *Store) DeleteStream(name string) (err error) {
+// upgradeStream rewrites the stream stored at name at the current schema
+// version, if it is stored at an old version.
+func (b *Store) upgradeStream(name string) (err error) {
 	err = b.Update(func(tx *blt.Tx) error {
 		bk := tx.Bucket(streamBucket)
 
 		val := bk.Get([]byte(name))
 		if val == nil {
-			return &store.NotFoundError{Kind: "stream", Entity: name}
+			return nil
 		}
-
-		err := bk.Delete([]byte(name))
-		return err
+		s, old, err := decodeStream(name, val)
+		if err != nil || !old {
+			return err
+		}
+		val, err = schema.Marshal(s)
+		if err != nil {
+			return err
+		}
+		return bk.Put([]byte(name), val)
 	})
 
 	return err
 }
 
-// UpdateStream overwrites the stream at name with the provided stream, or
-// returns an error if no stream exists at name.
-func (b *Store) UpdateStream(name string, s *stream.Stream) (err error) {
+// DeleteStream deletes the stream stored at name, and its events and
+// snapshots, or returns an error if no such stream exists.
+func (b *Store) DeleteStream(name string) (err error) {
 	err = b.Update(func(tx *blt.Tx) error {
-		bk := tx.Bucket(streamBucket)
+		if tx.Bucket(streamBucket).Get([]byte(name)) == nil {
+			return &store.NotFoundError{Kind: "stream", Entity: name}
+		}
+		return deleteStream(tx, name)
+	})
 
-		val := bk.Get([]byte(name))
+	return err
+}
+
+// ExpireStream deletes the stream stored at name, and its events and
+// snapshots, if it is at the provided revision. It returns an error if no
+// such stream exists, or if it has been updated since that revision.
+func (b *Store) ExpireStream(name string, revision uint64) (err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		val := tx.Bucket(streamBucket).Get([]byte(name))
 		if val == nil {
 			return &store.NotFoundError{Kind: "stream", Entity: name}
 		}
+		s, _, err := decodeStream(name, val)
+		if err != nil {
+			return err
+		}
+		if s.Revision != revision {
+			return &store.ConflictError{Kind: "stream", Entity: name, Revision: revision}
+		}
+		return deleteStream(tx, name)
+	})
 
-		val, _ = msgpack.Marshal(s)
-		err := bk.Put([]byte(name), val)
+	return err
+}
+
+// deleteStream deletes any stream stored at name, with its events, snapshots
+// and labels.
+func deleteStream(tx *blt.Tx, name string) (err error) {
+	err = indexLabels(tx, name, storedLabels(tx, name), nil)
+	if err != nil {
 		return err
+	}
+	err = tx.Bucket(streamBucket).Delete([]byte(name))
+	if err != nil {
+		return err
+	}
+	for _, key := range [][]byte{eventBucket, snapshotBucket} {
+		sub := tx.Bucket(key)
+		if sub.Bucket([]byte(name)) == nil {
+			continue
+		}
+		err = sub.DeleteBucket([]byte(name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameStream moves the stream stored at from, with its events and
+// snapshots, to to. It returns an error if no stream exists at from, or one
+// already exists at to.
+func (b *Store) RenameStream(from, to string) (err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		if tx.Bucket(streamBucket).Get([]byte(to)) != nil {
+			return &store.AlreadyExistsError{Kind: "stream", Entity: to}
+		}
+		r, err := readRecord(tx, from)
+		if err != nil {
+			return err
+		}
+		err = deleteStream(tx, from)
+		if err != nil {
+			return err
+		}
+		r.Rename(to)
+		return putRecord(tx, r)
 	})
 
 	return err
 }
 
-// ListStreams returns a paged list of streams, or an error if none are found.
-func (b *Store) ListStreams(pageNum, pageSize int) (s []*stream.Stream, err error) {
-	err = b.View(func(tx *blt.Tx) error {
-		bk := tx.Bucket(streamBucket)
-		offset := (pageNum - 1) * pageSize
+// CloneStream copies the stream stored at src, with its events and snapshots,
+// to dst, at revision 0. It returns an error if no stream exists at src, or
+// one already exists at dst.
+func (b *Store) CloneStream(src, dst string) (err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		if tx.Bucket(streamBucket).Get([]byte(dst)) != nil {
+			return &store.AlreadyExistsError{Kind: "stream", Entity: dst}
+		}
+		r, err := readRecord(tx, src)
+		if err != nil {
+			return err
+		}
+		r.Rename(dst)
+		r.Stream.Revision = 0
+		return putRecord(tx, r)
+	})
 
-		c := bk.Cursor()
-		i := 0
+	return err
+}
 
-		for k, v := c.First(); k != nil; k, v = c.Next() {
-			if i >= offset+pageSize {
-				break
-			}
-			if i >= offset {
-				st := &stream.Stream{}
-				err = msgpack.Unmarshal(v, st)
-				if err != nil {
-					return err
-				}
-				s = append(s, st)
+// UpdateStream overwrites the stream at name with the provided stream, adds
+// the events to its history, and keeps the stored stream as a snapshot if one
+// is due. It returns an error if no stream exists at name, or if the stored
+// stream is not at the provided stream's revision. On success the revision is
+// incremented.
+func (b *Store) UpdateStream(name string, s *stream.Stream, events ...*stream.Event) (err error) {
+	err = b.Update(func(tx *blt.Tx) error {
+		return updateStream(tx, name, s, events)
+	})
+
+	return err
+}
+
+// UpdateStreams applies each update as UpdateStream does, in a single write
+// transaction. Updates whose stream is missing or at another revision fail
+// alone, with their errors at their positions in errs, while any other error
+// fails them all.
+func (b *Store) UpdateStreams(updates []*store.Update) (errs []error, err error) {
+	errs = make([]error, len(updates))
+	// applied counts the updates tried before the transaction failed, whose
+	// revisions were incremented unless they were skipped.
+	applied := 0
+	err = b.Update(func(tx *blt.Tx) error {
+		for i, u := range updates {
+			errs[i] = updateStream(tx, u.Name, u.Stream, u.Events)
+			if errs[i] != nil && !store.Skippable(errs[i]) {
+				return errs[i]
 			}
-			i++
+			applied = i + 1
 		}
 		return nil
 	})
-
 	if err != nil {
+		for i, u := range updates[:applied] {
+			if errs[i] == nil {
+				u.Stream.Revision--
+			}
+		}
 		return nil, err
 	}
-	if len(s) == 0 {
-		err = &store.NoneFoundError{Kind: "stream"}
-		return nil, err
+	return errs, nil
+}
+
+// updateStream overwrites the stream at name, as UpdateStream, in the
+// transaction. It returns an error before writing anything if no stream exists
+// at name, or if it is at another revision.
+func updateStream(tx *blt.Tx, name string, s *stream.Stream, events []*stream.Event) (err error) {
+	bk := tx.Bucket(streamBucket)
+
+	val := bk.Get([]byte(name))
+	if val == nil {
+		return &store.NotFoundError{Kind: "stream", Entity: name}
 	}
-	return s, nil
+	old, _, err := decodeStream(name, val)
+	if err != nil {
+		return err
+	}
+	if old.Revision != s.Revision {
+		return &store.ConflictError{Kind: "stream", Entity: name, Revision: s.Revision}
+	}
+	err = putSnapshot(tx, name, s.Config.Snapshots, old, val)
+	if err != nil {
+		return err
+	}
+
+	s.Revision++
+	val, err = schema.Marshal(s)
+	if err == nil {
+		err = bk.Put([]byte(name), val)
+	}
+	if err == nil {
+		err = indexLabels(tx, name, old.Config.Labels, s.Config.Labels)
+	}
+	if err == nil {
+		err = putEvents(tx, name, s, events)
+	}
+	if err != nil {
+		s.Revision--
+	}
+	return err
 }
@@ -19,158 +19,119 @@ package bolt_test
 
 import (
 	"testing"
-	"time"
 
 	blt "github.com/boltdb/bolt"
+	"github.com/cshenton/seer/store"
 	"github.com/cshenton/seer/store/bolt"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/store/storetest"
 	"github.com/cshenton/seer/stream"
 	"github.com/vmihailenco/msgpack"
 )
 
-func setUp(t *testing.T) (b *bolt.Store) {
+func newStore(t *testing.T) (b *bolt.Store) {
 	b, err := bolt.New(testPath(t))
 	if err != nil {
 		t.Fatal("unexpected error in bolt.New:", err)
 	}
-	names := []string{"sales", "visits", "usage"}
-
-	for _, n := range names {
-		s, _ := stream.New(n, 3600, 0, 0, 0)
-		err := b.CreateStream(n, s)
-		if err != nil {
-			t.Fatal("unexpected error in CreateStream:", err)
-		}
-	}
-
 	return b
 }
 
-func TestCreateStreamErrs(t *testing.T) {
-	b := setUp(t)
-	defer b.Close()
+func setUp(t *testing.T) (b *bolt.Store) {
+	b = newStore(t)
+	storetest.Fixtures(t, b)
+	return b
+}
 
-	s, _ := stream.New("sales", 3600, 0, 0, 0)
-	err := b.CreateStream("sales", s)
-	if err == nil {
-		t.Error("expected error, but it was nil")
-	}
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.StreamStore { return newStore(t) })
 }
 
-func TestGetStream(t *testing.T) {
+func TestGetStreamLegacy(t *testing.T) {
 	b := setUp(t)
 	defer b.Close()
 
-	tt := []string{"sales", "visits", "usage"}
-
-	for _, name := range tt {
-		t.Run(name, func(t *testing.T) {
-			s, err := b.GetStream(name)
-			if err != nil {
-				t.Error("unexpected error in GetStream:", err)
-			}
-			if s.Config.Name != name {
-				t.Errorf("expected stream name %v, but got %v", name, s.Config.Name)
-			}
-		})
+	s, _ := stream.New("legacy", 3600, 0, 0, 0)
+	err := b.Update(func(tx *blt.Tx) error {
+		data, err := msgpack.Marshal(s)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket([]byte("streams")).Put([]byte("legacy"), data)
+	})
+	if err != nil {
+		t.Fatal("unexpected error while creating legacy data")
 	}
-}
 
-func TestGetStreamErrs(t *testing.T) {
-	b := setUp(t)
-	defer b.Close()
-
-	s, err := b.GetStream("notastream")
-	if err == nil {
-		t.Error("expected error, but it was nil")
+	s, err = b.GetStream("legacy")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
 	}
-	if s != nil {
-		t.Error("expected nil stream, but it was", s)
+	if s.Config.Name != "legacy" {
+		t.Errorf("expected stream name %v, but got %v", "legacy", s.Config.Name)
 	}
-}
-
-func TestDeleteStream(t *testing.T) {
-	b := setUp(t)
-	defer b.Close()
-
-	tt := []string{"sales", "visits", "usage"}
 
-	for _, name := range tt {
-		t.Run(name, func(t *testing.T) {
-			err := b.DeleteStream(name)
-			if err != nil {
-				t.Error("unexpected error in DeleteStream:", err)
-			}
-
-			_, err = b.GetStream(name)
-			if err == nil {
-				t.Error("expected error, but it was nil")
-			}
-		})
-	}
+	b.View(func(tx *blt.Tx) error {
+		data := tx.Bucket([]byte("streams")).Get([]byte("legacy"))
+		if data[0] != schema.Version {
+			t.Errorf("expected record upgraded to version %v, but it was %v", schema.Version, data[0])
+		}
+		return nil
+	})
 }
 
-func TestDeleteStreamErrs(t *testing.T) {
+func TestGetStreamCorrupt(t *testing.T) {
 	b := setUp(t)
 	defer b.Close()
 
-	err := b.DeleteStream("notastream")
-	if err == nil {
-		t.Error("expected error, but it was nil")
+	err := b.Update(func(tx *blt.Tx) error {
+		return tx.Bucket([]byte("streams")).Put([]byte("corrupt"), []byte{schema.Version, 0xff})
+	})
+	if err != nil {
+		t.Fatal("unexpected error while creating corrupt data")
 	}
-}
-
-func TestUpdateStream(t *testing.T) {
-	b := setUp(t)
-	defer b.Close()
 
-	tt := []string{"sales", "visits", "usage"}
-
-	for _, name := range tt {
-		t.Run(name, func(t *testing.T) {
-			s, err := b.GetStream(name)
-			if err != nil {
-				t.Error("unexpected error in GetStream:", err)
-			}
-			s.Update([]float64{3.14}, []time.Time{time.Now()})
-
-			err = b.UpdateStream(name, s)
-			if err != nil {
-				t.Error("unexpected error in UpdateStream:", err)
-			}
-		})
+	_, err = b.GetStream("corrupt")
+	cerr, ok := err.(*store.CorruptDataError)
+	if !ok {
+		t.Fatalf("expected corrupt data error, but got %v", err)
 	}
-}
-
-func TestUpdateStreamErrs(t *testing.T) {
-	b := setUp(t)
-	defer b.Close()
-	name := "notastream"
-
-	s, _ := stream.New(name, 3600, 0, 0, 0)
-	err := b.UpdateStream(name, s)
-	if err == nil {
-		t.Error("expected error, but it was nil")
+	if cerr.Entity != "corrupt" || cerr.Err == nil {
+		t.Errorf("expected details of the corrupt stream, but got %v", cerr)
 	}
 }
 
-func TestListStreams(t *testing.T) {
+func TestUpdateStreamsCorrupt(t *testing.T) {
 	b := setUp(t)
 	defer b.Close()
 
-	num := 1
-	size := 2
-
-	s, err := b.ListStreams(num, size)
+	err := b.Update(func(tx *blt.Tx) error {
+		return tx.Bucket([]byte("streams")).Put([]byte("corrupt"), []byte{schema.Version, 0xff})
+	})
 	if err != nil {
-		t.Fatal("unexpected error in ListStreams:", err)
+		t.Fatal("unexpected error while creating corrupt data")
 	}
 
-	if len(s) != size {
-		t.Errorf("expected %v streams, but there were %v", size, len(s))
+	names := []string{"sales", "corrupt", "visits"}
+	updates := make([]*store.Update, len(names))
+	for i, name := range names {
+		s, _ := stream.New(name, 3600, 0, 0, 0)
+		if name != "corrupt" {
+			s, _ = b.GetStream(name)
+		}
+		updates[i] = &store.Update{Name: name, Stream: s}
 	}
-	for i := range s {
-		if s[i].Config.Period != 3600 {
-			t.Errorf("expected period of %v, but it was %v", 3600, s[i].Config.Period)
+	_, err = b.UpdateStreams(updates)
+	if _, ok := err.(*store.CorruptDataError); !ok {
+		t.Fatalf("expected corrupt data error, but got %v", err)
+	}
+
+	// Streams before and after the corrupt one are left at their stored
+	// revisions, so can still be saved.
+	for _, u := range []*store.Update{updates[0], updates[2]} {
+		err = b.UpdateStream(u.Name, u.Stream)
+		if err != nil {
+			t.Errorf("unexpected error in UpdateStream of %v: %v", u.Name, err)
 		}
 	}
 }
@@ -179,12 +140,7 @@ func TestListStreamsErrs(t *testing.T) {
 	b := setUp(t)
 	defer b.Close()
 
-	_, err := b.ListStreams(20, 10)
-	if err == nil {
-		t.Error("expected error, but it was nil")
-	}
-
-	err = b.Update(func(tx *blt.Tx) error {
+	err := b.Update(func(tx *blt.Tx) error {
 		bk := tx.Bucket([]byte("streams"))
 		data, err := msgpack.Marshal([]string{"corrupt", "data"})
 		if err != nil {
@@ -196,7 +152,7 @@ func TestListStreamsErrs(t *testing.T) {
 		t.Fatal("unexpected error while creating corrupt data")
 	}
 
-	_, err = b.ListStreams(1, 5)
+	_, _, err = b.ListStreams(&store.ListOptions{})
 	if err == nil {
 		t.Error("expected error, but it was nil")
 	}
@@ -53,12 +53,48 @@ func (err *NoneFoundError) Error() string {
 	return fmt.Sprintf("no entities of kind %v were found", err.Kind)
 }
 
-// CorruptDataError is returned when data at a key has invalid schema.
+// ConflictError is returned when an Entity has been modified since it was read.
+type ConflictError struct {
+	Kind     string
+	Entity   string
+	Revision uint64
+}
+
+// Error message for ConflictError, implements error interface.
+func (err *ConflictError) Error() string {
+	return fmt.Sprintf("%v with name %v was modified since revision %v", err.Kind, err.Entity, err.Revision)
+}
+
+// PeriodError is returned when a stream's snapshot was taken at another
+// period than the stream's current period, so can't be rolled back to.
+type PeriodError struct {
+	Kind     string
+	Entity   string
+	Period   float64
+	Snapshot float64
+}
+
+// Error message for PeriodError, implements error interface.
+func (err *PeriodError) Error() string {
+	return fmt.Sprintf("%v with name %v has period %v, but its snapshot has period %v", err.Kind, err.Entity, err.Period, err.Snapshot)
+}
+
+// CorruptDataError is returned when data at a key has invalid schema. Entity
+// and Err, if set, identify the entity and the underlying decoding error.
 type CorruptDataError struct {
-	Kind string
+	Kind   string
+	Entity string
+	Err    error
 }
 
 // Error message for CorruptDataError, implements error interface.
 func (err *CorruptDataError) Error() string {
-	return fmt.Sprintf("unable to unmarshal entity of kind %v", err.Kind)
+	msg := fmt.Sprintf("unable to unmarshal entity of kind %v", err.Kind)
+	if err.Entity != "" {
+		msg += fmt.Sprintf(" with name %v", err.Entity)
+	}
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
+	}
+	return msg
 }
@@ -18,6 +18,7 @@
 package store_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/cshenton/seer/store"
@@ -58,13 +59,55 @@ func TestNoneFoundError(t *testing.T) {
 	}
 }
 
-func TestCorruptDataError(t *testing.T) {
-	msg := "unable to unmarshal entity of kind stream"
-	err := store.CorruptDataError{
-		Kind: "stream",
+func TestConflictError(t *testing.T) {
+	msg := "stream with name wallace was modified since revision 3"
+	err := store.ConflictError{
+		Kind:     "stream",
+		Entity:   "wallace",
+		Revision: 3,
+	}
+
+	if err.Error() != msg {
+		t.Errorf("expected message `%v`, but got `%v`", msg, err.Error())
+	}
+}
+
+func TestPeriodError(t *testing.T) {
+	msg := "stream with name wallace has period 86400, but its snapshot has period 3600"
+	err := store.PeriodError{
+		Kind:     "stream",
+		Entity:   "wallace",
+		Period:   86400,
+		Snapshot: 3600,
 	}
 
 	if err.Error() != msg {
 		t.Errorf("expected message `%v`, but got `%v`", msg, err.Error())
 	}
 }
+
+func TestCorruptDataError(t *testing.T) {
+	tt := []struct {
+		name string
+		err  store.CorruptDataError
+		msg  string
+	}{
+		{
+			"kind only",
+			store.CorruptDataError{Kind: "stream"},
+			"unable to unmarshal entity of kind stream",
+		},
+		{
+			"with details",
+			store.CorruptDataError{Kind: "stream", Entity: "wallace", Err: errors.New("record is empty")},
+			"unable to unmarshal entity of kind stream with name wallace: record is empty",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err.Error() != tc.msg {
+				t.Errorf("expected message `%v`, but got `%v`", tc.msg, tc.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package store
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/stream"
+)
+
+// Order is the order in which streams are listed.
+type Order int
+
+// The orders streams can be listed in. Streams with the same last event time
+// are listed in name order.
+const (
+	ByName      Order = 0
+	ByLastEvent Order = 1
+)
+
+// ListOptions selects the streams returned by ListStreams.
+type ListOptions struct {
+	// Prefix restricts the list to streams with names that begin with it.
+	Prefix string
+	// Selector restricts the list to streams with labels that match it.
+	Selector label.Selector
+	// Order and Descending set the order of the list.
+	Order      Order
+	Descending bool
+	// After, if set, restricts the list to streams after the cursor.
+	After *Cursor
+	// Limit is the most streams listed, or if not positive, all of them.
+	Limit int
+}
+
+// Cursor is a position in a list of streams, the name and last event time of
+// the stream it follows.
+type Cursor struct {
+	Name string
+	Time time.Time
+}
+
+// CursorOf returns the position of the stream in a list.
+func CursorOf(s *stream.Stream) (c *Cursor) {
+	return &Cursor{Name: s.Config.Name, Time: s.Time}
+}
+
+// Before reports whether the stream at a is listed before the stream at b.
+func (o *ListOptions) Before(a, b *Cursor) bool {
+	c := strings.Compare(a.Name, b.Name)
+	if o.Order == ByLastEvent && !a.Time.Equal(b.Time) {
+		c = 1
+		if a.Time.Before(b.Time) {
+			c = -1
+		}
+	}
+	if o.Descending {
+		return c > 0
+	}
+	return c < 0
+}
+
+// Page returns the page of the streams selected by the options, and the total
+// number of streams with the prefix and matching labels, for stores that list
+// streams by sorting them.
+func (o *ListOptions) Page(all []*stream.Stream) (s []*stream.Stream, total int) {
+	for _, st := range all {
+		if strings.HasPrefix(st.Config.Name, o.Prefix) && o.Selector.Matches(st.Config.Labels) {
+			s = append(s, st)
+		}
+	}
+	total = len(s)
+	sort.Slice(s, func(i, j int) bool {
+		return o.Before(CursorOf(s[i]), CursorOf(s[j]))
+	})
+	if o.After != nil {
+		i := sort.Search(len(s), func(i int) bool {
+			return o.Before(o.After, CursorOf(s[i]))
+		})
+		s = s[i:]
+	}
+	if o.Limit > 0 && len(s) > o.Limit {
+		s = s[:o.Limit]
+	}
+	return s, total
+}
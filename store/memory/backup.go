@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"sort"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// Backup calls fn with the record of every stream, in name order, from a copy
+// of the store taken under a single read lock, so the backup is consistent
+// while the store is in use. It stops at the first error from fn.
+func (m *Store) Backup(fn func(r *store.Record) error) (err error) {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.streams))
+	streams := make(map[string][]byte, len(m.streams))
+	events := make(map[string][]stream.Event, len(m.events))
+	snapshots := make(map[string][]streamSnapshot, len(m.snapshots))
+	for name, val := range m.streams {
+		names = append(names, name)
+		streams[name] = val
+		events[name] = append([]stream.Event{}, m.events[name]...)
+		snapshots[name] = append([]streamSnapshot{}, m.snapshots[name]...)
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		s, err := decodeStream(name, streams[name])
+		if err != nil {
+			return err
+		}
+		r := &store.Record{Stream: s}
+		for i := range events[name] {
+			r.Events = append(r.Events, &events[name][i])
+		}
+		for _, snap := range snapshots[name] {
+			st, err := decodeStream(name, snap.Data)
+			if err != nil {
+				return err
+			}
+			r.Snapshots = append(r.Snapshots, st)
+		}
+
+		err = fn(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreStream saves the record's stream at its name, with its events and
+// snapshots, replacing any stream stored there.
+func (m *Store) RestoreStream(r *store.Record) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.putRecord(r)
+}
+
+// GetRecord returns the record of the stream at name, with its events and
+// snapshots, or an error if no such stream exists.
+func (m *Store) GetRecord(name string) (r *store.Record, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.readRecord(name)
+}
+
+// readRecord reads the stream at name, and its events and snapshots. Callers
+// must hold the lock.
+func (m *Store) readRecord(name string) (r *store.Record, err error) {
+	val, ok := m.streams[name]
+	if !ok {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	s, err := decodeStream(name, val)
+	if err != nil {
+		return nil, err
+	}
+	r = &store.Record{Stream: s}
+	for _, e := range m.events[name] {
+		e := e
+		r.Events = append(r.Events, &e)
+	}
+	for _, snap := range m.snapshots[name] {
+		st, err := decodeStream(name, snap.Data)
+		if err != nil {
+			return nil, err
+		}
+		r.Snapshots = append(r.Snapshots, st)
+	}
+	return r, nil
+}
+
+// putRecord saves the record's stream at its name, with its events and
+// snapshots, replacing any stream stored there. Callers must hold the write
+// lock.
+func (m *Store) putRecord(r *store.Record) (err error) {
+	name := r.Stream.Config.Name
+	val, err := schema.Marshal(r.Stream)
+	if err != nil {
+		return err
+	}
+	var snaps []streamSnapshot
+	for _, s := range r.Snapshots {
+		data, err := schema.Marshal(s)
+		if err != nil {
+			return err
+		}
+		snaps = append(snaps, streamSnapshot{Time: s.Time, Data: data})
+	}
+	var events []stream.Event
+	for _, e := range r.Events {
+		events = append(events, *e)
+	}
+
+	m.deleteStream(name)
+	m.streams[name] = val
+	m.indexLabels(name, nil, r.Stream.Config.Labels)
+	if len(events) > 0 {
+		m.events[name] = events
+	}
+	if len(snaps) > 0 {
+		m.snapshots[name] = snaps
+	}
+	return nil
+}
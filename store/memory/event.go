@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+// putEvents adds the events to the stream's history, then discards events
+// outside its retention policy. If the stream has no policy, its history is
+// removed. Callers must hold the write lock.
+func (m *Store) putEvents(name string, s *stream.Stream, events []*stream.Event) {
+	r := s.Config.Retention
+	if r == nil {
+		delete(m.events, name)
+		return
+	}
+
+	hist := m.events[name]
+	for _, e := range events {
+		i := sort.Search(len(hist), func(i int) bool { return !hist[i].Time.Before(e.Time) })
+		if i < len(hist) && hist[i].Time.Equal(e.Time) {
+			hist[i].Value = e.Value
+			continue
+		}
+		hist = append(hist, stream.Event{})
+		copy(hist[i+1:], hist[i:])
+		hist[i] = *e
+	}
+
+	start := 0
+	if cutoff := r.Cutoff(s.Time); !cutoff.IsZero() {
+		start = sort.Search(len(hist), func(i int) bool { return !hist[i].Time.Before(cutoff) })
+	}
+	if r.Count > 0 && len(hist)-start > r.Count {
+		start = len(hist) - r.Count
+	}
+	m.events[name] = append([]stream.Event{}, hist[start:]...)
+}
+
+// GetEvents returns up to limit of the stream's retained events in [from, to),
+// in time order, where a zero from or to is unbounded, and a non-positive
+// limit returns all of them. It returns an error if no stream exists at name.
+func (m *Store) GetEvents(name string, from, to time.Time, limit int) (e []*stream.Event, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.streams[name]; !ok {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	hist := m.events[name]
+	i := sort.Search(len(hist), func(i int) bool { return !hist[i].Time.Before(from) })
+
+	for ; i < len(hist); i++ {
+		if !to.IsZero() && !hist[i].Time.Before(to) {
+			break
+		}
+		if limit > 0 && len(e) >= limit {
+			break
+		}
+		ev := hist[i]
+		e = append(e, &ev)
+	}
+	return e, nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"github.com/cshenton/seer/label"
+)
+
+// labelIndex holds the names of the streams with each label value, by label
+// key then value.
+type labelIndex map[string]map[string]map[string]bool
+
+// indexLabels updates the label index of the stream at name from its old to
+// its new labels.
+func (m *Store) indexLabels(name string, old, new map[string]string) {
+	for k, v := range old {
+		if nv, ok := new[k]; ok && nv == v {
+			continue
+		}
+		delete(m.labels[k][v], name)
+		if len(m.labels[k][v]) == 0 {
+			delete(m.labels[k], v)
+		}
+		if len(m.labels[k]) == 0 {
+			delete(m.labels, k)
+		}
+	}
+	for k, v := range new {
+		if m.labels[k] == nil {
+			m.labels[k] = make(map[string]map[string]bool)
+		}
+		if m.labels[k][v] == nil {
+			m.labels[k][v] = make(map[string]bool)
+		}
+		m.labels[k][v][name] = true
+	}
+}
+
+// storedLabels returns the labels of the stream stored at name, or nil if
+// there is no stream, or it cannot be decoded.
+func (m *Store) storedLabels(name string) (labels map[string]string) {
+	val, ok := m.streams[name]
+	if !ok {
+		return nil
+	}
+	s, err := decodeMeta(name, val)
+	if err != nil {
+		return nil
+	}
+	return s.Config.Labels
+}
+
+// selectNames returns the names of the streams indexed with labels that meet
+// the selector's =, in and exists requirements, and false if it has none. The
+// names may include streams that fail its other requirements.
+func (m *Store) selectNames(sel label.Selector) (names map[string]bool, ok bool) {
+	for _, r := range sel {
+		var values []string
+		switch r.Operator {
+		case label.Equals, label.In:
+			values = r.Values
+		case label.Exists:
+			for v := range m.labels[r.Key] {
+				values = append(values, v)
+			}
+		default:
+			continue
+		}
+
+		found := make(map[string]bool)
+		for _, v := range values {
+			for name := range m.labels[r.Key][v] {
+				if !ok || names[name] {
+					found[name] = true
+				}
+			}
+		}
+		names, ok = found, true
+	}
+	return names, ok
+}
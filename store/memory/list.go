@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"strings"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+// ListStreams returns the streams selected by the options, without their
+// models, and the total number of streams with the prefix and matching labels.
+// Streams listed in name order without a selector are paged by name, so only
+// the listed streams are decoded, and those listed by label are only decoded
+// if found in the label index.
+func (m *Store) ListStreams(opts *store.ListOptions) (s []*stream.Stream, total int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names, indexed := m.selectNames(opts.Selector)
+	decode := opts.Order == store.ByLastEvent || len(opts.Selector) > 0
+	var all []*stream.Stream
+	for name, val := range m.streams {
+		if !strings.HasPrefix(name, opts.Prefix) || (indexed && !names[name]) {
+			continue
+		}
+		st := &stream.Stream{Config: &stream.Config{Name: name}}
+		if decode {
+			st, err = decodeMeta(name, val)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		all = append(all, st)
+	}
+	s, total = opts.Page(all)
+
+	if !decode {
+		for i := range s {
+			s[i], err = decodeMeta(s[i].Config.Name, m.streams[s[i].Config.Name])
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return s, total, nil
+}
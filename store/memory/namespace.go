@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"sort"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+)
+
+// decodeNamespace decodes the namespace stored at name.
+func decodeNamespace(name string, val []byte) (ns *store.Namespace, err error) {
+	ns, err = schema.UnmarshalNamespace(val)
+	if err != nil {
+		return nil, &store.CorruptDataError{Kind: "namespace", Entity: name, Err: err}
+	}
+	return ns, nil
+}
+
+// CreateNamespace saves the provided namespace, or returns an error if a
+// namespace with its name already exists.
+func (m *Store) CreateNamespace(ns *store.Namespace) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.namespaces[ns.Name]; ok {
+		return &store.AlreadyExistsError{Kind: "namespace", Entity: ns.Name}
+	}
+	val, err := schema.MarshalNamespace(ns)
+	if err != nil {
+		return err
+	}
+	m.namespaces[ns.Name] = val
+	return nil
+}
+
+// GetNamespace returns the namespace stored at name, or an error if it does
+// not exist.
+func (m *Store) GetNamespace(name string) (ns *store.Namespace, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	val, ok := m.namespaces[name]
+	if !ok {
+		return nil, &store.NotFoundError{Kind: "namespace", Entity: name}
+	}
+	return decodeNamespace(name, val)
+}
+
+// UpdateNamespace overwrites the stored namespace with the provided one, or
+// returns an error if it does not exist.
+func (m *Store) UpdateNamespace(ns *store.Namespace) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.namespaces[ns.Name]; !ok {
+		return &store.NotFoundError{Kind: "namespace", Entity: ns.Name}
+	}
+	val, err := schema.MarshalNamespace(ns)
+	if err != nil {
+		return err
+	}
+	m.namespaces[ns.Name] = val
+	return nil
+}
+
+// DeleteNamespace deletes the namespace stored at name, or returns an error if
+// it does not exist.
+func (m *Store) DeleteNamespace(name string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.namespaces[name]; !ok {
+		return &store.NotFoundError{Kind: "namespace", Entity: name}
+	}
+	delete(m.namespaces, name)
+	return nil
+}
+
+// ListNamespaces returns every namespace, in name order.
+func (m *Store) ListNamespaces() (ns []*store.Namespace, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for name, val := range m.namespaces {
+		n, err := decodeNamespace(name, val)
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	sort.Slice(ns, func(i, j int) bool { return ns[i].Name < ns[j].Name })
+	return ns, nil
+}
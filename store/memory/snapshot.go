@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// streamSnapshot is an encoded prior state of a stream.
+type streamSnapshot struct {
+	Time time.Time
+	Data []byte
+}
+
+// putSnapshot keeps the stored stream old, encoded as val, as a snapshot if
+// one is due under the policy, then discards the oldest snapshots beyond the
+// policy's count. If there is no policy, the stream's snapshots are removed.
+// Callers must hold the write lock.
+func (m *Store) putSnapshot(name string, p *stream.SnapshotPolicy, old *stream.Stream, val []byte) {
+	if p == nil {
+		delete(m.snapshots, name)
+		return
+	}
+
+	snaps := m.snapshots[name]
+	var latest time.Time
+	if len(snaps) > 0 {
+		latest = snaps[len(snaps)-1].Time
+	}
+	if !p.Due(old.Time, latest) {
+		return
+	}
+	if len(snaps) > 0 && snaps[len(snaps)-1].Time.Equal(old.Time) {
+		snaps = snaps[:len(snaps)-1]
+	}
+	snaps = append(snaps, streamSnapshot{Time: old.Time, Data: val})
+	if len(snaps) > p.Count {
+		snaps = snaps[len(snaps)-p.Count:]
+	}
+	m.snapshots[name] = append([]streamSnapshot{}, snaps...)
+}
+
+// RollbackStream restores the model and time of the stream at name from its
+// latest snapshot at or before to, keeping its current config, and discards
+// its later snapshots and events. It returns an error if no stream exists at
+// name, it has no such snapshot, or the snapshot is at another period. The
+// restored stream takes the next revision. If the stream is not after to, it
+// is unchanged.
+func (m *Store) RollbackStream(name string, to time.Time) (s *stream.Stream, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	val, ok := m.streams[name]
+	if !ok {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	cur, err := decodeStream(name, val)
+	if err != nil {
+		return nil, err
+	}
+	if !cur.Time.After(to) {
+		return cur, nil
+	}
+
+	snaps := m.snapshots[name]
+	i := sort.Search(len(snaps), func(i int) bool { return snaps[i].Time.After(to) })
+	if i == 0 {
+		return nil, &store.NotFoundError{Kind: "snapshot", Entity: name}
+	}
+	snap, err := decodeStream(name, snaps[i-1].Data)
+	if err != nil {
+		return nil, err
+	}
+	s, err = store.Rollback(cur, snap)
+	if err != nil {
+		return nil, err
+	}
+	val, err = schema.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	m.streams[name] = val
+	m.snapshots[name] = snaps[:i]
+	hist := m.events[name]
+	j := sort.Search(len(hist), func(j int) bool { return hist[j].Time.After(s.Time) })
+	if hist != nil {
+		m.events[name] = hist[:j]
+	}
+	return s, nil
+}
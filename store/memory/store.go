@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+	"github.com/vmihailenco/msgpack"
+)
+
+// snapshotVersion is the current snapshot format. Snapshots written before
+// namespaces have none, which decode as empty.
+const snapshotVersion = 1
+
+// snapshot is the on disk format of the store.
+type snapshot struct {
+	Version    int
+	Streams    map[string][]byte
+	Events     map[string][]stream.Event
+	Snapshots  map[string][]streamSnapshot
+	Namespaces map[string][]byte
+}
+
+// Store is a concurrency safe, in memory store.StreamStore. Streams are held
+// encoded, so callers never share state with the store. If it has a path, the
+// store is restored from it on creation, and snapshotted to it periodically
+// and on Close.
+type Store struct {
+	mu         sync.RWMutex
+	streams    map[string][]byte
+	events     map[string][]stream.Event
+	snapshots  map[string][]streamSnapshot
+	labels     labelIndex
+	namespaces map[string][]byte
+
+	path string
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a memory Store. If path is not empty, the store is restored
+// from any snapshot at path, and if interval is positive, snapshotted to path
+// at that interval.
+func New(path string, interval time.Duration) (m *Store, err error) {
+	m = &Store{
+		streams:    make(map[string][]byte),
+		events:     make(map[string][]stream.Event),
+		snapshots:  make(map[string][]streamSnapshot),
+		labels:     make(labelIndex),
+		namespaces: make(map[string][]byte),
+		path:       path,
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	err = m.restore()
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 {
+		m.stop = make(chan struct{})
+		m.done = make(chan struct{})
+		go m.loop(interval)
+	}
+	return m, nil
+}
+
+// Snapshot atomically writes the contents of the store to its path.
+func (m *Store) Snapshot() (err error) {
+	m.mu.RLock()
+	val, err := msgpack.Marshal(&snapshot{
+		Version:    snapshotVersion,
+		Streams:    m.streams,
+		Events:     m.events,
+		Snapshots:  m.snapshots,
+		Namespaces: m.namespaces,
+	})
+	m.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(m.path), filepath.Base(m.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(val)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// Close stops periodic snapshots, and takes a final snapshot if the store has
+// a path.
+func (m *Store) Close() (err error) {
+	if m.stop != nil {
+		close(m.stop)
+		<-m.done
+		m.stop = nil
+	}
+	if m.path == "" {
+		return nil
+	}
+	return m.Snapshot()
+}
+
+// restore loads the snapshot at the store's path, if there is one, upgrading
+// any streams stored at old schema versions, and indexing their labels.
+func (m *Store) restore() (err error) {
+	info, err := os.Stat(m.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("snapshot path %v is a directory, but should be a file", m.path)
+	}
+	val, err := ioutil.ReadFile(m.path)
+	if err != nil {
+		return err
+	}
+	if len(val) == 0 {
+		return nil
+	}
+	snap := &snapshot{}
+	err = msgpack.Unmarshal(val, snap)
+	if err != nil {
+		return err
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %v", snap.Version)
+	}
+	if snap.Streams != nil {
+		m.streams = snap.Streams
+	}
+	if snap.Events != nil {
+		m.events = snap.Events
+	}
+	if snap.Snapshots != nil {
+		m.snapshots = snap.Snapshots
+	}
+	if snap.Namespaces != nil {
+		m.namespaces = snap.Namespaces
+	}
+
+	// Upgrade streams stored at old schema versions.
+	for name, val := range m.streams {
+		s, v, err := schema.Unmarshal(val)
+		if err != nil {
+			return &store.CorruptDataError{Kind: "stream", Entity: name, Err: err}
+		}
+		m.indexLabels(name, nil, s.Config.Labels)
+		if v < schema.Version {
+			m.streams[name], err = schema.Marshal(s)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loop snapshots the store at the provided interval until stopped.
+func (m *Store) loop(interval time.Duration) {
+	defer close(m.done)
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			m.Snapshot()
+		case <-m.stop:
+			return
+		}
+	}
+}
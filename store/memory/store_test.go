@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/memory"
+	"github.com/cshenton/seer/store/storetest"
+	"github.com/cshenton/seer/stream"
+	"github.com/vmihailenco/msgpack"
+)
+
+func testPath(t *testing.T) string {
+	f, err := ioutil.TempFile(os.TempDir(), "memory_test")
+	if err != nil {
+		t.Fatal("failed to create test snapshot file")
+	}
+	f.Close()
+	return f.Name()
+}
+
+func TestNew(t *testing.T) {
+	tt := []struct {
+		name string
+		path string
+	}{
+		{"ephemeral", ""},
+		{"empty snapshot", testPath(t)},
+		{"missing snapshot", testPath(t) + "_missing"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := memory.New(tc.path, time.Minute)
+			if err != nil {
+				t.Fatal("unexpected error in memory.New:", err)
+			}
+			err = m.Close()
+			if err != nil {
+				t.Error("unexpected error in Close:", err)
+			}
+		})
+	}
+}
+
+func TestNewErrs(t *testing.T) {
+	path := testPath(t)
+	err := ioutil.WriteFile(path, []byte("corrupt"), 0600)
+	if err != nil {
+		t.Fatal("unexpected error while creating corrupt snapshot")
+	}
+
+	m, err := memory.New(path, 0)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+	if m != nil {
+		t.Error("expected nil store, but it was", m)
+	}
+}
+
+func TestNewDirectory(t *testing.T) {
+	m, err := memory.New(os.TempDir(), 0)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+	if m != nil {
+		t.Error("expected nil store, but it was", m)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	path := testPath(t)
+	m, _ := memory.New(path, 0)
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	m.CreateStream("sales", s)
+
+	err := m.Close()
+	if err != nil {
+		t.Fatal("unexpected error in Close:", err)
+	}
+
+	r, err := memory.New(path, 0)
+	if err != nil {
+		t.Fatal("unexpected error in memory.New:", err)
+	}
+	s, err = r.GetStream("sales")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if s.Config.Name != "sales" {
+		t.Errorf("expected stream name %v, but got %v", "sales", s.Config.Name)
+	}
+}
+
+func TestSnapshotEvents(t *testing.T) {
+	path := testPath(t)
+	m, _ := memory.New(path, 0)
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	s.Config.Retention = &stream.Retention{}
+	m.CreateStream("sales", s)
+	vals, times := storetest.Events(5)
+	s.Update(vals, times)
+	events, _ := stream.Events(vals, times)
+	m.UpdateStream("sales", s, events...)
+	m.Close()
+
+	r, err := memory.New(path, 0)
+	if err != nil {
+		t.Fatal("unexpected error in memory.New:", err)
+	}
+	e, err := r.GetEvents("sales", time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatal("unexpected error in GetEvents:", err)
+	}
+	if len(e) != len(events) {
+		t.Errorf("expected %v events, but there were %v", len(events), len(e))
+	}
+}
+
+func TestSnapshotLabels(t *testing.T) {
+	path := testPath(t)
+	m, _ := memory.New(path, 0)
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	s.Config.Labels = map[string]string{"team": "growth"}
+	m.CreateStream("sales", s)
+	m.Close()
+
+	r, err := memory.New(path, 0)
+	if err != nil {
+		t.Fatal("unexpected error in memory.New:", err)
+	}
+	sel, _ := label.Parse("team=growth")
+	l, total, err := r.ListStreams(&store.ListOptions{Selector: sel})
+	if err != nil {
+		t.Fatal("unexpected error in ListStreams:", err)
+	}
+	if total != 1 || len(l) != 1 {
+		t.Errorf("expected labelled stream to be indexed on restore, but got %v", l)
+	}
+}
+
+func TestSnapshotNamespaces(t *testing.T) {
+	path := testPath(t)
+	m, _ := memory.New(path, 0)
+	m.CreateNamespace(&store.Namespace{Name: "growth", MaxStreams: 3})
+	m.Close()
+
+	r, err := memory.New(path, 0)
+	if err != nil {
+		t.Fatal("unexpected error in memory.New:", err)
+	}
+	ns, err := r.GetNamespace("growth")
+	if err != nil {
+		t.Fatal("unexpected error in GetNamespace:", err)
+	}
+	if ns.MaxStreams != 3 {
+		t.Errorf("expected max streams %v, but got %v", 3, ns.MaxStreams)
+	}
+}
+
+func TestRestoreUnversioned(t *testing.T) {
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	val, _ := msgpack.Marshal(s)
+	snap, _ := msgpack.Marshal(map[string][]byte{"sales": val})
+	path := testPath(t)
+	ioutil.WriteFile(path, snap, 0600)
+
+	_, err := memory.New(path, 0)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func TestPeriodicSnapshot(t *testing.T) {
+	path := testPath(t)
+	m, _ := memory.New(path, 10*time.Millisecond)
+	defer m.Close()
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	m.CreateStream("sales", s)
+
+	time.Sleep(100 * time.Millisecond)
+
+	r, err := memory.New(path, 0)
+	if err != nil {
+		t.Fatal("unexpected error in memory.New:", err)
+	}
+	_, err = r.GetStream("sales")
+	if err != nil {
+		t.Error("expected stream to be snapshotted, but got", err)
+	}
+}
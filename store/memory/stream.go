@@ -0,0 +1,233 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// decodeStream decodes the stream stored at name.
+func decodeStream(name string, val []byte) (s *stream.Stream, err error) {
+	s, _, err = schema.Unmarshal(val)
+	if err != nil {
+		return nil, &store.CorruptDataError{Kind: "stream", Entity: name, Err: err}
+	}
+	return s, nil
+}
+
+// decodeMeta decodes the stream stored at name, without its model.
+func decodeMeta(name string, val []byte) (s *stream.Stream, err error) {
+	s, err = schema.UnmarshalMeta(val)
+	if err != nil {
+		return nil, &store.CorruptDataError{Kind: "stream", Entity: name, Err: err}
+	}
+	return s, nil
+}
+
+// CreateStream saves the provided stream at name, returns an error if a
+// stream already exists at that address.
+func (m *Store) CreateStream(name string, s *stream.Stream) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.streams[name]; ok {
+		return &store.AlreadyExistsError{Kind: "stream", Entity: name}
+	}
+	val, err := schema.Marshal(s)
+	if err != nil {
+		return err
+	}
+	m.streams[name] = val
+	m.indexLabels(name, nil, s.Config.Labels)
+	return nil
+}
+
+// GetStream returns the stream stored at name, or an error if the stream does
+// not exist, or has corrupted data.
+func (m *Store) GetStream(name string) (s *stream.Stream, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	val, ok := m.streams[name]
+	if !ok {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	return decodeStream(name, val)
+}
+
+// GetStreams returns the streams stored at names, in order, under a single
+// read lock, with nil where no stream is stored.
+func (m *Store) GetStreams(names []string) (s []*stream.Stream, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s = make([]*stream.Stream, len(names))
+	for i, name := range names {
+		val, ok := m.streams[name]
+		if !ok {
+			continue
+		}
+		s[i], err = decodeStream(name, val)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// DeleteStream deletes the stream stored at name, and its events and
+// snapshots, or returns an error if no such stream exists.
+func (m *Store) DeleteStream(name string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.streams[name]; !ok {
+		return &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	m.deleteStream(name)
+	return nil
+}
+
+// ExpireStream deletes the stream stored at name, and its events and
+// snapshots, if it is at the provided revision. It returns an error if no
+// such stream exists, or if it has been updated since that revision.
+func (m *Store) ExpireStream(name string, revision uint64) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	val, ok := m.streams[name]
+	if !ok {
+		return &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	s, err := decodeStream(name, val)
+	if err != nil {
+		return err
+	}
+	if s.Revision != revision {
+		return &store.ConflictError{Kind: "stream", Entity: name, Revision: revision}
+	}
+	m.deleteStream(name)
+	return nil
+}
+
+// deleteStream deletes any stream stored at name, with its events, snapshots
+// and labels. Callers must hold the write lock.
+func (m *Store) deleteStream(name string) {
+	m.indexLabels(name, m.storedLabels(name), nil)
+	delete(m.streams, name)
+	delete(m.events, name)
+	delete(m.snapshots, name)
+}
+
+// RenameStream moves the stream stored at from, with its events and
+// snapshots, to to. It returns an error if no stream exists at from, or one
+// already exists at to.
+func (m *Store) RenameStream(from, to string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.streams[to]; ok {
+		return &store.AlreadyExistsError{Kind: "stream", Entity: to}
+	}
+	r, err := m.readRecord(from)
+	if err != nil {
+		return err
+	}
+	r.Rename(to)
+	err = m.putRecord(r)
+	if err != nil {
+		return err
+	}
+	m.deleteStream(from)
+	return nil
+}
+
+// CloneStream copies the stream stored at src, with its events and snapshots,
+// to dst, at revision 0. It returns an error if no stream exists at src, or
+// one already exists at dst.
+func (m *Store) CloneStream(src, dst string) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.streams[dst]; ok {
+		return &store.AlreadyExistsError{Kind: "stream", Entity: dst}
+	}
+	r, err := m.readRecord(src)
+	if err != nil {
+		return err
+	}
+	r.Rename(dst)
+	r.Stream.Revision = 0
+	return m.putRecord(r)
+}
+
+// UpdateStream overwrites the stream at name with the provided stream, adds
+// the events to its history, and keeps the stored stream as a snapshot if one
+// is due. It returns an error if no stream exists at name, or if the stored
+// stream is not at the provided stream's revision. On success the revision is
+// incremented.
+func (m *Store) UpdateStream(name string, s *stream.Stream, events ...*stream.Event) (err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.updateStream(name, s, events)
+}
+
+// UpdateStreams applies each update as UpdateStream does, under a single write
+// lock. Updates are applied independently, so every update that fails has its
+// error at its position in errs.
+func (m *Store) UpdateStreams(updates []*store.Update) (errs []error, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	errs = make([]error, len(updates))
+	for i, u := range updates {
+		errs[i] = m.updateStream(u.Name, u.Stream, u.Events)
+	}
+	return errs, nil
+}
+
+// updateStream overwrites the stream at name, as UpdateStream. Callers must
+// hold the write lock.
+func (m *Store) updateStream(name string, s *stream.Stream, events []*stream.Event) (err error) {
+	val, ok := m.streams[name]
+	if !ok {
+		return &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	old, err := decodeStream(name, val)
+	if err != nil {
+		return err
+	}
+	if old.Revision != s.Revision {
+		return &store.ConflictError{Kind: "stream", Entity: name, Revision: s.Revision}
+	}
+
+	s.Revision++
+	data, err := schema.Marshal(s)
+	if err != nil {
+		s.Revision--
+		return err
+	}
+	m.putSnapshot(name, s.Config.Snapshots, old, val)
+	m.streams[name] = data
+	m.indexLabels(name, old.Config.Labels, s.Config.Labels)
+	m.putEvents(name, s, events)
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/memory"
+	"github.com/cshenton/seer/store/storetest"
+)
+
+func newStore(t *testing.T) (b *memory.Store) {
+	b, err := memory.New("", 0)
+	if err != nil {
+		t.Fatal("unexpected error in memory.New:", err)
+	}
+	return b
+}
+
+func setUp(t *testing.T) (b *memory.Store) {
+	b = newStore(t)
+	storetest.Fixtures(t, b)
+	return b
+}
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.StreamStore { return newStore(t) })
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	b := setUp(t)
+	defer b.Close()
+	n := 20
+
+	done := make(chan struct{})
+	for i := 0; i < n; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for {
+				s, _ := b.GetStream("sales")
+				err := b.UpdateStream("sales", s)
+				if _, ok := err.(*store.ConflictError); !ok {
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		<-done
+	}
+
+	s, _ := b.GetStream("sales")
+	if s.Revision != uint64(n) {
+		t.Errorf("expected revision %v, but it was %v", n, s.Revision)
+	}
+}
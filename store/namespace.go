@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package store
+
+import (
+	"context"
+)
+
+// Namespace is an isolated set of streams, stored under names prefixed with
+// the namespace name and a slash. MaxStreams limits the number of streams in
+// the namespace, and MaxPointsPerSecond the rate events are sent to them,
+// where zero is unlimited.
+type Namespace struct {
+	Name               string
+	MaxStreams         int
+	MaxPointsPerSecond float64
+}
+
+// NamespaceStore defines the methods required to store and retrieve
+// namespaces. Namespaces only hold their quotas, their streams are stored as
+// any other. ListNamespaces returns every namespace, in name order.
+type NamespaceStore interface {
+	CreateNamespace(ns *Namespace) (err error)
+	GetNamespace(name string) (ns *Namespace, err error)
+	UpdateNamespace(ns *Namespace) (err error)
+	DeleteNamespace(name string) (err error)
+	ListNamespaces() (ns []*Namespace, err error)
+}
+
+// CreateNamespace creates a namespace using the current context store, it
+// returns an error if the namespace already exists.
+func CreateNamespace(c context.Context, ns *Namespace) (err error) {
+	return streamFromContext(c).CreateNamespace(ns)
+}
+
+// GetNamespace returns the namespace with the specific name using the current
+// context store.
+func GetNamespace(c context.Context, name string) (ns *Namespace, err error) {
+	return streamFromContext(c).GetNamespace(name)
+}
+
+// UpdateNamespace replaces the quotas of a namespace using the current context
+// store.
+func UpdateNamespace(c context.Context, ns *Namespace) (err error) {
+	return streamFromContext(c).UpdateNamespace(ns)
+}
+
+// DeleteNamespace deletes the namespace with the specific name using the
+// current context store.
+func DeleteNamespace(c context.Context, name string) (err error) {
+	return streamFromContext(c).DeleteNamespace(name)
+}
+
+// ListNamespaces lists every namespace in the current context store.
+func ListNamespaces(c context.Context) (ns []*Namespace, err error) {
+	return streamFromContext(c).ListNamespaces()
+}
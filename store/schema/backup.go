@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// backupHeader begins every backup, followed by length prefixed records.
+var backupHeader = []byte("seer-backup\x01")
+
+// maxRecordSize bounds the size of a single backup record.
+const maxRecordSize = 1 << 30
+
+// Writer writes store records as a backup, which any store can restore.
+type Writer struct {
+	w      io.Writer
+	header bool
+}
+
+// NewWriter returns a Writer that writes a backup to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write encodes a record, and the backup header if it is the first. Records
+// with a namespace are written as just the namespace.
+func (w *Writer) Write(r *store.Record) (err error) {
+	if !w.header {
+		_, err = w.w.Write(backupHeader)
+		if err != nil {
+			return err
+		}
+		w.header = true
+	}
+
+	pb := &Record{}
+	if r.Namespace != nil {
+		pb.Namespace = namespaceProto(r.Namespace)
+	} else {
+		pb.Stream, err = Marshal(r.Stream)
+		if err != nil {
+			return err
+		}
+	}
+	for _, e := range r.Events {
+		ts, err := ptypes.TimestampProto(e.Time)
+		if err != nil {
+			return err
+		}
+		pb.Events = append(pb.Events, &Event{Time: ts, Value: e.Value})
+	}
+	for _, s := range r.Snapshots {
+		data, err := Marshal(s)
+		if err != nil {
+			return err
+		}
+		pb.Snapshots = append(pb.Snapshots, data)
+	}
+	body, err := proto.Marshal(pb)
+	if err != nil {
+		return err
+	}
+
+	n := make([]byte, binary.MaxVarintLen64)
+	_, err = w.w.Write(n[:binary.PutUvarint(n, uint64(len(body)))])
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(body)
+	return err
+}
+
+// Close writes the backup header if no records were written, so that empty
+// backups can be restored.
+func (w *Writer) Close() (err error) {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	_, err = w.w.Write(backupHeader)
+	return err
+}
+
+// Reader reads store records from a backup.
+type Reader struct {
+	r      *bufio.Reader
+	header bool
+}
+
+// NewReader returns a Reader that reads a backup from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read decodes the next record, or returns io.EOF at the end of the backup.
+func (r *Reader) Read() (rec *store.Record, err error) {
+	if !r.header {
+		h := make([]byte, len(backupHeader))
+		_, err = io.ReadFull(r.r, h)
+		if err != nil || !bytes.Equal(h, backupHeader) {
+			err = fmt.Errorf("not a seer backup")
+			return nil, err
+		}
+		r.header = true
+	}
+
+	n, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n > maxRecordSize {
+		err = fmt.Errorf("record of %v bytes exceeds the maximum of %v", n, maxRecordSize)
+		return nil, err
+	}
+	body := make([]byte, n)
+	_, err = io.ReadFull(r.r, body)
+	if err != nil {
+		err = fmt.Errorf("backup is truncated: %v", err)
+		return nil, err
+	}
+
+	pb := &Record{}
+	err = proto.Unmarshal(body, pb)
+	if err != nil {
+		return nil, err
+	}
+	rec = &store.Record{}
+	if pb.Namespace != nil {
+		rec.Namespace = namespaceFromProto(pb.Namespace)
+		return rec, nil
+	}
+	rec.Stream, _, err = Unmarshal(pb.Stream)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range pb.Events {
+		t, err := ptypes.Timestamp(e.Time)
+		if err != nil {
+			return nil, err
+		}
+		rec.Events = append(rec.Events, &stream.Event{Time: t, Value: e.Value})
+	}
+	for _, data := range pb.Snapshots {
+		s, _, err := Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		rec.Snapshots = append(rec.Snapshots, s)
+	}
+	return rec, nil
+}
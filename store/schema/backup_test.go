@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+func testRecord(t *testing.T) (r *store.Record) {
+	s := testStream(t)
+	snap := testStream(t)
+	snap.Revision = 3
+	events, err := stream.Events([]float64{1, 2}, []time.Time{
+		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal("unexpected error in stream.Events:", err)
+	}
+	return &store.Record{Stream: s, Events: events, Snapshots: []*stream.Stream{snap}}
+}
+
+func TestBackup(t *testing.T) {
+	tt := []struct {
+		name string
+		num  int
+	}{
+		{"empty", 0},
+		{"single", 1},
+		{"multiple", 3},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := schema.NewWriter(buf)
+			for i := 0; i < tc.num; i++ {
+				err := w.Write(testRecord(t))
+				if err != nil {
+					t.Fatal("unexpected error in Write:", err)
+				}
+			}
+			err := w.Close()
+			if err != nil {
+				t.Fatal("unexpected error in Close:", err)
+			}
+
+			r := schema.NewReader(buf)
+			n := 0
+			for {
+				rec, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal("unexpected error in Read:", err)
+				}
+				n++
+				if rec.Stream.Config.Name != "sales" || rec.Stream.Revision != 7 {
+					t.Errorf("expected stream sales at revision %v, but got %v at %v", 7, rec.Stream.Config.Name, rec.Stream.Revision)
+				}
+				if len(rec.Events) != 2 || !rec.Events[1].Time.Equal(time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)) || rec.Events[1].Value != 2 {
+					t.Errorf("expected events to round trip, but got %v", rec.Events)
+				}
+				if len(rec.Snapshots) != 1 || rec.Snapshots[0].Revision != 3 {
+					t.Errorf("expected snapshot at revision %v, but got %v", 3, rec.Snapshots)
+				}
+			}
+			if n != tc.num {
+				t.Errorf("expected %v records, but got %v", tc.num, n)
+			}
+		})
+	}
+}
+
+func TestBackupNamespace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := schema.NewWriter(buf)
+	w.Write(&store.Record{Namespace: &store.Namespace{Name: "growth", MaxStreams: 10, MaxPointsPerSecond: 2.5}})
+	w.Write(testRecord(t))
+	w.Close()
+
+	r := schema.NewReader(buf)
+	rec, err := r.Read()
+	if err != nil {
+		t.Fatal("unexpected error in Read:", err)
+	}
+	ns := rec.Namespace
+	if rec.Stream != nil || ns == nil || ns.Name != "growth" || ns.MaxStreams != 10 || ns.MaxPointsPerSecond != 2.5 {
+		t.Errorf("expected namespace growth, but got %v", rec)
+	}
+	rec, err = r.Read()
+	if err != nil {
+		t.Fatal("unexpected error in Read:", err)
+	}
+	if rec.Namespace != nil || rec.Stream.Config.Name != "sales" {
+		t.Errorf("expected stream sales, but got %v", rec)
+	}
+}
+
+func TestBackupErrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := schema.NewWriter(buf)
+	w.Write(testRecord(t))
+	w.Close()
+	valid := buf.Bytes()
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"not a backup", []byte("definitely not a backup")},
+		{"truncated", valid[:len(valid)-5]},
+		// The header is the first 12 bytes.
+		{"corrupt record", append(append([]byte{}, valid[:12]...), 2, 0xff, 0xff)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := schema.NewReader(bytes.NewReader(tc.data)).Read()
+			if err == nil || err == io.EOF {
+				t.Errorf("expected error, but got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema
+
+import (
+	"github.com/cshenton/seer/store"
+	"github.com/golang/protobuf/proto"
+)
+
+// MarshalNamespace encodes the namespace.
+func MarshalNamespace(ns *store.Namespace) (data []byte, err error) {
+	return proto.Marshal(namespaceProto(ns))
+}
+
+// UnmarshalNamespace decodes a namespace encoded by MarshalNamespace.
+func UnmarshalNamespace(data []byte) (ns *store.Namespace, err error) {
+	pb := &Namespace{}
+	err = proto.Unmarshal(data, pb)
+	if err != nil {
+		return nil, err
+	}
+	return namespaceFromProto(pb), nil
+}
+
+func namespaceProto(ns *store.Namespace) (pb *Namespace) {
+	return &Namespace{
+		Name:               ns.Name,
+		MaxStreams:         int64(ns.MaxStreams),
+		MaxPointsPerSecond: ns.MaxPointsPerSecond,
+	}
+}
+
+func namespaceFromProto(pb *Namespace) (ns *store.Namespace) {
+	return &store.Namespace{
+		Name:               pb.Name,
+		MaxStreams:         int(pb.MaxStreams),
+		MaxPointsPerSecond: pb.MaxPointsPerSecond,
+	}
+}
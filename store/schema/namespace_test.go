@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+)
+
+func TestMarshalNamespace(t *testing.T) {
+	tt := []struct {
+		name string
+		ns   *store.Namespace
+	}{
+		{"unlimited", &store.Namespace{Name: "growth"}},
+		{"quotas", &store.Namespace{Name: "growth", MaxStreams: 100, MaxPointsPerSecond: 12.5}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := schema.MarshalNamespace(tc.ns)
+			if err != nil {
+				t.Fatal("unexpected error in MarshalNamespace:", err)
+			}
+			ns, err := schema.UnmarshalNamespace(data)
+			if err != nil {
+				t.Fatal("unexpected error in UnmarshalNamespace:", err)
+			}
+			if *ns != *tc.ns {
+				t.Errorf("expected namespace %v, but got %v", tc.ns, ns)
+			}
+		})
+	}
+}
+
+func TestUnmarshalNamespaceErrs(t *testing.T) {
+	_, err := schema.UnmarshalNamespace([]byte{0xff, 0xff})
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
@@ -0,0 +1,463 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package schema defines the versioned on disk encoding of streams.
+//
+// Records are prefixed with a single version byte, followed by a protocol
+// buffer message of that version. Records written before versioning are
+// msgpack encoded stream structs, and are read as version 0. Each version has
+// a registered decoder, which upgrades records of that version to the current
+// stream, so stores can upgrade old records as they read them.
+//
+// Backups are a header followed by length prefixed Record messages, each a
+// stream with its retained events and snapshots, or a namespace, and can be
+// restored into any store.
+//
+// Namespaces are encoded as bare Namespace messages, without a version.
+package schema
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cshenton/seer/dist/mv"
+	"github.com/cshenton/seer/dist/uv"
+	"github.com/cshenton/seer/model"
+	"github.com/cshenton/seer/stream"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/vmihailenco/msgpack"
+)
+
+// Version is the current schema version, written by Marshal.
+const Version = 1
+
+// decoders decode stream records of the version at their index.
+var decoders = []func([]byte) (*stream.Stream, error){
+	decodeLegacy,
+	decodeV1,
+}
+
+// modelDecoders decode model records of the version at their index.
+var modelDecoders = []func([]byte) (*model.Model, error){
+	decodeLegacyModel,
+	decodeV1Model,
+}
+
+// Marshal encodes the stream at the current schema version.
+func Marshal(s *stream.Stream) (data []byte, err error) {
+	ts, err := timestampProto(s.Time)
+	if err != nil {
+		return nil, err
+	}
+	pb := &Stream{
+		Config: &Config{
+			Name:      s.Config.Name,
+			Period:    s.Config.Period,
+			Min:       s.Config.Min,
+			Max:       s.Config.Max,
+			Domain:    int32(s.Config.Domain),
+			Retention: retentionProto(s.Config.Retention),
+			Snapshots: snapshotPolicyProto(s.Config.Snapshots),
+			Labels:    s.Config.Labels,
+			Ttl:       durationProto(s.Config.TTL),
+		},
+		Model:    modelProto(s.Model),
+		Time:     ts,
+		Revision: s.Revision,
+	}
+	body, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{Version}, body...), nil
+}
+
+// Unmarshal decodes a stream record of any known version, and returns the
+// version it was written at.
+func Unmarshal(data []byte) (s *stream.Stream, version int, err error) {
+	version, body, err := split(data)
+	if err != nil {
+		return nil, version, err
+	}
+	s, err = decoders[version](body)
+	if err == nil {
+		err = complete(s.Model)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to decode version %v record: %v", version, err)
+		return nil, version, err
+	}
+	return s, version, nil
+}
+
+// UnmarshalMeta decodes a stream record of any known version without its
+// model, which is left nil, so is cheaper than Unmarshal when only the
+// configuration, time and revision are needed.
+func UnmarshalMeta(data []byte) (s *stream.Stream, err error) {
+	version, body, err := split(data)
+	if err != nil {
+		return nil, err
+	}
+	if version == 1 {
+		pb := &StreamMeta{}
+		err = proto.Unmarshal(body, pb)
+		if err == nil {
+			s, err = streamFromProto(pb.Config, pb.Time, pb.Revision)
+		}
+	} else {
+		s, err = decoders[version](body)
+		if err == nil {
+			s.Model = nil
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to decode version %v record: %v", version, err)
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarshalModel encodes the model at the current schema version.
+func MarshalModel(m *model.Model) (data []byte, err error) {
+	body, err := proto.Marshal(modelProto(m))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{Version}, body...), nil
+}
+
+// UnmarshalModel decodes a model record of any known version, and returns the
+// version it was written at.
+func UnmarshalModel(data []byte) (m *model.Model, version int, err error) {
+	version, body, err := split(data)
+	if err != nil {
+		return nil, version, err
+	}
+	m, err = modelDecoders[version](body)
+	if err == nil {
+		err = complete(m)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to decode version %v record: %v", version, err)
+		return nil, version, err
+	}
+	return m, version, nil
+}
+
+// split separates a record into its version and body. Legacy msgpack records
+// begin with a map header, which is never a valid version byte.
+func split(data []byte) (version int, body []byte, err error) {
+	if len(data) == 0 {
+		err = fmt.Errorf("record is empty")
+		return 0, nil, err
+	}
+	if data[0] >= 0x80 {
+		return 0, data, nil
+	}
+	version = int(data[0])
+	if version == 0 || version >= len(decoders) {
+		err = fmt.Errorf("record version %v is not supported, the latest is %v", version, Version)
+		return version, nil, err
+	}
+	return version, data[1:], nil
+}
+
+// complete returns an error if the model is missing a component, or has one
+// of the wrong shape, so that corrupt records fail to decode rather than panic
+// once used.
+func complete(m *model.Model) (err error) {
+	if m.Deterministic == nil || m.Deterministic.Normal == nil {
+		return fmt.Errorf("model has no deterministic component")
+	}
+	if len(m.Deterministic.Location) < 2 {
+		return fmt.Errorf("model's deterministic component has dimension %v, but needs a level and trend", len(m.Deterministic.Location))
+	}
+	err = shaped(m.Deterministic.Normal)
+	if err != nil {
+		return fmt.Errorf("model's deterministic component is invalid: %v", err)
+	}
+	if m.Stochastic == nil || m.Stochastic.Normal == nil {
+		return fmt.Errorf("model has no stochastic component")
+	}
+	err = shaped(m.Stochastic.Normal)
+	if err != nil {
+		return fmt.Errorf("model's stochastic component is invalid: %v", err)
+	}
+	if m.RCE == nil {
+		return fmt.Errorf("model has no covariance estimator")
+	}
+
+	r := m.RCE
+	n := len(r.Ratios)
+	if len(r.Weights) != n || len(r.Levels) != n || len(r.Variances) != n {
+		return fmt.Errorf("covariance estimator has %v ratios, but %v weights, %v levels and %v variances", n, len(r.Weights), len(r.Levels), len(r.Variances))
+	}
+	for i := range r.Ratios {
+		if r.Levels[i] == nil || r.Variances[i] == nil {
+			return fmt.Errorf("covariance estimator is missing ratio %v's posterior", i)
+		}
+		if len(r.Levels[i].Location) != 1 {
+			return fmt.Errorf("covariance estimator level %v has dimension %v, but should have 1", i, len(r.Levels[i].Location))
+		}
+		err = shaped(r.Levels[i])
+		if err != nil {
+			return fmt.Errorf("covariance estimator level %v is invalid: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// shaped returns an error if the normal has no location, or its covariance
+// isn't a square matrix of its location's dimension.
+func shaped(n *mv.Normal) (err error) {
+	d := len(n.Location)
+	if d == 0 || len(n.Covariance) != d*d {
+		return fmt.Errorf("location has dimension %v, but covariance has %v entries", d, len(n.Covariance))
+	}
+	return nil
+}
+
+// decodeLegacy decodes an unversioned msgpack stream. Fields that have since
+// been added are left at their zero values, which the model treats as
+// defaults.
+func decodeLegacy(body []byte) (s *stream.Stream, err error) {
+	s = &stream.Stream{}
+	err = msgpack.Unmarshal(body, s)
+	if err != nil {
+		return nil, err
+	}
+	if s.Config == nil || s.Model == nil {
+		err = fmt.Errorf("record has no config or model")
+		return nil, err
+	}
+	return s, nil
+}
+
+// decodeLegacyModel decodes an unversioned msgpack model.
+func decodeLegacyModel(body []byte) (m *model.Model, err error) {
+	m = &model.Model{}
+	err = msgpack.Unmarshal(body, m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeV1 decodes a version 1 stream.
+func decodeV1(body []byte) (s *stream.Stream, err error) {
+	pb := &Stream{}
+	err = proto.Unmarshal(body, pb)
+	if err != nil {
+		return nil, err
+	}
+	if pb.Model == nil {
+		err = fmt.Errorf("record has no model")
+		return nil, err
+	}
+	s, err = streamFromProto(pb.Config, pb.Time, pb.Revision)
+	if err != nil {
+		return nil, err
+	}
+	s.Model = modelFromProto(pb.Model)
+	return s, nil
+}
+
+// streamFromProto converts a version 1 stream, without its model.
+func streamFromProto(conf *Config, ts *timestamp.Timestamp, rev uint64) (s *stream.Stream, err error) {
+	if conf == nil {
+		err = fmt.Errorf("record has no config")
+		return nil, err
+	}
+	s = &stream.Stream{
+		Config: &stream.Config{
+			Name:   conf.Name,
+			Period: conf.Period,
+			Min:    conf.Min,
+			Max:    conf.Max,
+			Domain: stream.Domain(conf.Domain),
+			Labels: conf.Labels,
+		},
+		Revision: rev,
+	}
+	if ts != nil {
+		s.Time, err = ptypes.Timestamp(ts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r := conf.Retention; r != nil {
+		s.Config.Retention = &stream.Retention{Count: int(r.Count)}
+		if r.Age != nil {
+			s.Config.Retention.Age, err = ptypes.Duration(r.Age)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p := conf.Snapshots; p != nil {
+		s.Config.Snapshots = &stream.SnapshotPolicy{Count: int(p.Count)}
+		if p.Interval != nil {
+			s.Config.Snapshots.Interval, err = ptypes.Duration(p.Interval)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if conf.Ttl != nil {
+		s.Config.TTL, err = ptypes.Duration(conf.Ttl)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// decodeV1Model decodes a version 1 model.
+func decodeV1Model(body []byte) (m *model.Model, err error) {
+	pb := &Model{}
+	err = proto.Unmarshal(body, pb)
+	if err != nil {
+		return nil, err
+	}
+	return modelFromProto(pb), nil
+}
+
+// durationProto converts a duration, leaving zero unset.
+func durationProto(d time.Duration) *duration.Duration {
+	if d == 0 {
+		return nil
+	}
+	return ptypes.DurationProto(d)
+}
+
+// timestampProto converts a time, leaving the zero time unset.
+func timestampProto(t time.Time) (ts *timestamp.Timestamp, err error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return ptypes.TimestampProto(t)
+}
+
+// retentionProto converts a retention policy, leaving an absent policy unset.
+func retentionProto(r *stream.Retention) (pb *Retention) {
+	if r == nil {
+		return nil
+	}
+	pb = &Retention{Count: int64(r.Count)}
+	if r.Age != 0 {
+		pb.Age = ptypes.DurationProto(r.Age)
+	}
+	return pb
+}
+
+// snapshotPolicyProto converts a snapshot policy, leaving an absent policy
+// unset.
+func snapshotPolicyProto(p *stream.SnapshotPolicy) (pb *SnapshotPolicy) {
+	if p == nil {
+		return nil
+	}
+	pb = &SnapshotPolicy{Count: int64(p.Count)}
+	if p.Interval != 0 {
+		pb.Interval = ptypes.DurationProto(p.Interval)
+	}
+	return pb
+}
+
+// modelProto converts a model to its protocol buffer message.
+func modelProto(m *model.Model) (pb *Model) {
+	pb = &Model{}
+	if d := m.Deterministic; d != nil {
+		pb.Deterministic = &Deterministic{Normal: normalProto(d.Normal)}
+		if d.Params != nil {
+			pb.Deterministic.Params = &Params{
+				LevelVar:    d.Params.LevelVar,
+				TrendVar:    d.Params.TrendVar,
+				HarmonicVar: d.Params.HarmonicVar,
+			}
+		}
+	}
+	if s := m.Stochastic; s != nil {
+		pb.Stochastic = &Stochastic{Normal: normalProto(s.Normal)}
+	}
+	if r := m.RCE; r != nil {
+		pb.Rce = &RCE{
+			Ratios:    r.Ratios,
+			Weights:   r.Weights,
+			Levels:    make([]*Normal, len(r.Levels)),
+			Variances: make([]*InverseGamma, len(r.Variances)),
+		}
+		for i, l := range r.Levels {
+			pb.Rce.Levels[i] = normalProto(l)
+		}
+		for i, v := range r.Variances {
+			pb.Rce.Variances[i] = &InverseGamma{Shape: v.Shape, Scale: v.Scale}
+		}
+	}
+	return pb
+}
+
+// modelFromProto converts a protocol buffer message to a model.
+func modelFromProto(pb *Model) (m *model.Model) {
+	m = &model.Model{}
+	if d := pb.Deterministic; d != nil {
+		m.Deterministic = &model.Deterministic{Normal: normalFromProto(d.Normal)}
+		if d.Params != nil {
+			m.Deterministic.Params = &model.Params{
+				LevelVar:    d.Params.LevelVar,
+				TrendVar:    d.Params.TrendVar,
+				HarmonicVar: d.Params.HarmonicVar,
+			}
+		}
+	}
+	if s := pb.Stochastic; s != nil {
+		m.Stochastic = &model.Stochastic{Normal: normalFromProto(s.Normal)}
+	}
+	if r := pb.Rce; r != nil {
+		m.RCE = &model.RCE{
+			Ratios:    r.Ratios,
+			Weights:   r.Weights,
+			Levels:    make([]*mv.Normal, len(r.Levels)),
+			Variances: make([]*uv.InverseGamma, len(r.Variances)),
+		}
+		for i, l := range r.Levels {
+			m.RCE.Levels[i] = normalFromProto(l)
+		}
+		for i, v := range r.Variances {
+			m.RCE.Variances[i] = &uv.InverseGamma{Shape: v.Shape, Scale: v.Scale}
+		}
+	}
+	return m
+}
+
+// normalProto converts a multivariate normal to its protocol buffer message.
+func normalProto(n *mv.Normal) (pb *Normal) {
+	if n == nil {
+		return nil
+	}
+	return &Normal{Location: n.Location, Covariance: n.Covariance}
+}
+
+// normalFromProto converts a protocol buffer message to a multivariate normal.
+func normalFromProto(pb *Normal) (n *mv.Normal) {
+	if pb == nil {
+		return nil
+	}
+	return &mv.Normal{Location: pb.Location, Covariance: pb.Covariance}
+}
@@ -0,0 +1,398 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/model"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+	"github.com/golang/protobuf/proto"
+	"github.com/vmihailenco/msgpack"
+)
+
+func testStream(t *testing.T) (s *stream.Stream) {
+	s, err := stream.New("sales", 86400, 0, 0, 1)
+	if err != nil {
+		t.Fatal("unexpected error in stream.New:", err)
+	}
+	n := 5
+	vals := make([]float64, n)
+	times := make([]time.Time, n)
+	for i := range vals {
+		vals[i] = float64(i + 1)
+		times[i] = time.Date(2016, 1, 1+i, 0, 0, 0, 0, time.UTC)
+	}
+	s.Update(vals, times)
+	s.Revision = 7
+	return s
+}
+
+func TestMarshal(t *testing.T) {
+	tt := []struct {
+		name   string
+		params *model.Params
+	}{
+		{"default params", nil},
+		{"fitted params", &model.Params{LevelVar: 1, TrendVar: 2, HarmonicVar: 3}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testStream(t)
+			s.Model.Deterministic.Params = tc.params
+
+			data, err := schema.Marshal(s)
+			if err != nil {
+				t.Fatal("unexpected error in Marshal:", err)
+			}
+			if data[0] != schema.Version {
+				t.Errorf("expected version byte %v, but got %v", schema.Version, data[0])
+			}
+
+			got, v, err := schema.Unmarshal(data)
+			if err != nil {
+				t.Fatal("unexpected error in Unmarshal:", err)
+			}
+			if v != schema.Version {
+				t.Errorf("expected version %v, but got %v", schema.Version, v)
+			}
+			if !reflect.DeepEqual(got.Config, s.Config) || !got.Time.Equal(s.Time) || got.Revision != s.Revision {
+				t.Errorf("expected stream %v, but got %v", s, got)
+			}
+			if (tc.params == nil) != (got.Model.Deterministic.Params == nil) {
+				t.Errorf("expected params %v, but got %v", tc.params, got.Model.Deterministic.Params)
+			}
+			_, want, _, _ := s.Forecast(3, nil)
+			_, have, _, _ := got.Forecast(3, nil)
+			for i := range want {
+				if want[i] != have[i] {
+					t.Errorf("expected forecast %v, but got %v", want, have)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshalLabels(t *testing.T) {
+	tt := []struct {
+		name   string
+		labels map[string]string
+	}{
+		{"none", nil},
+		{"single", map[string]string{"team": "growth"}},
+		{"several", map[string]string{"team": "growth", "region": "eu", "example.com/tier": ""}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testStream(t)
+			s.Config.Labels = tc.labels
+
+			data, _ := schema.Marshal(s)
+			got, _, err := schema.Unmarshal(data)
+			if err != nil {
+				t.Fatal("unexpected error in Unmarshal:", err)
+			}
+			if len(got.Config.Labels) != len(tc.labels) {
+				t.Fatalf("expected labels %v, but got %v", tc.labels, got.Config.Labels)
+			}
+			for k, v := range tc.labels {
+				if got.Config.Labels[k] != v {
+					t.Errorf("expected labels %v, but got %v", tc.labels, got.Config.Labels)
+				}
+			}
+
+			meta, err := schema.UnmarshalMeta(data)
+			if err != nil {
+				t.Fatal("unexpected error in UnmarshalMeta:", err)
+			}
+			if len(meta.Config.Labels) != len(tc.labels) {
+				t.Errorf("expected meta labels %v, but got %v", tc.labels, meta.Config.Labels)
+			}
+		})
+	}
+}
+
+func TestMarshalRetention(t *testing.T) {
+	tt := []struct {
+		name      string
+		retention *stream.Retention
+	}{
+		{"none", nil},
+		{"unbounded", &stream.Retention{}},
+		{"count", &stream.Retention{Count: 100}},
+		{"count and age", &stream.Retention{Count: 100, Age: 48 * time.Hour}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testStream(t)
+			s.Config.Retention = tc.retention
+
+			data, _ := schema.Marshal(s)
+			got, _, err := schema.Unmarshal(data)
+			if err != nil {
+				t.Fatal("unexpected error in Unmarshal:", err)
+			}
+			if (tc.retention == nil) != (got.Config.Retention == nil) {
+				t.Fatalf("expected retention %v, but got %v", tc.retention, got.Config.Retention)
+			}
+			if tc.retention != nil && *got.Config.Retention != *tc.retention {
+				t.Errorf("expected retention %v, but got %v", tc.retention, got.Config.Retention)
+			}
+		})
+	}
+}
+
+func TestMarshalSnapshotPolicy(t *testing.T) {
+	s := testStream(t)
+	s.Config.Snapshots = &stream.SnapshotPolicy{Count: 24, Interval: time.Hour}
+
+	data, _ := schema.Marshal(s)
+	got, _, err := schema.Unmarshal(data)
+	if err != nil {
+		t.Fatal("unexpected error in Unmarshal:", err)
+	}
+	if got.Config.Snapshots == nil || *got.Config.Snapshots != *s.Config.Snapshots {
+		t.Errorf("expected snapshot policy %v, but got %v", s.Config.Snapshots, got.Config.Snapshots)
+	}
+}
+
+func TestMarshalTTL(t *testing.T) {
+	s := testStream(t)
+	s.Config.TTL = 72 * time.Hour
+
+	data, _ := schema.Marshal(s)
+	got, _, err := schema.Unmarshal(data)
+	if err != nil {
+		t.Fatal("unexpected error in Unmarshal:", err)
+	}
+	if got.Config.TTL != s.Config.TTL {
+		t.Errorf("expected ttl %v, but got %v", s.Config.TTL, got.Config.TTL)
+	}
+	meta, err := schema.UnmarshalMeta(data)
+	if err != nil {
+		t.Fatal("unexpected error in UnmarshalMeta:", err)
+	}
+	if meta.Config.TTL != s.Config.TTL {
+		t.Errorf("expected meta ttl %v, but got %v", s.Config.TTL, meta.Config.TTL)
+	}
+}
+
+func TestUnmarshalLegacy(t *testing.T) {
+	s := testStream(t)
+	data, _ := msgpack.Marshal(s)
+
+	got, v, err := schema.Unmarshal(data)
+	if err != nil {
+		t.Fatal("unexpected error in Unmarshal:", err)
+	}
+	if v != 0 {
+		t.Errorf("expected version %v, but got %v", 0, v)
+	}
+	if got.Config.Name != s.Config.Name || got.Revision != s.Revision {
+		t.Errorf("expected stream %v, but got %v", s, got)
+	}
+}
+
+func TestUnmarshalMeta(t *testing.T) {
+	s := testStream(t)
+	s.Config.Retention = &stream.Retention{Count: 10, Age: time.Hour}
+	current, _ := schema.Marshal(s)
+	legacy, _ := msgpack.Marshal(s)
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{"current", current},
+		{"legacy", legacy},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := schema.UnmarshalMeta(tc.data)
+			if err != nil {
+				t.Fatal("unexpected error in UnmarshalMeta:", err)
+			}
+			if got.Config.Name != s.Config.Name || got.Revision != s.Revision || !got.Time.Equal(s.Time) {
+				t.Errorf("expected stream %v, but got %v", s, got)
+			}
+			if got.Config.Retention == nil || got.Config.Retention.Age != time.Hour {
+				t.Errorf("expected retention %v, but got %v", s.Config.Retention, got.Config.Retention)
+			}
+			if got.Model != nil {
+				t.Errorf("expected no model, but got %v", got.Model)
+			}
+		})
+	}
+
+	_, err := schema.UnmarshalMeta([]byte{schema.Version, 0xff})
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func TestUnmarshalLegacyFields(t *testing.T) {
+	// Streams written before the RCE was a conjugate learner stored its
+	// posteriors as theta and zeta, which must be dropped, not fail.
+	type ig struct{ Shape, Scale float64 }
+	type rce struct{ Theta, Zeta ig }
+	type legacyModel struct {
+		Deterministic *model.Deterministic
+		Stochastic    *model.Stochastic
+		RCE           *rce
+	}
+	type legacy struct {
+		Config *stream.Config
+		Model  *legacyModel
+		Time   time.Time
+	}
+	s := testStream(t)
+	data, _ := msgpack.Marshal(&legacy{
+		Config: s.Config,
+		Model: &legacyModel{
+			Deterministic: s.Model.Deterministic,
+			Stochastic:    s.Model.Stochastic,
+			RCE:           &rce{Theta: ig{2, 80}, Zeta: ig{2, 10}},
+		},
+		Time: s.Time,
+	})
+
+	got, _, err := schema.Unmarshal(data)
+	if err != nil {
+		t.Fatal("unexpected error in Unmarshal:", err)
+	}
+	err = got.Update([]float64{1}, []time.Time{got.Time.Add(got.Config.Duration())})
+	if err != nil {
+		t.Error("unexpected error updating legacy stream:", err)
+	}
+}
+
+func TestUnmarshalErrs(t *testing.T) {
+	s := testStream(t)
+	data, _ := schema.Marshal(s)
+	noModel, _ := schema.Marshal(s)
+	noModel = noModel[:1]
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"unknown version", append([]byte{schema.Version + 1}, data[1:]...)},
+		{"zero version", append([]byte{0}, data[1:]...)},
+		{"truncated", data[:len(data)/2]},
+		{"no model", noModel},
+		{"corrupt legacy", []byte{0x83, 0x01}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _, err := schema.Unmarshal(tc.data)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if s != nil {
+				t.Error("expected nil stream, but it was", s)
+			}
+		})
+	}
+}
+
+func TestUnmarshalMalformed(t *testing.T) {
+	tt := []struct {
+		name   string
+		mangle func(m *schema.Model)
+	}{
+		{"short weights", func(m *schema.Model) { m.Rce.Weights = m.Rce.Weights[:1] }},
+		{"short levels", func(m *schema.Model) { m.Rce.Levels = m.Rce.Levels[:1] }},
+		{"short variances", func(m *schema.Model) { m.Rce.Variances = m.Rce.Variances[:1] }},
+		{"empty level", func(m *schema.Model) { m.Rce.Levels[0] = &schema.Normal{} }},
+		{"wide level", func(m *schema.Model) {
+			m.Rce.Levels[0] = &schema.Normal{Location: []float64{0, 0}, Covariance: []float64{1, 0, 0, 1}}
+		}},
+		{"level covariance", func(m *schema.Model) { m.Rce.Levels[0].Covariance = []float64{1, 1} }},
+		{"deterministic covariance", func(m *schema.Model) {
+			n := m.Deterministic.Normal
+			n.Covariance = n.Covariance[:len(n.Covariance)-1]
+		}},
+		{"deterministic without trend", func(m *schema.Model) {
+			m.Deterministic.Normal = &schema.Normal{Location: []float64{1}, Covariance: []float64{1}}
+		}},
+		{"stochastic covariance", func(m *schema.Model) {
+			n := m.Stochastic.Normal
+			n.Covariance = append(n.Covariance, 1)
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			data, _ := schema.Marshal(testStream(t))
+			pb := &schema.Stream{}
+			err := proto.Unmarshal(data[1:], pb)
+			if err != nil {
+				t.Fatal("unexpected error in proto.Unmarshal:", err)
+			}
+			tc.mangle(pb.Model)
+
+			body, _ := proto.Marshal(pb)
+			s, _, err := schema.Unmarshal(append([]byte{schema.Version}, body...))
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if s != nil {
+				t.Error("expected nil stream, but it was", s)
+			}
+			body, _ = proto.Marshal(pb.Model)
+			_, _, err = schema.UnmarshalModel(append([]byte{schema.Version}, body...))
+			if err == nil {
+				t.Error("expected error in UnmarshalModel, but it was nil")
+			}
+		})
+	}
+}
+
+func TestMarshalModel(t *testing.T) {
+	s := testStream(t)
+
+	data, err := schema.MarshalModel(s.Model)
+	if err != nil {
+		t.Fatal("unexpected error in MarshalModel:", err)
+	}
+	m, v, err := schema.UnmarshalModel(data)
+	if err != nil {
+		t.Fatal("unexpected error in UnmarshalModel:", err)
+	}
+	if v != schema.Version {
+		t.Errorf("expected version %v, but got %v", schema.Version, v)
+	}
+	if m.RCE.Noise() != s.Model.RCE.Noise() {
+		t.Errorf("expected noise %v, but got %v", s.Model.RCE.Noise(), m.RCE.Noise())
+	}
+
+	legacy, _ := msgpack.Marshal(s.Model)
+	m, v, err = schema.UnmarshalModel(legacy)
+	if err != nil {
+		t.Fatal("unexpected error in UnmarshalModel:", err)
+	}
+	if v != 0 {
+		t.Errorf("expected version %v, but got %v", 0, v)
+	}
+	if m.RCE.Noise() != s.Model.RCE.Noise() {
+		t.Errorf("expected noise %v, but got %v", s.Model.RCE.Noise(), m.RCE.Noise())
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// Backup calls fn with the record of every stream, in name order, from a
+// single read transaction, so the backup is consistent while the store is in
+// use. It stops at the first error from fn.
+func (s *Store) Backup(fn func(r *store.Record) error) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT name FROM streams ORDER BY name`)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		r, err := readRecord(tx, name)
+		if err != nil {
+			return err
+		}
+		err = fn(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRecord returns the record of the stream at name, with its events and
+// snapshots, or an error if no such stream exists.
+func (s *Store) GetRecord(name string) (r *store.Record, err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	r, err = readRecord(tx, name)
+	if err == sql.ErrNoRows {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	return r, err
+}
+
+// readRecord reads the stream at name, and its events and snapshots.
+func readRecord(tx *sql.Tx, name string) (r *store.Record, err error) {
+	st, _, err := scanStream(tx.QueryRow(`SELECT `+streamColumns+` FROM streams WHERE name = ?`, name))
+	if err != nil {
+		return nil, err
+	}
+	err = loadLabels(tx, st)
+	if err != nil {
+		return nil, err
+	}
+	r = &store.Record{Stream: st}
+
+	rows, err := tx.Query(`SELECT time, value FROM events WHERE stream = ? ORDER BY time`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ts string
+			ev stream.Event
+		)
+		err = rows.Scan(&ts, &ev.Value)
+		if err != nil {
+			return nil, err
+		}
+		ev.Time, err = time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, &store.CorruptDataError{Kind: "event", Entity: name, Err: err}
+		}
+		r.Events = append(r.Events, &ev)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	snaps, err := tx.Query(`SELECT data FROM snapshots WHERE stream = ? ORDER BY time`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer snaps.Close()
+	for snaps.Next() {
+		var data []byte
+		err = snaps.Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+		snap, _, err := schema.Unmarshal(data)
+		if err != nil {
+			return nil, &store.CorruptDataError{Kind: "snapshot", Entity: name, Err: err}
+		}
+		r.Snapshots = append(r.Snapshots, snap)
+	}
+	return r, snaps.Err()
+}
+
+// RestoreStream saves the record's stream at its name, with its events and
+// snapshots, replacing any stream stored there.
+func (s *Store) RestoreStream(r *store.Record) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM streams WHERE name = ?`, r.Stream.Config.Name)
+	if err != nil {
+		return err
+	}
+	err = putRecord(tx, r)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// putRecord saves the record's stream at its name, with its events and
+// snapshots, where no stream is stored.
+func putRecord(tx *sql.Tx, r *store.Record) (err error) {
+	name := r.Stream.Config.Name
+	err = insertStream(tx, name, r.Stream)
+	if err != nil {
+		return err
+	}
+	for _, e := range r.Events {
+		_, err = tx.Exec(
+			`INSERT INTO events (stream, time, value) VALUES (?, ?, ?)`,
+			name, eventTime(e.Time), e.Value,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	for _, snap := range r.Snapshots {
+		data, err := schema.Marshal(snap)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			`INSERT INTO snapshots (stream, time, data) VALUES (?, ?, ?)`,
+			name, eventTime(snap.Time), data,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
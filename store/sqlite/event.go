@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+// eventTimeFormat is a fixed width RFC 3339 format, so that event times sort
+// in time order as text.
+const eventTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// eventTime returns the time as an events table column value.
+func eventTime(t time.Time) string {
+	return t.UTC().Format(eventTimeFormat)
+}
+
+// putEvents adds the events to the stream's history, then discards events
+// outside its retention policy. If the stream has no policy, its history is
+// removed.
+func putEvents(tx *sql.Tx, name string, s *stream.Stream, events []*stream.Event) (err error) {
+	r := s.Config.Retention
+	if r == nil {
+		_, err = tx.Exec(`DELETE FROM events WHERE stream = ?`, name)
+		return err
+	}
+
+	for _, e := range events {
+		_, err = tx.Exec(
+			`INSERT OR REPLACE INTO events (stream, time, value) VALUES (?, ?, ?)`,
+			name, eventTime(e.Time), e.Value,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cutoff := r.Cutoff(s.Time); !cutoff.IsZero() {
+		_, err = tx.Exec(`DELETE FROM events WHERE stream = ? AND time < ?`, name, eventTime(cutoff))
+		if err != nil {
+			return err
+		}
+	}
+	if r.Count > 0 {
+		_, err = tx.Exec(
+			`DELETE FROM events WHERE stream = ? AND time NOT IN (
+				SELECT time FROM events WHERE stream = ? ORDER BY time DESC LIMIT ?
+			)`,
+			name, name, r.Count,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEvents returns up to limit of the stream's retained events in [from, to),
+// in time order, where a zero from or to is unbounded, and a non-positive
+// limit returns all of them. It returns an error if no stream exists at name.
+func (s *Store) GetEvents(name string, from, to time.Time, limit int) (e []*stream.Event, err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ok, err := exists(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+
+	query := `SELECT time, value FROM events WHERE stream = ?`
+	args := []interface{}{name}
+	if !from.IsZero() {
+		query += ` AND time >= ?`
+		args = append(args, eventTime(from))
+	}
+	if !to.IsZero() {
+		query += ` AND time < ?`
+		args = append(args, eventTime(to))
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	query += ` ORDER BY time LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ts string
+			ev stream.Event
+		)
+		err = rows.Scan(&ts, &ev.Value)
+		if err != nil {
+			return nil, err
+		}
+		ev.Time, err = time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, &store.CorruptDataError{Kind: "event", Entity: name, Err: err}
+		}
+		e = append(e, &ev)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/stream"
+)
+
+// labelBatch is the most streams whose labels are loaded in one query, to
+// stay within sqlite's limit on query parameters.
+const labelBatch = 500
+
+// queryer is implemented by both sql.DB and sql.Tx.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// putLabels replaces the rows of the labels table for the stream at name.
+func putLabels(tx *sql.Tx, name string, labels map[string]string) (err error) {
+	_, err = tx.Exec(`DELETE FROM labels WHERE stream = ?`, name)
+	if err != nil {
+		return err
+	}
+	for k, v := range labels {
+		_, err = tx.Exec(`INSERT INTO labels (stream, key, value) VALUES (?, ?, ?)`, name, k, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadLabels sets the labels of the streams from the labels table.
+func loadLabels(q queryer, streams ...*stream.Stream) (err error) {
+	for len(streams) > 0 {
+		n := len(streams)
+		if n > labelBatch {
+			n = labelBatch
+		}
+		err = loadBatch(q, streams[:n])
+		if err != nil {
+			return err
+		}
+		streams = streams[n:]
+	}
+	return nil
+}
+
+// loadBatch sets the labels of a batch of streams from the labels table.
+func loadBatch(q queryer, streams []*stream.Stream) (err error) {
+	byName := make(map[string]*stream.Stream, len(streams))
+	args := make([]interface{}, len(streams))
+	for i, st := range streams {
+		byName[st.Config.Name] = st
+		args[i] = st.Config.Name
+	}
+	rows, err := q.Query(`SELECT stream, key, value FROM labels WHERE stream IN (`+params(len(args))+`)`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, k, v string
+		err = rows.Scan(&name, &k, &v)
+		if err != nil {
+			return err
+		}
+		conf := byName[name].Config
+		if conf.Labels == nil {
+			conf.Labels = make(map[string]string)
+		}
+		conf.Labels[k] = v
+	}
+	return rows.Err()
+}
+
+// selectorWhere returns a condition on the streams table, and its arguments,
+// that holds for streams with labels matching the selector.
+func selectorWhere(sel label.Selector) (where string, args []interface{}) {
+	var conds []string
+	for _, r := range sel {
+		sub := `SELECT stream FROM labels WHERE key = ?`
+		args = append(args, r.Key)
+		if len(r.Values) > 0 {
+			sub += ` AND value IN (` + params(len(r.Values)) + `)`
+			for _, v := range r.Values {
+				args = append(args, v)
+			}
+		}
+		switch r.Operator {
+		case label.NotEquals, label.NotIn, label.DoesNotExist:
+			conds = append(conds, `name NOT IN (`+sub+`)`)
+		default:
+			conds = append(conds, `name IN (`+sub+`)`)
+		}
+	}
+	return strings.Join(conds, ` AND `), args
+}
+
+// params returns n comma separated query parameters.
+func params(n int) string {
+	return strings.TrimSuffix(strings.Repeat(`?, `, n), `, `)
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+// ListStreams returns the streams selected by the options, without their
+// models, and the total number of streams with the prefix and matching labels.
+// Streams are listed by the primary key, or by the last event time index, and
+// selected by label with the label index.
+func (s *Store) ListStreams(opts *store.ListOptions) (st []*stream.Stream, total int, err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	where := `name >= ?`
+	args := []interface{}{opts.Prefix}
+	if end, ok := prefixEnd(opts.Prefix); ok {
+		where += ` AND name < ?`
+		args = append(args, end)
+	}
+	if len(opts.Selector) > 0 {
+		cond, condArgs := selectorWhere(opts.Selector)
+		where += ` AND ` + cond
+		args = append(args, condArgs...)
+	}
+	err = tx.QueryRow(`SELECT COUNT(*) FROM streams WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	key, cmp, dir := `name`, `>`, `ASC`
+	if opts.Descending {
+		cmp, dir = `<`, `DESC`
+	}
+	order := `name ` + dir
+	if opts.Order == store.ByLastEvent {
+		key = `(IFNULL(last_event_time, ''), name)`
+		order = `IFNULL(last_event_time, '') ` + dir + `, ` + order
+	}
+	if opts.After != nil {
+		if opts.Order == store.ByLastEvent {
+			where += ` AND ` + key + ` ` + cmp + ` (?, ?)`
+			args = append(args, cursorTime(opts.After), opts.After.Name)
+		} else {
+			where += ` AND name ` + cmp + ` ?`
+			args = append(args, opts.After.Name)
+		}
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, limit)
+
+	rows, err := tx.Query(`SELECT `+metaColumns+` FROM streams WHERE `+where+` ORDER BY `+order+` LIMIT ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		str, err := scanMeta(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		st = append(st, str)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+
+	err = loadLabels(tx, st...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return st, total, nil
+}
+
+// cursorTime returns the cursor's time as it is compared in the last event
+// time index.
+func cursorTime(c *store.Cursor) string {
+	if c.Time.IsZero() {
+		return ""
+	}
+	return eventTime(c.Time)
+}
+
+// prefixEnd returns the least name greater than every name with the prefix,
+// and false if there is none.
+func prefixEnd(prefix string) (end string, ok bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
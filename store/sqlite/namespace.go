@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/cshenton/seer/store"
+)
+
+// namespaceColumns are the columns of the namespaces table, in scan order.
+const namespaceColumns = `name, max_streams, max_points_per_second`
+
+// scanNamespace reads a namespace from a row of the namespaces table.
+func scanNamespace(row scanner) (ns *store.Namespace, err error) {
+	ns = &store.Namespace{}
+	err = row.Scan(&ns.Name, &ns.MaxStreams, &ns.MaxPointsPerSecond)
+	if err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateNamespace saves the provided namespace, or returns an error if a
+// namespace with its name already exists.
+func (s *Store) CreateNamespace(ns *store.Namespace) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var n int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM namespaces WHERE name = ?`, ns.Name).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return &store.AlreadyExistsError{Kind: "namespace", Entity: ns.Name}
+	}
+	_, err = tx.Exec(
+		`INSERT INTO namespaces (`+namespaceColumns+`) VALUES (?, ?, ?)`,
+		ns.Name, ns.MaxStreams, ns.MaxPointsPerSecond,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetNamespace returns the namespace stored at name, or an error if it does
+// not exist.
+func (s *Store) GetNamespace(name string) (ns *store.Namespace, err error) {
+	row := s.QueryRow(`SELECT `+namespaceColumns+` FROM namespaces WHERE name = ?`, name)
+	ns, err = scanNamespace(row)
+	if err == sql.ErrNoRows {
+		return nil, &store.NotFoundError{Kind: "namespace", Entity: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// UpdateNamespace overwrites the stored namespace with the provided one, or
+// returns an error if it does not exist.
+func (s *Store) UpdateNamespace(ns *store.Namespace) (err error) {
+	res, err := s.Exec(
+		`UPDATE namespaces SET max_streams = ?, max_points_per_second = ? WHERE name = ?`,
+		ns.MaxStreams, ns.MaxPointsPerSecond, ns.Name,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &store.NotFoundError{Kind: "namespace", Entity: ns.Name}
+	}
+	return nil
+}
+
+// DeleteNamespace deletes the namespace stored at name, or returns an error if
+// it does not exist.
+func (s *Store) DeleteNamespace(name string) (err error) {
+	res, err := s.Exec(`DELETE FROM namespaces WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &store.NotFoundError{Kind: "namespace", Entity: name}
+	}
+	return nil
+}
+
+// ListNamespaces returns every namespace, in name order.
+func (s *Store) ListNamespaces() (ns []*store.Namespace, err error) {
+	rows, err := s.Query(`SELECT ` + namespaceColumns + ` FROM namespaces ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		n, err := scanNamespace(rows)
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// putSnapshot keeps the stored stream as a snapshot if one is due under the
+// policy of st, which is about to replace it, then discards the oldest
+// snapshots beyond the policy's count. If there is no policy, the stream's
+// snapshots are removed. Nothing is kept if the stored stream is not at the
+// revision of st, as the update will conflict.
+func putSnapshot(tx *sql.Tx, name string, st *stream.Stream) (err error) {
+	p := st.Config.Snapshots
+	if p == nil {
+		_, err = tx.Exec(`DELETE FROM snapshots WHERE stream = ?`, name)
+		return err
+	}
+
+	old, _, err := scanStream(tx.QueryRow(`SELECT `+streamColumns+` FROM streams WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if old.Revision != st.Revision {
+		return nil
+	}
+
+	var (
+		last   sql.NullString
+		latest time.Time
+	)
+	err = tx.QueryRow(`SELECT MAX(time) FROM snapshots WHERE stream = ?`, name).Scan(&last)
+	if err != nil {
+		return err
+	}
+	if last.Valid {
+		latest, err = time.Parse(time.RFC3339Nano, last.String)
+		if err != nil {
+			return &store.CorruptDataError{Kind: "snapshot", Entity: name, Err: err}
+		}
+	}
+	if !p.Due(old.Time, latest) {
+		return nil
+	}
+
+	err = loadLabels(tx, old)
+	if err != nil {
+		return err
+	}
+	data, err := schema.Marshal(old)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT OR REPLACE INTO snapshots (stream, time, data) VALUES (?, ?, ?)`,
+		name, eventTime(old.Time), data,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`DELETE FROM snapshots WHERE stream = ? AND time NOT IN (
+			SELECT time FROM snapshots WHERE stream = ? ORDER BY time DESC LIMIT ?
+		)`,
+		name, name, p.Count,
+	)
+	return err
+}
+
+// RollbackStream restores the model and time of the stream at name from its
+// latest snapshot at or before to, keeping its current config, and discards
+// its later snapshots and events. It returns an error if no stream exists at
+// name, it has no such snapshot, or the snapshot is at another period. The
+// restored stream takes the next revision. If the stream is not after to, it
+// is unchanged.
+func (s *Store) RollbackStream(name string, to time.Time) (st *stream.Stream, err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cur, _, err := scanStream(tx.QueryRow(`SELECT `+streamColumns+` FROM streams WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadLabels(tx, cur)
+	if err != nil {
+		return nil, err
+	}
+	if !cur.Time.After(to) {
+		return cur, nil
+	}
+
+	var data []byte
+	err = tx.QueryRow(
+		`SELECT data FROM snapshots WHERE stream = ? AND time <= ? ORDER BY time DESC LIMIT 1`,
+		name, eventTime(to),
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, &store.NotFoundError{Kind: "snapshot", Entity: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	snap, _, err := schema.Unmarshal(data)
+	if err != nil {
+		return nil, &store.CorruptDataError{Kind: "snapshot", Entity: name, Err: err}
+	}
+	st, err = store.Rollback(cur, snap)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := schema.MarshalModel(st.Model)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(
+		`UPDATE streams SET last_event_time = ?, revision = ?, model = ? WHERE name = ?`,
+		lastEventTime(st), int64(st.Revision), state, name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	after := eventTime(st.Time)
+	_, err = tx.Exec(`DELETE FROM snapshots WHERE stream = ? AND time > ?`, name, after)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`DELETE FROM events WHERE stream = ? AND time > ?`, name, after)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	// Registers the sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are the schema changes, applied in order, that bring a database
+// up to date. Applied migrations must never be edited, only appended to.
+var migrations = []string{
+	`CREATE TABLE streams (
+		name            TEXT PRIMARY KEY,
+		period          REAL NOT NULL,
+		min             REAL NOT NULL,
+		max             REAL NOT NULL,
+		domain          INTEGER NOT NULL,
+		last_event_time TEXT,
+		revision        INTEGER NOT NULL,
+		model           BLOB NOT NULL
+	);
+	CREATE TABLE events (
+		stream TEXT NOT NULL REFERENCES streams (name) ON DELETE CASCADE,
+		time   TEXT NOT NULL,
+		value  REAL NOT NULL,
+		PRIMARY KEY (stream, time)
+	);`,
+	`ALTER TABLE streams ADD COLUMN retention_count INTEGER;
+	ALTER TABLE streams ADD COLUMN retention_age INTEGER;`,
+	`ALTER TABLE streams ADD COLUMN snapshot_count INTEGER;
+	ALTER TABLE streams ADD COLUMN snapshot_interval INTEGER;
+	CREATE TABLE snapshots (
+		stream TEXT NOT NULL REFERENCES streams (name) ON DELETE CASCADE,
+		time   TEXT NOT NULL,
+		data   BLOB NOT NULL,
+		PRIMARY KEY (stream, time)
+	);`,
+	`UPDATE streams SET last_event_time = substr(last_event_time, 1, 19) || '.' ||
+		substr(rtrim(substr(last_event_time, 21), 'Z') || '000000000', 1, 9) || 'Z'
+		WHERE last_event_time IS NOT NULL;
+	CREATE INDEX streams_last_event_time ON streams (IFNULL(last_event_time, ''), name);`,
+	`CREATE TABLE labels (
+		stream TEXT NOT NULL REFERENCES streams (name) ON DELETE CASCADE,
+		key    TEXT NOT NULL,
+		value  TEXT NOT NULL,
+		PRIMARY KEY (stream, key)
+	);
+	CREATE INDEX labels_key_value ON labels (key, value, stream);`,
+	`CREATE TABLE namespaces (
+		name                  TEXT PRIMARY KEY,
+		max_streams           INTEGER NOT NULL,
+		max_points_per_second REAL NOT NULL
+	);`,
+	`ALTER TABLE streams ADD COLUMN ttl INTEGER NOT NULL DEFAULT 0;`,
+	// Databases that applied migration 4 without its rewrite may still hold
+	// variable width times, which the index orders wrongly. Fixed width times
+	// are left as they are.
+	`UPDATE streams SET last_event_time = substr(last_event_time, 1, 19) || '.' ||
+		substr(rtrim(substr(last_event_time, 21), 'Z') || '000000000', 1, 9) || 'Z'
+		WHERE last_event_time IS NOT NULL;`,
+}
+
+// Store wraps a sqlite DB and fulfills the store.StreamStore interface.
+//
+// Stream configuration, last event time and revision are stored as columns of
+// the streams table, with only the model state encoded, so that streams can be
+// inspected with standard SQL tools. The events table holds retained raw
+// events, for streams with a retention count and age, in nanoseconds, set. The
+// snapshots table holds encoded prior states, for streams with a snapshot
+// count and interval set. The labels table holds stream labels, indexed by key
+// and value, and the namespaces table holds namespaces and their quotas. The
+// database is opened in WAL mode, so other processes may read it while Seer is
+// running.
+type Store struct {
+	*sql.DB
+}
+
+// New opens, or creates, a sqlite Store at the provided file path and applies
+// any outstanding migrations.
+func New(path string) (s *Store, err error) {
+	dsn := fmt.Sprintf("file:%v?_foreign_keys=1&_journal_mode=WAL&_busy_timeout=5000", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	s = &Store{db}
+
+	err = s.migrate()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Version returns the number of migrations applied to the database.
+func (s *Store) Version() (v int, err error) {
+	err = s.QueryRow(`SELECT COUNT(*) FROM migrations`).Scan(&v)
+	return v, err
+}
+
+// migrate idempotently applies outstanding migrations, each in a transaction.
+func (s *Store) migrate() (err error) {
+	_, err = s.Exec(`CREATE TABLE IF NOT EXISTS migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+
+	v, err := s.Version()
+	if err != nil {
+		return err
+	}
+	for i := v; i < len(migrations); i++ {
+		tx, err := s.Begin()
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(migrations[i])
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO migrations (version) VALUES (?)`, i+1)
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %v failed: %v", i+1, err)
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
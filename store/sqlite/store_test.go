@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store/sqlite"
+	"github.com/cshenton/seer/stream"
+)
+
+func testPath(t *testing.T) string {
+	f, err := ioutil.TempFile(os.TempDir(), "sqlite_test")
+	if err != nil {
+		t.Fatal("failed to create test db file")
+	}
+	f.Close()
+	return f.Name()
+}
+
+func TestNew(t *testing.T) {
+	s, err := sqlite.New(testPath(t))
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	defer s.Close()
+}
+
+func TestNewErrs(t *testing.T) {
+	_, err := sqlite.New(os.TempDir())
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	path := testPath(t)
+	s, err := sqlite.New(path)
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	v, err := s.Version()
+	if err != nil {
+		t.Fatal("unexpected error in Version:", err)
+	}
+	if v != 8 {
+		t.Errorf("expected version %v, but it was %v", 8, v)
+	}
+	s.Close()
+
+	// Reopening must not reapply migrations.
+	s, err = sqlite.New(path)
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	defer s.Close()
+	v, _ = s.Version()
+	if v != 8 {
+		t.Errorf("expected version %v, but it was %v", 8, v)
+	}
+}
+
+func TestMigrateLastEventTime(t *testing.T) {
+	path := testPath(t)
+	s, err := sqlite.New(path)
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	tt := []struct {
+		name string
+		old  string
+		want string
+	}{
+		{"whole seconds", "2016-01-01T00:00:00Z", "2016-01-01T00:00:00.000000000Z"},
+		{"fractional seconds", "2016-01-01T00:00:00.5Z", "2016-01-01T00:00:00.500000000Z"},
+		{"fixed width", "2016-01-01T00:00:00.123456789Z", "2016-01-01T00:00:00.123456789Z"},
+	}
+
+	// Times written before the rewrite are variable width.
+	for _, tc := range tt {
+		_, err = s.Exec(
+			`INSERT INTO streams (name, period, min, max, domain, last_event_time, revision, model) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			tc.name, 3600, 0, 0, 0, tc.old, 0, []byte{},
+		)
+		if err != nil {
+			t.Fatal("unexpected error while creating old data:", err)
+		}
+	}
+	_, err = s.Exec(`DELETE FROM migrations WHERE version = 8`)
+	if err != nil {
+		t.Fatal("unexpected error while reverting migration:", err)
+	}
+	s.Close()
+
+	s, err = sqlite.New(path)
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	defer s.Close()
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var last string
+			s.QueryRow(`SELECT last_event_time FROM streams WHERE name = ?`, tc.name).Scan(&last)
+			if last != tc.want {
+				t.Errorf("expected last event time %v, but got %v", tc.want, last)
+			}
+		})
+	}
+}
+
+func TestLastEventTimeFormat(t *testing.T) {
+	s, err := sqlite.New(testPath(t))
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	defer s.Close()
+
+	tt := []struct {
+		name string
+		time time.Time
+		want string
+	}{
+		{"whole seconds", time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), "2016-01-01T00:00:00.000000000Z"},
+		{"fractional seconds", time.Date(2016, 1, 1, 0, 0, 0, 5e8, time.UTC), "2016-01-01T00:00:00.500000000Z"},
+		{"other zone", time.Date(2016, 1, 1, 10, 0, 0, 0, time.FixedZone("AEST", 10*3600)), "2016-01-01T00:00:00.000000000Z"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			st, _ := stream.New(tc.name, 3600, 0, 0, 0)
+			st.Time = tc.time
+			err := s.CreateStream(tc.name, st)
+			if err != nil {
+				t.Fatal("unexpected error in CreateStream:", err)
+			}
+			var last string
+			s.QueryRow(`SELECT last_event_time FROM streams WHERE name = ?`, tc.name).Scan(&last)
+			if last != tc.want {
+				t.Errorf("expected last event time %v, but got %v", tc.want, last)
+			}
+		})
+	}
+}
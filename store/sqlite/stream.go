@@ -0,0 +1,480 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/stream"
+)
+
+// metaColumns are the columns of the streams table other than the model, in
+// scan order.
+const metaColumns = `name, period, min, max, domain, last_event_time, revision, retention_count, retention_age,
+	snapshot_count, snapshot_interval, ttl`
+
+// streamColumns are the columns of the streams table, in scan order.
+const streamColumns = metaColumns + `, model`
+
+// scanner is implemented by both sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanStream reads a stream from a row of the streams table, and whether its
+// model was stored at an old schema version.
+func scanStream(row scanner) (s *stream.Stream, old bool, err error) {
+	var state []byte
+	s, err = scanColumns(row, &state)
+	if err != nil {
+		return nil, false, err
+	}
+	m, v, err := schema.UnmarshalModel(state)
+	if err != nil {
+		return nil, false, &store.CorruptDataError{Kind: "stream", Entity: s.Config.Name, Err: err}
+	}
+	s.Model = m
+	return s, v < schema.Version, nil
+}
+
+// scanMeta reads a stream, without its model, from a row of the metadata
+// columns of the streams table.
+func scanMeta(row scanner) (s *stream.Stream, err error) {
+	return scanColumns(row)
+}
+
+// scanColumns reads a stream from the metadata columns of a row, and scans
+// any remaining columns into rest.
+func scanColumns(row scanner, rest ...interface{}) (s *stream.Stream, err error) {
+	var (
+		conf  stream.Config
+		last  sql.NullString
+		rev   int64
+		count sql.NullInt64
+		age   sql.NullInt64
+		snaps sql.NullInt64
+		every sql.NullInt64
+		ttl   int64
+	)
+	dest := []interface{}{
+		&conf.Name, &conf.Period, &conf.Min, &conf.Max, &conf.Domain, &last, &rev,
+		&count, &age, &snaps, &every, &ttl,
+	}
+	err = row.Scan(append(dest, rest...)...)
+	if err != nil {
+		return nil, err
+	}
+	if count.Valid {
+		conf.Retention = &stream.Retention{
+			Count: int(count.Int64),
+			Age:   time.Duration(age.Int64),
+		}
+	}
+	if snaps.Valid {
+		conf.Snapshots = &stream.SnapshotPolicy{
+			Count:    int(snaps.Int64),
+			Interval: time.Duration(every.Int64),
+		}
+	}
+
+	conf.TTL = time.Duration(ttl)
+
+	s = &stream.Stream{
+		Config:   &conf,
+		Revision: uint64(rev),
+	}
+	if last.Valid {
+		s.Time, err = time.Parse(time.RFC3339Nano, last.String)
+		if err != nil {
+			return nil, &store.CorruptDataError{Kind: "stream", Entity: conf.Name, Err: err}
+		}
+	}
+	return s, nil
+}
+
+// lastEventTime returns the stream's time as a column value, in the fixed
+// width event time format so that streams sort by it, or null if unset.
+func lastEventTime(s *stream.Stream) sql.NullString {
+	if s.Time.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: eventTime(s.Time), Valid: true}
+}
+
+// retention returns the stream's retention count and age as column values,
+// null if it has no retention policy.
+func retention(s *stream.Stream) (count, age sql.NullInt64) {
+	r := s.Config.Retention
+	if r == nil {
+		return count, age
+	}
+	count = sql.NullInt64{Int64: int64(r.Count), Valid: true}
+	age = sql.NullInt64{Int64: int64(r.Age), Valid: true}
+	return count, age
+}
+
+// snapshotPolicy returns the stream's snapshot count and interval as column
+// values, null if it has no snapshot policy.
+func snapshotPolicy(s *stream.Stream) (count, interval sql.NullInt64) {
+	p := s.Config.Snapshots
+	if p == nil {
+		return count, interval
+	}
+	count = sql.NullInt64{Int64: int64(p.Count), Valid: true}
+	interval = sql.NullInt64{Int64: int64(p.Interval), Valid: true}
+	return count, interval
+}
+
+// insertStream inserts a row for the stream into the streams table.
+func insertStream(tx *sql.Tx, name string, st *stream.Stream) (err error) {
+	state, err := schema.MarshalModel(st.Model)
+	if err != nil {
+		return err
+	}
+	count, age := retention(st)
+	snaps, every := snapshotPolicy(st)
+	_, err = tx.Exec(
+		`INSERT INTO streams (`+streamColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		name, st.Config.Period, st.Config.Min, st.Config.Max, st.Config.Domain,
+		lastEventTime(st), int64(st.Revision), count, age, snaps, every, int64(st.Config.TTL), state,
+	)
+	if err != nil {
+		return err
+	}
+	return putLabels(tx, name, st.Config.Labels)
+}
+
+// exists reports whether a stream with the given name is stored.
+func exists(tx *sql.Tx, name string) (ok bool, err error) {
+	var n int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM streams WHERE name = ?`, name).Scan(&n)
+	return n > 0, err
+}
+
+// CreateStream saves the provided stream at name, returns an error if a
+// stream already exists at that address.
+func (s *Store) CreateStream(name string, st *stream.Stream) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ok, err := exists(tx, name)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return &store.AlreadyExistsError{Kind: "stream", Entity: name}
+	}
+
+	err = insertStream(tx, name, st)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetStream returns the stream stored at name, or an error if the stream does
+// not exist, or has corrupted data. Models stored at an old schema version are
+// upgraded to the current version.
+func (s *Store) GetStream(name string) (st *stream.Stream, err error) {
+	row := s.QueryRow(`SELECT `+streamColumns+` FROM streams WHERE name = ?`, name)
+	st, old, err := scanStream(row)
+	if err == sql.ErrNoRows {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadLabels(s, st)
+	if err != nil {
+		return nil, err
+	}
+
+	if old {
+		state, err := schema.MarshalModel(st.Model)
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.Exec(
+			`UPDATE streams SET model = ? WHERE name = ? AND revision = ?`,
+			state, name, int64(st.Revision),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+// GetStreams returns the streams stored at names, in order, from a single
+// read transaction, with nil where no stream is stored. Models stored at an
+// old schema version are decoded, but not upgraded.
+func (s *Store) GetStreams(names []string) (st []*stream.Stream, err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	st = make([]*stream.Stream, len(names))
+	var found []*stream.Stream
+	for i, name := range names {
+		row := tx.QueryRow(`SELECT `+streamColumns+` FROM streams WHERE name = ?`, name)
+		st[i], _, err = scanStream(row)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, st[i])
+	}
+	err = loadLabels(tx, found...)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// DeleteStream deletes the stream stored at name, and its events and
+// snapshots, or returns an error if no such stream exists.
+func (s *Store) DeleteStream(name string) (err error) {
+	res, err := s.Exec(`DELETE FROM streams WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	return nil
+}
+
+// ExpireStream deletes the stream stored at name, and its events and
+// snapshots, if it is at the provided revision. It returns an error if no
+// such stream exists, or if it has been updated since that revision.
+func (s *Store) ExpireStream(name string, revision uint64) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM streams WHERE name = ? AND revision = ?`, name, int64(revision))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		ok, err := exists(tx, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &store.NotFoundError{Kind: "stream", Entity: name}
+		}
+		return &store.ConflictError{Kind: "stream", Entity: name, Revision: revision}
+	}
+	return tx.Commit()
+}
+
+// RenameStream moves the stream stored at from, with its events and
+// snapshots, to to. It returns an error if no stream exists at from, or one
+// already exists at to.
+func (s *Store) RenameStream(from, to string) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	r, err := copyRecord(tx, from, to)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM streams WHERE name = ?`, from)
+	if err != nil {
+		return err
+	}
+	err = putRecord(tx, r)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CloneStream copies the stream stored at src, with its events and snapshots,
+// to dst, at revision 0. It returns an error if no stream exists at src, or
+// one already exists at dst.
+func (s *Store) CloneStream(src, dst string) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	r, err := copyRecord(tx, src, dst)
+	if err != nil {
+		return err
+	}
+	r.Stream.Revision = 0
+	err = putRecord(tx, r)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// copyRecord reads the record of the stream at src, renamed to dst, or
+// returns an error if no stream exists at src, or one already exists at dst.
+func copyRecord(tx *sql.Tx, src, dst string) (r *store.Record, err error) {
+	ok, err := exists(tx, dst)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, &store.AlreadyExistsError{Kind: "stream", Entity: dst}
+	}
+	r, err = readRecord(tx, src)
+	if err == sql.ErrNoRows {
+		return nil, &store.NotFoundError{Kind: "stream", Entity: src}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.Rename(dst)
+	return r, nil
+}
+
+// UpdateStream overwrites the stream at name with the provided stream, adds
+// the events to its history, and keeps the stored stream as a snapshot if one
+// is due. It returns an error if no stream exists at name, or if the stored
+// stream is not at the provided stream's revision. On success the revision is
+// incremented.
+func (s *Store) UpdateStream(name string, st *stream.Stream, events ...*stream.Event) (err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = updateStream(tx, name, st, events)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	st.Revision++
+	return nil
+}
+
+// UpdateStreams applies each update as UpdateStream does, in a single
+// transaction. Updates whose stream is missing or at another revision fail
+// alone, with their errors at their positions in errs, while any other error
+// fails them all.
+func (s *Store) UpdateStreams(updates []*store.Update) (errs []error, err error) {
+	tx, err := s.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	errs = make([]error, len(updates))
+	for i, u := range updates {
+		errs[i] = updateStream(tx, u.Name, u.Stream, u.Events)
+		if errs[i] != nil && !store.Skippable(errs[i]) {
+			return nil, errs[i]
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	for i, u := range updates {
+		if errs[i] == nil {
+			u.Stream.Revision++
+		}
+	}
+	return errs, nil
+}
+
+// updateStream overwrites the stream at name, as UpdateStream, in the
+// transaction, without incrementing the provided stream's revision. It
+// returns an error before writing anything if no stream exists at name, or if
+// it is at another revision.
+func updateStream(tx *sql.Tx, name string, st *stream.Stream, events []*stream.Event) (err error) {
+	state, err := schema.MarshalModel(st.Model)
+	if err != nil {
+		return err
+	}
+	var rev int64
+	err = tx.QueryRow(`SELECT revision FROM streams WHERE name = ?`, name).Scan(&rev)
+	if err == sql.ErrNoRows {
+		return &store.NotFoundError{Kind: "stream", Entity: name}
+	}
+	if err != nil {
+		return err
+	}
+	if uint64(rev) != st.Revision {
+		return &store.ConflictError{Kind: "stream", Entity: name, Revision: st.Revision}
+	}
+
+	err = putSnapshot(tx, name, st)
+	if err != nil {
+		return err
+	}
+
+	count, age := retention(st)
+	snaps, every := snapshotPolicy(st)
+	res, err := tx.Exec(
+		`UPDATE streams
+		SET period = ?, min = ?, max = ?, domain = ?, last_event_time = ?, revision = revision + 1, model = ?,
+			retention_count = ?, retention_age = ?, snapshot_count = ?, snapshot_interval = ?, ttl = ?
+		WHERE name = ? AND revision = ?`,
+		st.Config.Period, st.Config.Min, st.Config.Max, st.Config.Domain,
+		lastEventTime(st), state, count, age, snaps, every, int64(st.Config.TTL), name, int64(st.Revision),
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &store.ConflictError{Kind: "stream", Entity: name, Revision: st.Revision}
+	}
+	err = putLabels(tx, name, st.Config.Labels)
+	if err != nil {
+		return err
+	}
+	return putEvents(tx, name, st, events)
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/store/schema"
+	"github.com/cshenton/seer/store/sqlite"
+	"github.com/cshenton/seer/store/storetest"
+	"github.com/cshenton/seer/stream"
+	"github.com/vmihailenco/msgpack"
+)
+
+func newStore(t *testing.T) (b *sqlite.Store) {
+	b, err := sqlite.New(testPath(t))
+	if err != nil {
+		t.Fatal("unexpected error in sqlite.New:", err)
+	}
+	return b
+}
+
+func setUp(t *testing.T) (b *sqlite.Store) {
+	b = newStore(t)
+	storetest.Fixtures(t, b)
+	return b
+}
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.StreamStore { return newStore(t) })
+}
+
+func TestStreamColumns(t *testing.T) {
+	b := setUp(t)
+	defer b.Close()
+
+	s, _ := b.GetStream("sales")
+	tm := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Update([]float64{3.14}, []time.Time{tm})
+	b.UpdateStream("sales", s)
+
+	var (
+		period float64
+		last   string
+		rev    int
+	)
+	err := b.QueryRow(
+		`SELECT period, last_event_time, revision FROM streams WHERE name = ?`, "sales",
+	).Scan(&period, &last, &rev)
+	if err != nil {
+		t.Fatal("unexpected error while querying streams:", err)
+	}
+	if period != 3600 || last != "2016-01-01T00:00:00.000000000Z" || rev != 1 {
+		t.Errorf("expected columns %v, %v, %v, but got %v, %v, %v", 3600, "2016-01-01T00:00:00.000000000Z", 1, period, last, rev)
+	}
+
+	got, _ := b.GetStream("sales")
+	if !got.Time.Equal(tm) {
+		t.Errorf("expected time %v, but got %v", tm, got.Time)
+	}
+}
+
+func TestDeleteStreamEvents(t *testing.T) {
+	b := setUp(t)
+	defer b.Close()
+
+	_, err := b.Exec(`INSERT INTO events (stream, time, value) VALUES (?, ?, ?)`, "sales", "2016-01-01T00:00:00Z", 1)
+	if err != nil {
+		t.Fatal("unexpected error while inserting event:", err)
+	}
+	b.DeleteStream("sales")
+
+	var n int
+	b.QueryRow(`SELECT COUNT(*) FROM events`).Scan(&n)
+	if n != 0 {
+		t.Errorf("expected events to be deleted with stream, but there were %v", n)
+	}
+}
+
+func TestGetStreamLegacy(t *testing.T) {
+	b := setUp(t)
+	defer b.Close()
+
+	s, _ := stream.New("legacy", 3600, 0, 0, 0)
+	state, _ := msgpack.Marshal(s.Model)
+	_, err := b.Exec(
+		`INSERT INTO streams (name, period, min, max, domain, revision, model) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		"legacy", 3600, 0, 0, 0, 0, state,
+	)
+	if err != nil {
+		t.Fatal("unexpected error while creating legacy data:", err)
+	}
+
+	_, err = b.GetStream("legacy")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+
+	b.QueryRow(`SELECT model FROM streams WHERE name = ?`, "legacy").Scan(&state)
+	if state[0] != schema.Version {
+		t.Errorf("expected model upgraded to version %v, but it was %v", schema.Version, state[0])
+	}
+}
+
+func TestListStreamsErrs(t *testing.T) {
+	b := setUp(t)
+	defer b.Close()
+
+	_, err := b.Exec(
+		`INSERT INTO streams (name, period, min, max, domain, last_event_time, revision, model) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		"corrupt", 3600, 0, 0, 0, "yesterday", 0, []byte("corrupt"),
+	)
+	if err != nil {
+		t.Fatal("unexpected error while creating corrupt data")
+	}
+
+	_, _, err = b.ListStreams(&store.ListOptions{})
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store"
+)
+
+func testBackup(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	snapshotStream(t, b, 10)
+
+	var recs []*store.Record
+	err := b.Backup(func(r *store.Record) error {
+		recs = append(recs, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("unexpected error in Backup:", err)
+	}
+
+	names := []string{"sales", "usage", "visits"}
+	if len(recs) != len(names) {
+		t.Fatalf("expected %v records, but there were %v", len(names), len(recs))
+	}
+	for i := range names {
+		if recs[i].Stream.Config.Name != names[i] {
+			t.Errorf("expected stream %v, but got %v", names[i], recs[i].Stream.Config.Name)
+		}
+	}
+	if len(recs[0].Events) != 10 {
+		t.Errorf("expected %v events, but there were %v", 10, len(recs[0].Events))
+	}
+	if len(recs[0].Snapshots) != 3 {
+		t.Errorf("expected %v snapshots, but there were %v", 3, len(recs[0].Snapshots))
+	}
+	if len(recs[1].Events) != 0 || len(recs[1].Snapshots) != 0 {
+		t.Errorf("expected no history for %v, but got %v", names[1], recs[1])
+	}
+}
+
+func testBackupErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	n := 0
+	want := errors.New("stop")
+	err := b.Backup(func(r *store.Record) error {
+		n++
+		return want
+	})
+	if err != want {
+		t.Errorf("expected error %v, but got %v", want, err)
+	}
+	if n != 1 {
+		t.Errorf("expected backup to stop after %v record, but it read %v", 1, n)
+	}
+}
+
+func testRestoreStream(t *testing.T, open Opener) {
+	src := setUp(t, open)
+	defer closeStore(src)
+	times := snapshotStream(t, src, 10)
+	want, _ := src.GetStream("sales")
+
+	dst := setUp(t, open)
+	defer closeStore(dst)
+	dst.DeleteStream("usage")
+
+	err := src.Backup(func(r *store.Record) error {
+		return dst.RestoreStream(r)
+	})
+	if err != nil {
+		t.Fatal("unexpected error in RestoreStream:", err)
+	}
+
+	s, err := dst.GetStream("sales")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if s.Revision != want.Revision || !s.Time.Equal(want.Time) {
+		t.Errorf("expected revision %v at %v, but got %v at %v", want.Revision, want.Time, s.Revision, s.Time)
+	}
+	_, err = dst.GetStream("usage")
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+	e, _ := dst.GetEvents("sales", time.Time{}, time.Time{}, 0)
+	if len(e) != 10 {
+		t.Errorf("expected %v events, but there were %v", 10, len(e))
+	}
+
+	// Restored snapshots can be rolled back to.
+	s, err = dst.RollbackStream("sales", times[5])
+	if err != nil {
+		t.Fatal("unexpected error in RollbackStream:", err)
+	}
+	if !s.Time.Equal(times[4]) {
+		t.Errorf("expected time %v, but got %v", times[4], s.Time)
+	}
+}
+
+func testGetRecord(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := snapshotStream(t, b, 10)
+
+	r, err := b.GetRecord("sales")
+	if err != nil {
+		t.Fatal("unexpected error in GetRecord:", err)
+	}
+	if r.Stream.Config.Name != "sales" || len(r.Events) != len(times) || len(r.Snapshots) == 0 {
+		t.Errorf("expected sales with %v events and snapshots, but got %v with %v events and %v snapshots", len(times), r.Stream.Config.Name, len(r.Events), len(r.Snapshots))
+	}
+	_, err = b.GetRecord("notastream")
+	if _, ok := err.(*store.NotFoundError); !ok {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/stream"
+)
+
+func testUpdateStreamEvents(t *testing.T, open Opener) {
+	tt := []struct {
+		name      string
+		retention *stream.Retention
+		num       int
+	}{
+		{"no retention", nil, 0},
+		{"unbounded", &stream.Retention{}, 5},
+		{"count", &stream.Retention{Count: 3}, 3},
+		{"age", &stream.Retention{Age: 2 * time.Hour}, 3},
+		{"count and age", &stream.Retention{Count: 2, Age: 2 * time.Hour}, 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b := setUp(t, open)
+			defer closeStore(b)
+
+			s, _ := b.GetStream("sales")
+			s.Config.Retention = tc.retention
+			vals, times := Events(5)
+			s.Update(vals, times)
+			events, _ := stream.Events(vals, times)
+
+			err := b.UpdateStream("sales", s, events...)
+			if err != nil {
+				t.Fatal("unexpected error in UpdateStream:", err)
+			}
+			e, err := b.GetEvents("sales", time.Time{}, time.Time{}, 0)
+			if err != nil {
+				t.Fatal("unexpected error in GetEvents:", err)
+			}
+			if len(e) != tc.num {
+				t.Fatalf("expected %v events, but there were %v", tc.num, len(e))
+			}
+			if tc.num > 0 && (e[len(e)-1].Value != 4 || !e[len(e)-1].Time.Equal(times[4])) {
+				t.Errorf("expected latest event to be retained, but got %v", e[len(e)-1])
+			}
+		})
+	}
+}
+
+func testGetEvents(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	s, _ := b.GetStream("sales")
+	s.Config.Retention = &stream.Retention{}
+	vals, times := Events(5)
+	s.Update(vals, times)
+	events, _ := stream.Events(vals, times)
+	b.UpdateStream("sales", s, events...)
+
+	tt := []struct {
+		name  string
+		from  time.Time
+		to    time.Time
+		limit int
+		first float64
+		num   int
+	}{
+		{"all", time.Time{}, time.Time{}, 0, 0, 5},
+		{"from", times[1], time.Time{}, 0, 1, 4},
+		{"to", time.Time{}, times[3], 0, 0, 3},
+		{"range", times[1], times[3], 0, 1, 2},
+		{"limit", times[1], time.Time{}, 2, 1, 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := b.GetEvents("sales", tc.from, tc.to, tc.limit)
+			if err != nil {
+				t.Fatal("unexpected error in GetEvents:", err)
+			}
+			if len(e) != tc.num {
+				t.Fatalf("expected %v events, but there were %v", tc.num, len(e))
+			}
+			if e[0].Value != tc.first {
+				t.Errorf("expected first value %v, but it was %v", tc.first, e[0].Value)
+			}
+			for i := 1; i < len(e); i++ {
+				if !e[i].Time.After(e[i-1].Time) {
+					t.Errorf("expected events in time order, but got %v then %v", e[i-1].Time, e[i].Time)
+				}
+			}
+		})
+	}
+}
+
+func testGetEventsErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	_, err := b.GetEvents("notastream", time.Time{}, time.Time{}, 0)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func testDeleteStreamHistory(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	s, _ := b.GetStream("sales")
+	s.Config.Retention = &stream.Retention{}
+	vals, times := Events(5)
+	s.Update(vals, times)
+	events, _ := stream.Events(vals, times)
+	b.UpdateStream("sales", s, events...)
+
+	b.DeleteStream("sales")
+	s, _ = stream.New("sales", 3600, 0, 0, 0)
+	b.CreateStream("sales", s)
+
+	e, err := b.GetEvents("sales", time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Fatal("unexpected error in GetEvents:", err)
+	}
+	if len(e) != 0 {
+		t.Errorf("expected events to be deleted with stream, but there were %v", len(e))
+	}
+}
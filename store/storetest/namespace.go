@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/cshenton/seer/store"
+)
+
+func testNamespaces(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	for _, name := range []string{"ops", "growth"} {
+		err := b.CreateNamespace(&store.Namespace{Name: name, MaxStreams: 10})
+		if err != nil {
+			t.Fatal("unexpected error in CreateNamespace:", err)
+		}
+	}
+	err := b.UpdateNamespace(&store.Namespace{Name: "ops", MaxStreams: 5, MaxPointsPerSecond: 2.5})
+	if err != nil {
+		t.Fatal("unexpected error in UpdateNamespace:", err)
+	}
+
+	ns, err := b.GetNamespace("ops")
+	if err != nil {
+		t.Fatal("unexpected error in GetNamespace:", err)
+	}
+	if ns.MaxStreams != 5 || ns.MaxPointsPerSecond != 2.5 {
+		t.Errorf("expected updated quotas, but got %v", ns)
+	}
+
+	all, err := b.ListNamespaces()
+	if err != nil {
+		t.Fatal("unexpected error in ListNamespaces:", err)
+	}
+	if len(all) != 2 || all[0].Name != "growth" || all[1].Name != "ops" {
+		t.Errorf("expected namespaces in name order, but got %v", all)
+	}
+
+	err = b.DeleteNamespace("ops")
+	if err != nil {
+		t.Fatal("unexpected error in DeleteNamespace:", err)
+	}
+	_, err = b.GetNamespace("ops")
+	if _, ok := err.(*store.NotFoundError); !ok {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+}
+
+func testNamespacesErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	b.CreateNamespace(&store.Namespace{Name: "growth"})
+
+	tt := []struct {
+		name string
+		fn   func() error
+	}{
+		{"create existing", func() error { return b.CreateNamespace(&store.Namespace{Name: "growth"}) }},
+		{"get missing", func() error { _, err := b.GetNamespace("ops"); return err }},
+		{"update missing", func() error { return b.UpdateNamespace(&store.Namespace{Name: "ops"}) }},
+		{"delete missing", func() error { return b.DeleteNamespace("ops") }},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storetest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+// snapshotStream updates sales with n hourly events, one per update, keeping
+// at most 3 snapshots two hours apart, and returns the event times.
+func snapshotStream(t *testing.T, b store.StreamStore, n int) (times []time.Time) {
+	vals, times := Events(n)
+	for i := range vals {
+		s, _ := b.GetStream("sales")
+		s.Config.Retention = &stream.Retention{}
+		s.Config.Snapshots = &stream.SnapshotPolicy{Count: 3, Interval: 2 * time.Hour}
+		s.Update(vals[i:i+1], times[i:i+1])
+		events, _ := stream.Events(vals[i:i+1], times[i:i+1])
+
+		err := b.UpdateStream("sales", s, events...)
+		if err != nil {
+			t.Fatal("unexpected error in UpdateStream:", err)
+		}
+	}
+	return times
+}
+
+func testRollbackStream(t *testing.T, open Opener) {
+	tt := []struct {
+		name string
+		to   int
+		time int
+	}{
+		{"latest", 9, 9},
+		{"between snapshots", 5, 4},
+		{"at snapshot", 6, 6},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b := setUp(t, open)
+			defer closeStore(b)
+			times := snapshotStream(t, b, 10)
+			cur, _ := b.GetStream("sales")
+
+			s, err := b.RollbackStream("sales", times[tc.to])
+			if err != nil {
+				t.Fatal("unexpected error in RollbackStream:", err)
+			}
+			if !s.Time.Equal(times[tc.time]) {
+				t.Errorf("expected time %v, but got %v", times[tc.time], s.Time)
+			}
+
+			st, _ := b.GetStream("sales")
+			if !st.Time.Equal(s.Time) || st.Revision != s.Revision {
+				t.Errorf("expected stored stream at %v revision %v, but got %v revision %v", s.Time, s.Revision, st.Time, st.Revision)
+			}
+			if tc.to != tc.time && st.Revision != cur.Revision+1 {
+				t.Errorf("expected revision %v, but it was %v", cur.Revision+1, st.Revision)
+			}
+			e, _ := b.GetEvents("sales", time.Time{}, time.Time{}, 0)
+			if len(e) != tc.time+1 {
+				t.Errorf("expected %v events, but there were %v", tc.time+1, len(e))
+			}
+		})
+	}
+}
+
+func testRollbackStreamDiscards(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := snapshotStream(t, b, 10)
+
+	b.RollbackStream("sales", times[5])
+
+	// Later snapshots are gone, so rolling forward is a no op.
+	s, err := b.RollbackStream("sales", times[7])
+	if err != nil {
+		t.Fatal("unexpected error in RollbackStream:", err)
+	}
+	if !s.Time.Equal(times[4]) {
+		t.Errorf("expected time %v, but got %v", times[4], s.Time)
+	}
+
+	// Corrected events may then be sent.
+	s.Update([]float64{1}, times[5:6])
+	err = b.UpdateStream("sales", s)
+	if err != nil {
+		t.Error("unexpected error in UpdateStream:", err)
+	}
+}
+
+func testRollbackStreamConfig(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := snapshotStream(t, b, 10)
+
+	s, _ := b.GetStream("sales")
+	s.Config.Labels = map[string]string{"team": "ops"}
+	s.Config.TTL = 48 * time.Hour
+	err := b.UpdateStream("sales", s)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+
+	// Config changes made since the snapshot are kept.
+	s, err = b.RollbackStream("sales", times[5])
+	if err != nil {
+		t.Fatal("unexpected error in RollbackStream:", err)
+	}
+	if !s.Time.Equal(times[4]) {
+		t.Errorf("expected time %v, but got %v", times[4], s.Time)
+	}
+	st, _ := b.GetStream("sales")
+	if st.Config.Labels["team"] != "ops" || st.Config.TTL != 48*time.Hour {
+		t.Errorf("expected labels and ttl to be kept, but got %v and %v", st.Config.Labels, st.Config.TTL)
+	}
+	if names := selectNames(b, "team=ops"); strings.Join(names, ",") != "sales" {
+		t.Errorf("expected label index to list %v, but got %v", "sales", names)
+	}
+}
+
+func testRollbackStreamPeriod(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := snapshotStream(t, b, 10)
+
+	s, _ := b.GetStream("sales")
+	s.Config.Period = 86400
+	b.UpdateStream("sales", s)
+	cur, _ := b.GetStream("sales")
+
+	_, err := b.RollbackStream("sales", times[5])
+	if _, ok := err.(*store.PeriodError); !ok {
+		t.Errorf("expected period error, but got %v", err)
+	}
+	st, _ := b.GetStream("sales")
+	if !st.Time.Equal(times[9]) || st.Revision != cur.Revision {
+		t.Errorf("expected stream to be unchanged, but got %v revision %v", st.Time, st.Revision)
+	}
+}
+
+func testRollbackStreamErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := snapshotStream(t, b, 10)
+	s, _ := b.GetStream("visits")
+	s.Update([]float64{1}, times[:1])
+	b.UpdateStream("visits", s)
+
+	tt := []struct {
+		name   string
+		stream string
+		to     time.Time
+	}{
+		{"not a stream", "notastream", times[5]},
+		{"expired snapshot", "sales", times[3]},
+		{"no snapshots", "visits", times[0].Add(-time.Hour)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := b.RollbackStream(tc.stream, tc.to)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
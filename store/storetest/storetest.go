@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package storetest is a conformance suite for store.StreamStore
+// implementations, so that every backend is held to the same behaviour.
+package storetest
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+// Opener returns a new, empty store for a single test. Stores that are
+// io.Closers are closed at the end of the test.
+type Opener func(t *testing.T) store.StreamStore
+
+// Run runs the conformance suite against stores returned by open, each test
+// in its own subtest.
+func Run(t *testing.T, open Opener) {
+	tt := []struct {
+		name string
+		test func(t *testing.T, open Opener)
+	}{
+		{"CreateStreamErrs", testCreateStreamErrs},
+		{"GetStream", testGetStream},
+		{"GetStreamErrs", testGetStreamErrs},
+		{"DeleteStream", testDeleteStream},
+		{"DeleteStreamErrs", testDeleteStreamErrs},
+		{"UpdateStream", testUpdateStream},
+		{"UpdateStreamErrs", testUpdateStreamErrs},
+		{"UpdateStreamRevision", testUpdateStreamRevision},
+		{"ListStreams", testListStreams},
+		{"ListStreamsSelector", testListStreamsSelector},
+		{"RenameStream", testRenameStream},
+		{"CloneStream", testCloneStream},
+		{"RenameCloneStreamErrs", testRenameCloneStreamErrs},
+		{"ExpireStream", testExpireStream},
+		{"GetStreams", testGetStreams},
+		{"UpdateStreams", testUpdateStreams},
+		{"UpdateStreamEvents", testUpdateStreamEvents},
+		{"GetEvents", testGetEvents},
+		{"GetEventsErrs", testGetEventsErrs},
+		{"DeleteStreamHistory", testDeleteStreamHistory},
+		{"RollbackStream", testRollbackStream},
+		{"RollbackStreamDiscards", testRollbackStreamDiscards},
+		{"RollbackStreamConfig", testRollbackStreamConfig},
+		{"RollbackStreamPeriod", testRollbackStreamPeriod},
+		{"RollbackStreamErrs", testRollbackStreamErrs},
+		{"Backup", testBackup},
+		{"BackupErrs", testBackupErrs},
+		{"RestoreStream", testRestoreStream},
+		{"GetRecord", testGetRecord},
+		{"Namespaces", testNamespaces},
+		{"NamespacesErrs", testNamespacesErrs},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, open)
+		})
+	}
+}
+
+// Fixtures creates the sales, visits and usage streams, with an hourly
+// period, that the suite's tests start from.
+func Fixtures(t *testing.T, b store.StreamStore) {
+	for _, n := range []string{"sales", "visits", "usage"} {
+		s, _ := stream.New(n, 3600, 0, 0, 0)
+		err := b.CreateStream(n, s)
+		if err != nil {
+			t.Fatal("unexpected error in CreateStream:", err)
+		}
+	}
+}
+
+// Events returns n hourly values and times, from the start of 2016.
+func Events(n int) (vals []float64, times []time.Time) {
+	vals = make([]float64, n)
+	times = make([]time.Time, n)
+	for i := range vals {
+		vals[i] = float64(i)
+		times[i] = time.Date(2016, 1, 1, i, 0, 0, 0, time.UTC)
+	}
+	return vals, times
+}
+
+// setUp opens a store holding the fixture streams.
+func setUp(t *testing.T, open Opener) (b store.StreamStore) {
+	b = open(t)
+	Fixtures(t, b)
+	return b
+}
+
+// closeStore closes the store, if it has anything to close.
+func closeStore(b store.StreamStore) {
+	if c, ok := b.(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,531 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storetest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/label"
+	"github.com/cshenton/seer/store"
+	"github.com/cshenton/seer/stream"
+)
+
+func testCreateStreamErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	s, _ := stream.New("sales", 3600, 0, 0, 0)
+	err := b.CreateStream("sales", s)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func testGetStream(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	tt := []string{"sales", "visits", "usage"}
+
+	for _, name := range tt {
+		t.Run(name, func(t *testing.T) {
+			s, err := b.GetStream(name)
+			if err != nil {
+				t.Error("unexpected error in GetStream:", err)
+			}
+			if s.Config.Name != name {
+				t.Errorf("expected stream name %v, but got %v", name, s.Config.Name)
+			}
+		})
+	}
+}
+
+func testGetStreamErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	s, err := b.GetStream("notastream")
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+	if s != nil {
+		t.Error("expected nil stream, but it was", s)
+	}
+}
+
+func testDeleteStream(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	tt := []string{"sales", "visits", "usage"}
+
+	for _, name := range tt {
+		t.Run(name, func(t *testing.T) {
+			err := b.DeleteStream(name)
+			if err != nil {
+				t.Error("unexpected error in DeleteStream:", err)
+			}
+
+			_, err = b.GetStream(name)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func testDeleteStreamErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	err := b.DeleteStream("notastream")
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func testUpdateStream(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	tt := []string{"sales", "visits", "usage"}
+
+	for _, name := range tt {
+		t.Run(name, func(t *testing.T) {
+			s, err := b.GetStream(name)
+			if err != nil {
+				t.Error("unexpected error in GetStream:", err)
+			}
+			s.Update([]float64{3.14}, []time.Time{time.Now()})
+
+			err = b.UpdateStream(name, s)
+			if err != nil {
+				t.Error("unexpected error in UpdateStream:", err)
+			}
+		})
+	}
+}
+
+func testUpdateStreamErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	name := "notastream"
+
+	s, _ := stream.New(name, 3600, 0, 0, 0)
+	err := b.UpdateStream(name, s)
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func testUpdateStreamRevision(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	name := "sales"
+
+	first, _ := b.GetStream(name)
+	second, _ := b.GetStream(name)
+
+	err := b.UpdateStream(name, first)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	if first.Revision != 1 {
+		t.Errorf("expected revision %v, but it was %v", 1, first.Revision)
+	}
+
+	err = b.UpdateStream(name, second)
+	if _, ok := err.(*store.ConflictError); !ok {
+		t.Errorf("expected conflict error, but got %v", err)
+	}
+
+	s, _ := b.GetStream(name)
+	if s.Revision != 1 {
+		t.Errorf("expected stored revision %v, but it was %v", 1, s.Revision)
+	}
+}
+
+// listStreams adds streams to the store so that they list in different orders
+// by name and by last event time, and returns the event times.
+func listStreams(t *testing.T, b store.StreamStore) (times []time.Time) {
+	for _, n := range []string{"sales_eu", "sales_us"} {
+		s, _ := stream.New(n, 3600, 0, 0, 0)
+		b.CreateStream(n, s)
+	}
+	for i, n := range []string{"visits", "sales_us", "sales"} {
+		times = append(times, time.Date(2016, 1, 1, i+1, 0, 0, 0, time.UTC))
+		s, _ := b.GetStream(n)
+		s.Update([]float64{1}, times[i:i+1])
+		err := b.UpdateStream(n, s)
+		if err != nil {
+			t.Fatal("unexpected error in UpdateStream:", err)
+		}
+	}
+	return times
+}
+
+func testListStreams(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := listStreams(t, b)
+
+	tt := []struct {
+		name  string
+		opts  *store.ListOptions
+		want  []string
+		total int
+	}{
+		{"all", &store.ListOptions{}, []string{"sales", "sales_eu", "sales_us", "usage", "visits"}, 5},
+		{"limit", &store.ListOptions{Limit: 2}, []string{"sales", "sales_eu"}, 5},
+		{"after", &store.ListOptions{After: &store.Cursor{Name: "sales_eu"}, Limit: 2}, []string{"sales_us", "usage"}, 5},
+		{"prefix", &store.ListOptions{Prefix: "sales_"}, []string{"sales_eu", "sales_us"}, 2},
+		{"prefix after", &store.ListOptions{Prefix: "sales_", After: &store.Cursor{Name: "sales"}}, []string{"sales_eu", "sales_us"}, 2},
+		{"descending", &store.ListOptions{Descending: true}, []string{"visits", "usage", "sales_us", "sales_eu", "sales"}, 5},
+		{"descending after", &store.ListOptions{Descending: true, After: &store.Cursor{Name: "usage"}, Limit: 2}, []string{"sales_us", "sales_eu"}, 5},
+		{"descending prefix", &store.ListOptions{Prefix: "sales", Descending: true}, []string{"sales_us", "sales_eu", "sales"}, 3},
+		{"by last event", &store.ListOptions{Order: store.ByLastEvent}, []string{"sales_eu", "usage", "visits", "sales_us", "sales"}, 5},
+		{"by last event after", &store.ListOptions{Order: store.ByLastEvent, After: &store.Cursor{Name: "usage"}}, []string{"visits", "sales_us", "sales"}, 5},
+		{"by last event after time", &store.ListOptions{Order: store.ByLastEvent, After: &store.Cursor{Name: "visits", Time: times[0]}}, []string{"sales_us", "sales"}, 5},
+		{"by last event descending", &store.ListOptions{Order: store.ByLastEvent, Descending: true, Limit: 2}, []string{"sales", "sales_us"}, 5},
+		{"by last event descending after", &store.ListOptions{Order: store.ByLastEvent, Descending: true, After: &store.Cursor{Name: "usage"}}, []string{"sales_eu"}, 5},
+		{"by last event prefix", &store.ListOptions{Order: store.ByLastEvent, Prefix: "sales"}, []string{"sales_eu", "sales_us", "sales"}, 3},
+		{"empty", &store.ListOptions{Prefix: "none"}, nil, 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, total, err := b.ListStreams(tc.opts)
+			if err != nil {
+				t.Fatal("unexpected error in ListStreams:", err)
+			}
+			if total != tc.total {
+				t.Errorf("expected total of %v, but it was %v", tc.total, total)
+			}
+			names := make([]string, len(s))
+			for i := range s {
+				names[i] = s[i].Config.Name
+				if s[i].Config.Period != 3600 || s[i].Model != nil {
+					t.Errorf("expected metadata of period %v, but got %v", 3600, s[i])
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("expected streams %v, but got %v", tc.want, names)
+			}
+		})
+	}
+}
+
+// labelStreams labels the streams, relabelling one and deleting another, so
+// that stale labels would be listed if the label index were not maintained.
+func labelStreams(t *testing.T, b store.StreamStore) {
+	s, _ := stream.New("archived", 3600, 0, 0, 0)
+	s.Config.Labels = map[string]string{"team": "growth"}
+	b.CreateStream("archived", s)
+	b.DeleteStream("archived")
+
+	for _, l := range []struct {
+		name   string
+		labels map[string]string
+	}{
+		{"sales", map[string]string{"team": "growth", "region": "eu"}},
+		{"usage", map[string]string{"team": "growth"}},
+		{"usage", map[string]string{"team": "ops"}},
+		{"visits", map[string]string{"team": "growth", "region": "us"}},
+	} {
+		s, _ := b.GetStream(l.name)
+		s.Config.Labels = l.labels
+		err := b.UpdateStream(l.name, s)
+		if err != nil {
+			t.Fatal("unexpected error in UpdateStream:", err)
+		}
+	}
+}
+
+func testListStreamsSelector(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	labelStreams(t, b)
+
+	tt := []struct {
+		name     string
+		selector string
+		opts     store.ListOptions
+		want     []string
+		total    int
+	}{
+		{"equals", "team=growth", store.ListOptions{}, []string{"sales", "visits"}, 2},
+		{"in", "team in (growth, ops)", store.ListOptions{}, []string{"sales", "usage", "visits"}, 3},
+		{"not equals", "team!=growth", store.ListOptions{}, []string{"usage"}, 1},
+		{"exists", "region", store.ListOptions{}, []string{"sales", "visits"}, 2},
+		{"does not exist", "!region", store.ListOptions{}, []string{"usage"}, 1},
+		{"combined", "team=growth,region!=us", store.ListOptions{}, []string{"sales"}, 1},
+		{"prefix", "team=growth", store.ListOptions{Prefix: "v"}, []string{"visits"}, 1},
+		{"limit", "team=growth", store.ListOptions{Descending: true, Limit: 1}, []string{"visits"}, 2},
+		{"by last event", "team", store.ListOptions{Order: store.ByLastEvent}, []string{"sales", "usage", "visits"}, 3},
+		{"none", "team=finance", store.ListOptions{}, nil, 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			opts.Selector, _ = label.Parse(tc.selector)
+			s, total, err := b.ListStreams(&opts)
+			if err != nil {
+				t.Fatal("unexpected error in ListStreams:", err)
+			}
+			if total != tc.total {
+				t.Errorf("expected total of %v, but it was %v", tc.total, total)
+			}
+			names := make([]string, len(s))
+			for i := range s {
+				names[i] = s[i].Config.Name
+				if s[i].Config.Labels["team"] == "" {
+					t.Errorf("expected labelled stream, but got %v", s[i].Config)
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("expected streams %v, but got %v", tc.want, names)
+			}
+		})
+	}
+}
+
+// labelledStream gives sales retained events, snapshots and a label.
+func labelledStream(t *testing.T, b store.StreamStore) (times []time.Time) {
+	times = snapshotStream(t, b, 10)
+	s, _ := b.GetStream("sales")
+	s.Config.Labels = map[string]string{"team": "growth"}
+	err := b.UpdateStream("sales", s)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	return times
+}
+
+// selectNames returns the names of the streams matching the selector.
+func selectNames(b store.StreamStore, selector string) (names []string) {
+	opts := &store.ListOptions{}
+	opts.Selector, _ = label.Parse(selector)
+	s, _, _ := b.ListStreams(opts)
+	for i := range s {
+		names = append(names, s[i].Config.Name)
+	}
+	return names
+}
+
+func testRenameStream(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := labelledStream(t, b)
+	old, _ := b.GetStream("sales")
+
+	err := b.RenameStream("sales", "revenue")
+	if err != nil {
+		t.Fatal("unexpected error in RenameStream:", err)
+	}
+	_, err = b.GetStream("sales")
+	if _, ok := err.(*store.NotFoundError); !ok {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+	s, err := b.GetStream("revenue")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if s.Config.Name != "revenue" || s.Revision != old.Revision {
+		t.Errorf("expected revenue at revision %v, but got %v at %v", old.Revision, s.Config.Name, s.Revision)
+	}
+	e, _ := b.GetEvents("revenue", time.Time{}, time.Time{}, 0)
+	if len(e) != len(times) {
+		t.Errorf("expected %v events, but there were %v", len(times), len(e))
+	}
+	if names := selectNames(b, "team=growth"); strings.Join(names, ",") != "revenue" {
+		t.Errorf("expected labels to move to revenue, but selected %v", names)
+	}
+
+	s, err = b.RollbackStream("revenue", times[5])
+	if err != nil {
+		t.Fatal("unexpected error in RollbackStream:", err)
+	}
+	if s.Config.Name != "revenue" {
+		t.Errorf("expected snapshot named %v, but it was %v", "revenue", s.Config.Name)
+	}
+}
+
+func testCloneStream(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	times := labelledStream(t, b)
+	old, _ := b.GetStream("sales")
+
+	err := b.CloneStream("sales", "sales-copy")
+	if err != nil {
+		t.Fatal("unexpected error in CloneStream:", err)
+	}
+	s, err := b.GetStream("sales-copy")
+	if err != nil {
+		t.Fatal("unexpected error in GetStream:", err)
+	}
+	if s.Config.Name != "sales-copy" || s.Revision != 0 {
+		t.Errorf("expected sales-copy at revision 0, but got %v at %v", s.Config.Name, s.Revision)
+	}
+	e, _ := b.GetEvents("sales-copy", time.Time{}, time.Time{}, 0)
+	if len(e) != len(times) {
+		t.Errorf("expected %v events, but there were %v", len(times), len(e))
+	}
+	if names := selectNames(b, "team=growth"); strings.Join(names, ",") != "sales,sales-copy" {
+		t.Errorf("expected both streams to be labelled, but selected %v", names)
+	}
+
+	// The clone is independent of its source.
+	_, err = b.RollbackStream("sales-copy", times[5])
+	if err != nil {
+		t.Fatal("unexpected error in RollbackStream:", err)
+	}
+	cur, _ := b.GetStream("sales")
+	if !cur.Time.Equal(old.Time) || cur.Revision != old.Revision {
+		t.Errorf("expected source unchanged at %v, but it was at %v", old.Time, cur.Time)
+	}
+	e, _ = b.GetEvents("sales", time.Time{}, time.Time{}, 0)
+	if len(e) != len(times) {
+		t.Errorf("expected %v source events, but there were %v", len(times), len(e))
+	}
+}
+
+func testRenameCloneStreamErrs(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	tt := []struct {
+		name   string
+		fn     func() error
+		exists bool
+	}{
+		{"rename missing", func() error { return b.RenameStream("missing", "other") }, false},
+		{"rename to existing", func() error { return b.RenameStream("sales", "visits") }, true},
+		{"clone missing", func() error { return b.CloneStream("missing", "other") }, false},
+		{"clone to existing", func() error { return b.CloneStream("sales", "visits") }, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			_, exists := err.(*store.AlreadyExistsError)
+			_, missing := err.(*store.NotFoundError)
+			if exists != tc.exists || missing == tc.exists {
+				t.Errorf("expected already exists %v, but got %v", tc.exists, err)
+			}
+		})
+	}
+	if _, err := b.GetStream("sales"); err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
+
+func testExpireStream(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	s, _ := b.GetStream("sales")
+	s.Config.TTL = time.Hour
+	err := b.UpdateStream("sales", s)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	listed, _, _ := b.ListStreams(&store.ListOptions{Prefix: "sales"})
+	if len(listed) != 1 || listed[0].Config.TTL != time.Hour {
+		t.Fatalf("expected sales to be listed with ttl %v, but got %v", time.Hour, listed)
+	}
+
+	err = b.ExpireStream("sales", 0)
+	if _, ok := err.(*store.ConflictError); !ok {
+		t.Errorf("expected conflict error, but got %v", err)
+	}
+	err = b.ExpireStream("sales", 1)
+	if err != nil {
+		t.Fatal("unexpected error in ExpireStream:", err)
+	}
+	_, err = b.GetStream("sales")
+	if _, ok := err.(*store.NotFoundError); !ok {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+	err = b.ExpireStream("sales", 1)
+	if _, ok := err.(*store.NotFoundError); !ok {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+}
+
+func testGetStreams(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+
+	s, err := b.GetStreams([]string{"visits", "notastream", "sales"})
+	if err != nil {
+		t.Fatal("unexpected error in GetStreams:", err)
+	}
+	if len(s) != 3 {
+		t.Fatalf("expected %v streams, but got %v", 3, len(s))
+	}
+	if s[0] == nil || s[0].Config.Name != "visits" || s[0].Model == nil {
+		t.Errorf("expected visits with its model, but got %v", s[0])
+	}
+	if s[1] != nil {
+		t.Errorf("expected nil for a missing stream, but got %v", s[1])
+	}
+	if s[2] == nil || s[2].Config.Name != "sales" {
+		t.Errorf("expected sales, but got %v", s[2])
+	}
+}
+
+func testUpdateStreams(t *testing.T, open Opener) {
+	b := setUp(t, open)
+	defer closeStore(b)
+	sales, _ := b.GetStream("sales")
+	visits, _ := b.GetStream("visits")
+	stale, _ := b.GetStream("usage")
+	stale.Revision = 5
+	tm := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	sales.Update([]float64{1}, []time.Time{tm})
+	visits.Update([]float64{2}, []time.Time{tm})
+
+	errs, err := b.UpdateStreams([]*store.Update{
+		{Name: "sales", Stream: sales},
+		{Name: "notastream", Stream: sales},
+		{Name: "usage", Stream: stale},
+		{Name: "visits", Stream: visits},
+	})
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStreams:", err)
+	}
+	if errs[0] != nil || errs[3] != nil {
+		t.Errorf("expected sales and visits to update, but got errors %v and %v", errs[0], errs[3])
+	}
+	if _, ok := errs[1].(*store.NotFoundError); !ok {
+		t.Errorf("expected not found error, but got %v", errs[1])
+	}
+	if _, ok := errs[2].(*store.ConflictError); !ok {
+		t.Errorf("expected conflict error, but got %v", errs[2])
+	}
+
+	for _, name := range []string{"sales", "visits"} {
+		s, _ := b.GetStream(name)
+		if s.Revision != 1 || !s.Time.Equal(tm) {
+			t.Errorf("expected %v at revision 1 and time %v, but got %v and %v", name, tm, s.Revision, s.Time)
+		}
+	}
+	if sales.Revision != 1 {
+		t.Errorf("expected updated stream at revision %v, but it was %v", 1, sales.Revision)
+	}
+}
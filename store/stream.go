@@ -19,17 +19,109 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/cshenton/seer/stream"
 )
 
 // StreamStore defines the methods required to store and retrieve streams.
+// UpdateStream is a compare and swap, it only succeeds if the stored stream is
+// at the provided stream's revision, and on success increments the revision.
+//
+// If the stream has a retention policy, the events passed to UpdateStream are
+// added to its history in the same transaction, and events outside the policy
+// are discarded. GetEvents returns up to limit retained events in [from, to),
+// in time order, where a zero from or to is unbounded.
+//
+// If the stream has a snapshot policy, UpdateStream keeps the stored stream as
+// a snapshot when one is due. RollbackStream restores the model and last
+// event time of the latest snapshot at or before the provided time, keeping
+// the stream's current config, and discards later snapshots and events. It
+// fails with a PeriodError if the period has changed since the snapshot.
+//
+// GetStreams returns many streams from a single read of the store, in the
+// order of the provided names, with nil for names where no stream is stored.
+//
+// UpdateStreams applies many updates, as UpdateStream, in a single
+// transaction. An update that fails because its stream is missing or at
+// another revision is skipped, and its error returned at its position in
+// errs, while any other error fails every update.
+//
+// ListStreams returns the streams selected by the options, and the total
+// number of streams with the prefix and matching labels. Listed streams have
+// no model, as only their configuration, last event time and revision are
+// decoded. An empty list is not an error. Stores index stream labels, so that
+// selectors with =, in or exists requirements only decode matching streams.
+//
+// Backup calls fn with the record of every stream, in name order, from a
+// single consistent read of the store, and stops at the first error from fn.
+// RestoreStream saves a record, replacing any stream of the same name along
+// with its events and snapshots. GetRecord reads the record of one stream.
+//
+// RenameStream moves a stream, with its events and snapshots, to a new name,
+// and CloneStream copies one, each in a single transaction. Both fail if a
+// stream already exists at the new name. Clones start at revision 0.
+//
+// ExpireStream deletes a stream like DeleteStream, but only if it is still at
+// the provided revision, so a stream updated after it was found to be expired
+// is kept.
+//
+// Stores also hold the namespaces that streams may be created in.
 type StreamStore interface {
+	NamespaceStore
+
 	CreateStream(name string, s *stream.Stream) (err error)
 	GetStream(name string) (s *stream.Stream, err error)
+	GetStreams(names []string) (s []*stream.Stream, err error)
 	DeleteStream(name string) (err error)
-	ListStreams(pageNum, pageSize int) (s []*stream.Stream, err error)
-	UpdateStream(name string, s *stream.Stream) (err error)
+	ListStreams(opts *ListOptions) (s []*stream.Stream, total int, err error)
+	UpdateStream(name string, s *stream.Stream, events ...*stream.Event) (err error)
+	UpdateStreams(updates []*Update) (errs []error, err error)
+	GetEvents(name string, from, to time.Time, limit int) (e []*stream.Event, err error)
+	RollbackStream(name string, to time.Time) (s *stream.Stream, err error)
+	Backup(fn func(r *Record) error) (err error)
+	RestoreStream(r *Record) (err error)
+	GetRecord(name string) (r *Record, err error)
+	RenameStream(from, to string) (err error)
+	CloneStream(src, dst string) (err error)
+	ExpireStream(name string, revision uint64) (err error)
+}
+
+// Update is a stream to save with UpdateStreams, at its name, and the events to
+// retain with it.
+type Update struct {
+	Name   string
+	Stream *stream.Stream
+	Events []*stream.Event
+}
+
+// Skippable reports whether an update in a batch that failed with err can be
+// skipped, leaving the rest to be applied, as its stream is missing or at
+// another revision.
+func Skippable(err error) bool {
+	switch err.(type) {
+	case *NotFoundError, *ConflictError:
+		return true
+	}
+	return false
+}
+
+// Rollback returns the stream cur with the model and last event time of its
+// snapshot snap, at cur's next revision. The stream keeps cur's config, so
+// config changes made since the snapshot are kept, unless the period has
+// changed, as the snapshot's model can only be used at its own period.
+func Rollback(cur, snap *stream.Stream) (s *stream.Stream, err error) {
+	if snap.Config.Period != cur.Config.Period {
+		err = &PeriodError{Kind: "stream", Entity: cur.Config.Name, Period: cur.Config.Period, Snapshot: snap.Config.Period}
+		return nil, err
+	}
+	s = &stream.Stream{
+		Config:   cur.Config,
+		Model:    snap.Model,
+		Time:     snap.Time,
+		Revision: cur.Revision + 1,
+	}
+	return s, nil
 }
 
 // CreateStream creates a stream using the store on the current context, it returns an
@@ -43,18 +135,59 @@ func GetStream(c context.Context, name string) (s *stream.Stream, err error) {
 	return streamFromContext(c).GetStream(name)
 }
 
+// GetStreams returns the streams with the specific names, or nil where there
+// are none, using the current context store.
+func GetStreams(c context.Context, names []string) (s []*stream.Stream, err error) {
+	return streamFromContext(c).GetStreams(names)
+}
+
 // DeleteStream deletes the stream with the specific name using the current context store.
 func DeleteStream(c context.Context, name string) (err error) {
 	return streamFromContext(c).DeleteStream(name)
 }
 
-// ListStreams lists pages streams from the current context store.
-func ListStreams(c context.Context, pageNum, pageSize int) (s []*stream.Stream, err error) {
-	return streamFromContext(c).ListStreams(pageNum, pageSize)
+// ListStreams lists streams, without their models, from the current context
+// store.
+func ListStreams(c context.Context, opts *ListOptions) (s []*stream.Stream, total int, err error) {
+	return streamFromContext(c).ListStreams(opts)
+}
+
+// UpdateStream saves the provided stream, and any events to retain, and returns
+// an error if no stream with the given name exists, or if it has been updated
+// since the provided stream's revision.
+func UpdateStream(c context.Context, name string, s *stream.Stream, events ...*stream.Event) (err error) {
+	return streamFromContext(c).UpdateStream(name, s, events...)
+}
+
+// UpdateStreams applies many updates in a single transaction using the current
+// context store.
+func UpdateStreams(c context.Context, updates []*Update) (errs []error, err error) {
+	return streamFromContext(c).UpdateStreams(updates)
+}
+
+// GetEvents returns a stream's retained events using the current context store.
+func GetEvents(c context.Context, name string, from, to time.Time, limit int) (e []*stream.Event, err error) {
+	return streamFromContext(c).GetEvents(name, from, to, limit)
+}
+
+// RollbackStream restores a stream to its latest snapshot at or before the
+// provided time using the current context store.
+func RollbackStream(c context.Context, name string, to time.Time) (s *stream.Stream, err error) {
+	return streamFromContext(c).RollbackStream(name, to)
+}
+
+// RenameStream moves a stream to a new name using the current context store.
+func RenameStream(c context.Context, from, to string) (err error) {
+	return streamFromContext(c).RenameStream(from, to)
+}
+
+// CloneStream copies a stream to a new name using the current context store.
+func CloneStream(c context.Context, src, dst string) (err error) {
+	return streamFromContext(c).CloneStream(src, dst)
 }
 
-// UpdateStream saves the provided stream, and returns an error if no stream with
-// the given name exists.
-func UpdateStream(c context.Context, name string, s *stream.Stream) (err error) {
-	return streamFromContext(c).UpdateStream(name, s)
+// ExpireStream deletes the stream with the specific name, if it is still at
+// the revision, using the current context store.
+func ExpireStream(c context.Context, name string, revision uint64) (err error) {
+	return streamFromContext(c).ExpireStream(name, revision)
 }
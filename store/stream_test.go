@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cshenton/seer/store"
 	"github.com/cshenton/seer/store/bolt"
@@ -106,11 +107,127 @@ func TestUpdateStream(t *testing.T) {
 func TestListStreams(t *testing.T) {
 	c := setUp(t)
 
-	s, err := store.ListStreams(c, 1, 2)
+	s, total, err := store.ListStreams(c, &store.ListOptions{Limit: 2})
+	if err != nil {
+		t.Error("unexpected error in ListStreams:", err)
+	}
+	if len(s) != 2 || total != 3 {
+		t.Errorf("expected %v of %v streams, but got %v of %v", 2, 3, len(s), total)
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	c := setUp(t)
+	name := "sales"
+
+	s, _ := store.GetStream(c, name)
+	s.Config.Retention = &stream.Retention{}
+	times := []time.Time{time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.Update([]float64{1}, times)
+	events, _ := stream.Events([]float64{1}, times)
+
+	err := store.UpdateStream(c, name, s, events...)
+	if err != nil {
+		t.Fatal("unexpected error in UpdateStream:", err)
+	}
+	e, err := store.GetEvents(c, name, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		t.Error("unexpected error in GetEvents:", err)
+	}
+	if len(e) != 1 {
+		t.Errorf("expected %v events, but got %v", 1, len(e))
+	}
+}
+
+func TestRollbackStream(t *testing.T) {
+	c := setUp(t)
+	name := "sales"
+
+	_, err := store.RollbackStream(c, name, time.Now())
+	if err != nil {
+		t.Error("unexpected error in RollbackStream:", err)
+	}
+}
+
+func TestRenameStream(t *testing.T) {
+	c := setUp(t)
+
+	err := store.RenameStream(c, "sales", "revenue")
+	if err != nil {
+		t.Error("unexpected error in RenameStream:", err)
+	}
+	_, err = store.GetStream(c, "revenue")
+	if err != nil {
+		t.Error("unexpected error in GetStream:", err)
+	}
+}
+
+func TestCloneStream(t *testing.T) {
+	c := setUp(t)
+
+	err := store.CloneStream(c, "sales", "sales-copy")
+	if err != nil {
+		t.Error("unexpected error in CloneStream:", err)
+	}
+	_, err = store.GetStream(c, "sales-copy")
 	if err != nil {
 		t.Error("unexpected error in GetStream:", err)
 	}
+}
+
+func TestExpireStream(t *testing.T) {
+	c := setUp(t)
+
+	err := store.ExpireStream(c, "sales", 0)
+	if err != nil {
+		t.Error("unexpected error in ExpireStream:", err)
+	}
+	_, err = store.GetStream(c, "sales")
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func TestGetStreams(t *testing.T) {
+	c := setUp(t)
+
+	s, err := store.GetStreams(c, []string{"sales", "visits"})
+	if err != nil {
+		t.Error("unexpected error in GetStreams:", err)
+	}
 	if len(s) != 2 {
 		t.Errorf("expected %v streams, but got %v", 2, len(s))
 	}
 }
+
+func TestUpdateStreams(t *testing.T) {
+	c := setUp(t)
+	s, _ := store.GetStream(c, "sales")
+
+	errs, err := store.UpdateStreams(c, []*store.Update{{Name: "sales", Stream: s}})
+	if err != nil {
+		t.Error("unexpected error in UpdateStreams:", err)
+	}
+	if len(errs) != 1 || errs[0] != nil {
+		t.Errorf("expected the update to succeed, but got %v", errs)
+	}
+}
+
+func TestSkippable(t *testing.T) {
+	tt := []struct {
+		name string
+		err  error
+		skip bool
+	}{
+		{"not found", &store.NotFoundError{Kind: "stream", Entity: "sales"}, true},
+		{"conflict", &store.ConflictError{Kind: "stream", Entity: "sales"}, true},
+		{"corrupt", &store.CorruptDataError{Kind: "stream"}, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if store.Skippable(tc.err) != tc.skip {
+				t.Errorf("expected skippable %v, but it was %v", tc.skip, !tc.skip)
+			}
+		})
+	}
+}
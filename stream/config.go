@@ -50,13 +50,108 @@ func (d Domain) IsOpen() bool {
 	return d == Continuous
 }
 
-// Config stores static configuration about a stream.
+// Retention determines which of a stream's raw events are kept. At most Count
+// events are kept, and none older than Age before the stream's latest event. A
+// zero Count or Age is unbounded.
+type Retention struct {
+	Count int
+	Age   time.Duration
+}
+
+// NewRetention validates the provided retention policy and returns it.
+func NewRetention(count int, age time.Duration) (r *Retention, err error) {
+	if count < 0 {
+		err = errors.New(`retention count must not be negative`)
+		return nil, err
+	}
+	if age < 0 {
+		err = errors.New(`retention age must not be negative`)
+		return nil, err
+	}
+	r = &Retention{
+		Count: count,
+		Age:   age,
+	}
+	return r, nil
+}
+
+// Cutoff returns the time before which events are no longer retained, given
+// the latest event time, or the zero time if age is unbounded.
+func (r *Retention) Cutoff(latest time.Time) time.Time {
+	if r.Age == 0 {
+		return time.Time{}
+	}
+	return latest.Add(-r.Age)
+}
+
+// SnapshotPolicy determines which prior states of a stream are kept, so that
+// it can be rolled back. A state is kept if it is at least Interval, in stream
+// time, after the latest kept state, and at most Count states are kept.
+type SnapshotPolicy struct {
+	Count    int
+	Interval time.Duration
+}
+
+// NewSnapshotPolicy validates the provided snapshot policy and returns it.
+func NewSnapshotPolicy(count int, interval time.Duration) (p *SnapshotPolicy, err error) {
+	if count < 1 {
+		err = errors.New(`snapshot count must be 1 or more`)
+		return nil, err
+	}
+	if interval < 0 {
+		err = errors.New(`snapshot interval must not be negative`)
+		return nil, err
+	}
+	p = &SnapshotPolicy{
+		Count:    count,
+		Interval: interval,
+	}
+	return p, nil
+}
+
+// Due returns whether a state at time t should be kept, given the time of the
+// latest kept state, or the zero time if there is none. States of streams
+// which have seen no events are never kept.
+func (p *SnapshotPolicy) Due(t, latest time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	return latest.IsZero() || !t.Before(latest.Add(p.Interval))
+}
+
+// Config stores static configuration about a stream. Raw events are only kept
+// if the stream has a Retention policy, and prior states only if it has a
+// Snapshots policy. Labels are arbitrary key value pairs, such as service or
+// team, which streams can be selected by. A stream with a positive TTL expires
+// once its latest event is more than TTL old.
 type Config struct {
-	Name   string
-	Period float64
-	Min    float64
-	Max    float64
-	Domain Domain
+	Name      string
+	Period    float64
+	Min       float64
+	Max       float64
+	Domain    Domain
+	Retention *Retention
+	Snapshots *SnapshotPolicy
+	Labels    map[string]string
+	TTL       time.Duration
+}
+
+// ValidateName returns an error if name is not a valid stream name.
+func ValidateName(name string) (err error) {
+	if len(name) < 3 {
+		err = errors.New(`name must be three characters or longer`)
+		return err
+	}
+	return nil
+}
+
+// ValidateTTL returns an error if ttl is not a valid stream TTL.
+func ValidateTTL(ttl time.Duration) (err error) {
+	if ttl < 0 {
+		err = errors.New(`ttl must not be negative`)
+		return err
+	}
+	return nil
 }
 
 // NewConfig validates the provided configuration data and returns a Config.
@@ -67,8 +162,8 @@ func NewConfig(name string, period, min, max float64, domain int) (c *Config, er
 		return nil, err
 	}
 
-	if len(name) < 3 {
-		err = errors.New(`name must be three characters or longer`)
+	err = ValidateName(name)
+	if err != nil {
 		return nil, err
 	}
 
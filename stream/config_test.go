@@ -19,6 +19,7 @@ package stream_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/chulabs/seer/stream"
 )
@@ -137,3 +138,134 @@ func TestNewConfigErrs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateName(t *testing.T) {
+	tt := []struct {
+		name  string
+		valid bool
+	}{
+		{"sales", true},
+		{"abc", true},
+		{"ab", false},
+		{"", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := stream.ValidateName(tc.name)
+			if (err == nil) != tc.valid {
+				t.Errorf("expected valid %v, but got error %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateTTL(t *testing.T) {
+	if err := stream.ValidateTTL(time.Hour); err != nil {
+		t.Error("unexpected error in ValidateTTL:", err)
+	}
+	if err := stream.ValidateTTL(-time.Hour); err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
+
+func TestNewRetention(t *testing.T) {
+	r, err := stream.NewRetention(10, time.Hour)
+	if err != nil {
+		t.Fatal("unexpected error in NewRetention:", err)
+	}
+	if r.Count != 10 || r.Age != time.Hour {
+		t.Errorf("expected count %v and age %v, but got %v and %v", 10, time.Hour, r.Count, r.Age)
+	}
+}
+
+func TestNewRetentionErrs(t *testing.T) {
+	tt := []struct {
+		name  string
+		count int
+		age   time.Duration
+	}{
+		{"negative count", -1, 0},
+		{"negative age", 0, -time.Hour},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := stream.NewRetention(tc.count, tc.age)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	latest := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+	tt := []struct {
+		name   string
+		age    time.Duration
+		cutoff time.Time
+	}{
+		{"unbounded", 0, time.Time{}},
+		{"day", 24 * time.Hour, time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &stream.Retention{Age: tc.age}
+			if !r.Cutoff(latest).Equal(tc.cutoff) {
+				t.Errorf("expected cutoff %v, but got %v", tc.cutoff, r.Cutoff(latest))
+			}
+		})
+	}
+}
+
+func TestNewSnapshotPolicy(t *testing.T) {
+	p, err := stream.NewSnapshotPolicy(24, time.Hour)
+	if err != nil {
+		t.Fatal("unexpected error in NewSnapshotPolicy:", err)
+	}
+	if p.Count != 24 || p.Interval != time.Hour {
+		t.Errorf("expected count %v and interval %v, but got %v and %v", 24, time.Hour, p.Count, p.Interval)
+	}
+}
+
+func TestNewSnapshotPolicyErrs(t *testing.T) {
+	tt := []struct {
+		name     string
+		count    int
+		interval time.Duration
+	}{
+		{"zero count", 0, time.Hour},
+		{"negative interval", 1, -time.Hour},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := stream.NewSnapshotPolicy(tc.count, tc.interval)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestSnapshotPolicyDue(t *testing.T) {
+	t0 := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &stream.SnapshotPolicy{Count: 2, Interval: time.Hour}
+
+	tt := []struct {
+		name   string
+		t      time.Time
+		latest time.Time
+		due    bool
+	}{
+		{"no events", time.Time{}, time.Time{}, false},
+		{"first", t0, time.Time{}, true},
+		{"too soon", t0.Add(30 * time.Minute), t0, false},
+		{"interval", t0.Add(time.Hour), t0, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if p.Due(tc.t, tc.latest) != tc.due {
+				t.Errorf("expected due to be %v, but it was %v", tc.due, !tc.due)
+			}
+		})
+	}
+}
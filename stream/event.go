@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stream
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is a single observed value in a stream.
+type Event struct {
+	Time  time.Time
+	Value float64
+}
+
+// Events pairs the provided values and times into events, or returns an error
+// if there are an incorrect number of corresponding values.
+func Events(vals []float64, times []time.Time) (e []*Event, err error) {
+	if len(vals) != len(times) {
+		err = fmt.Errorf("vals, times should be equal length, but were %v and %v", len(vals), len(times))
+		return nil, err
+	}
+	e = make([]*Event, len(vals))
+	for i := range vals {
+		e[i] = &Event{Time: times[i], Value: vals[i]}
+	}
+	return e, nil
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2018 The Seer Authors. All rights reserved.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stream_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cshenton/seer/stream"
+)
+
+func TestEvents(t *testing.T) {
+	vals := []float64{1, 2}
+	times := []time.Time{time.Unix(0, 0), time.Unix(3600, 0)}
+
+	e, err := stream.Events(vals, times)
+	if err != nil {
+		t.Fatal("unexpected error in Events:", err)
+	}
+	for i := range e {
+		if e[i].Value != vals[i] || !e[i].Time.Equal(times[i]) {
+			t.Errorf("expected event %v, %v, but got %v", times[i], vals[i], e[i])
+		}
+	}
+}
+
+func TestEventsErrs(t *testing.T) {
+	_, err := stream.Events([]float64{1, 2}, []time.Time{time.Unix(0, 0)})
+	if err == nil {
+		t.Error("expected error, but it was nil")
+	}
+}
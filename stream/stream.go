@@ -20,6 +20,7 @@ package stream
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/cshenton/seer/dist/uv"
@@ -27,10 +28,13 @@ import (
 )
 
 // Stream represents a time series data stream that can learn and forecast.
+// Its Revision is incremented by the store on every update, so that
+// concurrent updates can be detected.
 type Stream struct {
-	Config *Config
-	Model  *model.Model
-	Time   time.Time
+	Config   *Config
+	Model    *model.Model
+	Time     time.Time
+	Revision uint64
 }
 
 // New constructs a stream given the required data.
@@ -39,11 +43,57 @@ func New(name string, period, min, max float64, domain int) (s *Stream, err erro
 	if err != nil {
 		return nil, err
 	}
-	s = &Stream{
+	return NewWithConfig(conf), nil
+}
+
+// NewWithConfig constructs a stream, with a fresh model, from a validated
+// config.
+func NewWithConfig(conf *Config) (s *Stream) {
+	return &Stream{
 		Config: conf,
 		Model:  model.New(conf.Period),
 	}
-	return s, nil
+}
+
+// Expired returns whether the stream's latest event is more than its TTL
+// before now. Streams without a TTL, or which have seen no events, never
+// expire.
+func (s *Stream) Expired(now time.Time) bool {
+	if s.Config.TTL <= 0 || s.Time.IsZero() {
+		return false
+	}
+	return now.Sub(s.Time) > s.Config.TTL
+}
+
+// Migration is how a stream's model is changed to suit a new config.
+type Migration int
+
+// Valid values for Migration. These MUST match with the enum defined in the
+// protocol buffer.
+const (
+	InPlace       Migration = 0
+	Transformed   Migration = 1
+	Reinitialised Migration = 2
+)
+
+// Reconfigure replaces the stream's config with the provided one, which must
+// be valid, and returns how its model was changed to suit it. A new period
+// alters the model's state space, so its state is transformed to the new
+// period, or if the stream has seen no events, a fresh model is used. Other
+// changes are applied in place.
+func (s *Stream) Reconfigure(conf *Config) (m Migration) {
+	old := s.Config.Period
+	s.Config = conf
+	switch {
+	case conf.Period == old:
+		return InPlace
+	case s.Time.IsZero():
+		s.Model = model.New(conf.Period)
+		return Reinitialised
+	default:
+		s.Model = s.Model.Transform(old, conf.Period)
+		return Transformed
+	}
 }
 
 // Update updates the provided sequence of values against the stream model. It
@@ -77,6 +127,81 @@ func (s *Stream) Update(vals []float64, times []time.Time) (err error) {
 	return nil
 }
 
+// Replay updates the stream with the events, in batches of at most size
+// events, calling progress with the number of events applied after each batch.
+// It stops at the first error from an update or from progress.
+func (s *Stream) Replay(events []*Event, size int, progress func(n int) error) (err error) {
+	if size <= 0 {
+		err = errors.New("size must be greater than 0")
+		return err
+	}
+	for i := 0; i < len(events); i += size {
+		end := i + size
+		if end > len(events) {
+			end = len(events)
+		}
+		vals := make([]float64, end-i)
+		times := make([]time.Time, end-i)
+		for j, e := range events[i:end] {
+			vals[j] = e.Value
+			times[j] = e.Time
+		}
+
+		err = s.Update(vals, times)
+		if err != nil {
+			return err
+		}
+		err = progress(end)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Refit learns the stream model's process variances from the provided history,
+// which must be in sequence, given its current random walk variance, and
+// applies them to subsequent updates. It does not alter the current model
+// state.
+func (s *Stream) Refit(vals []float64, times []time.Time) (err error) {
+	if len(vals) != len(times) {
+		err = fmt.Errorf("vals, times should be equal length, but were %v and %v", len(vals), len(times))
+		return err
+	}
+	for i := 1; i < len(times); i++ {
+		t := times[i-1].Add(s.Config.Duration())
+		if times[i] != t {
+			err = fmt.Errorf("expected time %v at position %v, but got %v", t, i, times[i])
+			return err
+		}
+	}
+
+	p, err := model.Fit(s.Config.Period, s.Model.RCE.Walk(), vals)
+	if err != nil {
+		return err
+	}
+	s.Model.Deterministic.Params = p
+	return nil
+}
+
+// maxStudentDof is the largest number of degrees of freedom for which forecasts
+// are Student's t, beyond it the normal is indistinguishable.
+const maxStudentDof = 100
+
+// predictive returns a Student's t with the moments of n while the variance
+// posterior is diffuse, and n itself once it has concentrated or if it is
+// still improper.
+func predictive(n *uv.Normal, dof float64) uv.Quantiler {
+	if dof > maxStudentDof {
+		return n
+	}
+	st, err := ToStudentT(n, dof)
+	if err != nil {
+		return n
+	}
+	return st
+}
+
 // Interval is a forecast confidence interval.
 type Interval struct {
 	Probability float64
@@ -84,25 +209,93 @@ type Interval struct {
 	UpperBound  []float64
 }
 
+// Aggregation determines how forecasts are aggregated over a window of periods.
+type Aggregation int
+
+// Valid values for Aggregation. These MUST match with the enum defined in the
+// protocol buffer.
+const (
+	None Aggregation = 0
+	Sum  Aggregation = 1
+	Mean Aggregation = 2
+)
+
 // Forecast forecasts against the model and transforms the result to the appropriate domain.
 func (s *Stream) Forecast(n int, probs []float64) (t []time.Time, v []float64, in []*Interval, err error) {
+	return s.ForecastAggregate(n, None, 1, probs)
+}
+
+// ValidateForecast returns an error if the arguments are not valid for
+// ForecastAggregate, on any stream.
+func ValidateForecast(n int, agg Aggregation, window int, probs []float64) (err error) {
 	if n <= 0 {
 		err = errors.New("n must be greater than 0")
-		return t, v, in, err
+		return err
+	}
+	if window <= 0 {
+		err = errors.New("window must be greater than 0")
+		return err
 	}
 	for i := range probs {
 		if probs[i] < 0 || probs[i] > 1 {
 			err = fmt.Errorf("probs must be in [0,1], but was %v at position %v", probs[i], i)
-			return t, v, in, err
+			return err
+		}
+	}
+	switch agg {
+	case None:
+		if window != 1 {
+			err = fmt.Errorf("window must be 1 without aggregation, but was %v", window)
+			return err
+		}
+	case Sum, Mean:
+	default:
+		err = fmt.Errorf("aggregation must be one of %v, %v or %v, but was %v", None, Sum, Mean, agg)
+		return err
+	}
+	return nil
+}
+
+// ForecastAggregate forecasts n consecutive windows of periods, with the
+// values in each window aggregated as specified, and transforms the result to
+// the appropriate domain. Windows must be a single period without aggregation,
+// and the returned times are those of the last period in each window.
+func (s *Stream) ForecastAggregate(n int, agg Aggregation, window int, probs []float64) (t []time.Time, v []float64, in []*Interval, err error) {
+	return s.ForecastAggregateFrom(0, n, agg, window, probs)
+}
+
+// ForecastAggregateFrom is ForecastAggregate, with the first window starting
+// after skip unforecast periods.
+func (s *Stream) ForecastAggregateFrom(skip, n int, agg Aggregation, window int, probs []float64) (t []time.Time, v []float64, in []*Interval, err error) {
+	err = ValidateForecast(n, agg, window, probs)
+	if err != nil {
+		return t, v, in, err
+	}
+	if skip < 0 {
+		err = errors.New("skip must not be negative")
+		return t, v, in, err
+	}
+
+	var f []*uv.Normal
+	switch agg {
+	case None:
+		f = s.Model.ForecastFrom(s.Config.Period, skip, n)
+	case Sum:
+		f = s.Model.ForecastSumFrom(s.Config.Period, skip, n, window)
+	case Mean:
+		f = s.Model.ForecastSumFrom(s.Config.Period, skip, n, window)
+		for i := range f {
+			f[i].Location /= float64(window)
+			f[i].Scale /= float64(window)
 		}
 	}
-	f := s.Model.Forecast(s.Config.Period, n)
 	q := make([]uv.Quantiler, n)
+	dof := s.Model.RCE.Dof()
 
 	switch s.Config.Domain {
 	case Continuous:
 		for i := range q {
-			q[i] = f[i]
+			q[i] = predictive(f[i], dof)
 		}
 	case ContinuousRight:
 		for i := range q {
@@ -111,7 +304,7 @@ func (s *Stream) Forecast(n int, probs []float64) (t []time.Time, v []float64, i
 	case ContinuousInterval:
 		// not implemented
 		for i := range q {
-			q[i] = f[i]
+			q[i] = predictive(f[i], dof)
 		}
 	case DiscreteRight:
 		// not implemented
@@ -121,7 +314,7 @@ func (s *Stream) Forecast(n int, probs []float64) (t []time.Time, v []float64, i
 	case DiscreteInterval:
 		// not implemented
 		for i := range q {
-			q[i] = f[i]
+			q[i] = predictive(f[i], dof)
 		}
 	}
 
@@ -136,9 +329,10 @@ func (s *Stream) Forecast(n int, probs []float64) (t []time.Time, v []float64, i
 		}
 	}
 
-	prev := s.Time
+	prev := s.Time.Add(time.Duration(skip) * s.Config.Duration())
+	step := time.Duration(window) * s.Config.Duration()
 	for i := range t {
-		next := prev.Add(s.Config.Duration())
+		next := prev.Add(step)
 
 		t[i] = next
 		v[i], _ = q[i].Quantile(0.5)
@@ -153,3 +347,68 @@ func (s *Stream) Forecast(n int, probs []float64) (t []time.Time, v []float64, i
 	}
 	return t, v, in, nil
 }
+
+// Skip returns the number of periods a forecast must pass over to start with
+// the first period at or after start. It is zero for any start at or before
+// the stream's next period.
+func (s *Stream) Skip(start time.Time) (skip int) {
+	d := start.Sub(s.Time)
+	p := s.Config.Duration()
+	if d <= p {
+		return 0
+	}
+	return int((d+p-1)/p) - 1
+}
+
+// Windows returns the number of whole windows of periods, after skipping skip
+// periods, which end at or before end.
+func (s *Stream) Windows(skip, window int, end time.Time) (n int) {
+	if window <= 0 {
+		return 0
+	}
+	periods := int(end.Sub(s.Time)/s.Config.Duration()) - skip
+	if periods <= 0 {
+		return 0
+	}
+	return periods / window
+}
+
+// Sample draws sample paths of length n from the forecast distribution, using
+// the provided seed. Paths are transformed to the stream's domain by mapping
+// each step through the quantiles of its forecast marginal, which preserves
+// the correlation between steps.
+func (s *Stream) Sample(n, paths int, seed int64) (t []time.Time, p [][]float64, err error) {
+	if n <= 0 {
+		err = errors.New("n must be greater than 0")
+		return t, p, err
+	}
+	if paths <= 0 {
+		err = errors.New("paths must be greater than 0")
+		return t, p, err
+	}
+	p, err = s.Model.Sample(s.Config.Period, n, paths, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.Config.Domain.IsRight() {
+		f := s.Model.Forecast(s.Config.Period, n)
+		for j := range f {
+			ln, err := ToLogNormal(f[j])
+			if err != nil {
+				continue
+			}
+			for i := range p {
+				p[i][j], _ = ln.Quantile(f[j].CDF(p[i][j]))
+			}
+		}
+	}
+
+	t = make([]time.Time, n)
+	prev := s.Time
+	for i := range t {
+		t[i] = prev.Add(s.Config.Duration())
+		prev = t[i]
+	}
+	return t, p, nil
+}
@@ -18,9 +18,12 @@
 package stream_test
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/cshenton/seer/dist/uv"
 	"github.com/cshenton/seer/stream"
 )
 
@@ -60,7 +63,7 @@ func TestStreamUpdate(t *testing.T) {
 		t.Fatal("unexpected error in Update:", err)
 	}
 
-	if s.Model.RCE.History[0] == 0 {
+	if s.Model.RCE.Variances[0].Shape == 0 {
 		t.Error("model was not updated")
 	}
 }
@@ -155,6 +158,194 @@ func TestStreamForecast(t *testing.T) {
 	}
 }
 
+func TestStreamForecastStudentT(t *testing.T) {
+	s, _ := stream.New("stream", 86400, 0, 0, 0)
+	n := 10
+	vals := make([]float64, n)
+	times := make([]time.Time, n)
+	for i := range vals {
+		vals[i] = float64(i % 3)
+		times[i] = time.Date(2016, 1, 1+i, 0, 0, 0, 0, time.UTC)
+	}
+	s.Update(vals, times)
+
+	f := s.Model.Forecast(s.Config.Period, 1)
+	nl, nu, _ := uv.ConfidenceInterval(f[0], 0.99)
+
+	_, _, in, err := s.Forecast(1, []float64{0.99})
+	if err != nil {
+		t.Fatal("unexpected error in Forecast,", err)
+	}
+	if in[0].LowerBound[0] >= nl || in[0].UpperBound[0] <= nu {
+		t.Errorf(
+			"expected interval wider than normal (%v, %v), but it was (%v, %v)",
+			nl, nu, in[0].LowerBound[0], in[0].UpperBound[0],
+		)
+	}
+}
+
+func TestStreamForecastAggregate(t *testing.T) {
+	tt := []struct {
+		name   string
+		agg    stream.Aggregation
+		window int
+		domain int
+	}{
+		{"none", stream.None, 1, 0},
+		{"sum", stream.Sum, 24, 0},
+		{"mean", stream.Mean, 24, 0},
+		{"sum, right continuous", stream.Sum, 24, 1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("stream", 3600, 0, 0, tc.domain)
+			start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+			s.Update([]float64{1}, []time.Time{start})
+
+			tm, v, in, err := s.ForecastAggregate(3, tc.agg, tc.window, []float64{0.9})
+			if err != nil {
+				t.Fatal("unexpected error in ForecastAggregate,", err)
+			}
+			if len(tm) != 3 || len(v) != 3 || len(in[0].LowerBound) != 3 {
+				t.Fatalf("expected %v times, values and bounds", 3)
+			}
+			end := start.Add(time.Duration(3*tc.window) * time.Hour)
+			if !tm[2].Equal(end) {
+				t.Errorf("expected last window to end at %v, but got %v", end, tm[2])
+			}
+		})
+	}
+}
+
+func TestStreamForecastAggregateMean(t *testing.T) {
+	s, _ := stream.New("stream", 3600, 0, 0, 0)
+	s.Update([]float64{1}, []time.Time{time.Now()})
+
+	_, sum, sumIn, _ := s.ForecastAggregate(2, stream.Sum, 4, []float64{0.9})
+	_, mean, meanIn, _ := s.ForecastAggregate(2, stream.Mean, 4, []float64{0.9})
+	for i := range sum {
+		if math.Abs(sum[i]/4-mean[i]) > 1e-6*math.Abs(sum[i]) {
+			t.Errorf("expected mean %v, but got %v", sum[i]/4, mean[i])
+		}
+		width := sumIn[0].UpperBound[i] - sumIn[0].LowerBound[i]
+		meanWidth := meanIn[0].UpperBound[i] - meanIn[0].LowerBound[i]
+		if math.Abs(width/4-meanWidth) > 1e-6*width {
+			t.Errorf("expected mean interval width %v, but got %v", width/4, meanWidth)
+		}
+	}
+}
+
+func TestStreamForecastAggregateErrs(t *testing.T) {
+	tt := []struct {
+		name   string
+		agg    stream.Aggregation
+		window int
+	}{
+		{"zero window", stream.Sum, 0},
+		{"negative window", stream.Mean, -1},
+		{"window without aggregation", stream.None, 2},
+		{"unknown aggregation", stream.Aggregation(7), 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("stream", 3600, 0, 0, 0)
+			s.Update([]float64{1}, []time.Time{time.Now()})
+
+			_, _, _, err := s.ForecastAggregate(10, tc.agg, tc.window, []float64{0.9})
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestStreamForecastAggregateFrom(t *testing.T) {
+	s, _ := stream.New("stream", 3600, 0, 0, 0)
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Update([]float64{1}, []time.Time{start})
+
+	tm, v, in, _ := s.ForecastAggregate(30, stream.None, 1, []float64{0.9})
+	ftm, fv, fin, err := s.ForecastAggregateFrom(20, 10, stream.None, 1, []float64{0.9})
+	if err != nil {
+		t.Fatal("unexpected error in ForecastAggregateFrom,", err)
+	}
+	for i := range ftm {
+		if !ftm[i].Equal(tm[i+20]) {
+			t.Errorf("expected time %v at %v, but got %v", tm[i+20], i, ftm[i])
+		}
+		if math.Abs(fv[i]-v[i+20]) > 1e-6 {
+			t.Errorf("expected value %v at %v, but got %v", v[i+20], i, fv[i])
+		}
+		if math.Abs(fin[0].UpperBound[i]-in[0].UpperBound[i+20]) > 1e-6 {
+			t.Errorf("expected upper bound %v at %v, but got %v", in[0].UpperBound[i+20], i, fin[0].UpperBound[i])
+		}
+	}
+
+	_, _, _, err = s.ForecastAggregateFrom(-1, 10, stream.None, 1, []float64{0.9})
+	if err == nil {
+		t.Error("expected error for negative skip, but it was nil")
+	}
+}
+
+func TestStreamSkipWindows(t *testing.T) {
+	s, _ := stream.New("stream", 3600, 0, 0, 0)
+	last := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Update([]float64{1}, []time.Time{last})
+
+	tt := []struct {
+		name   string
+		start  time.Time
+		end    time.Time
+		window int
+		skip   int
+		n      int
+	}{
+		{"before last event", last.Add(-time.Hour), last.Add(3 * time.Hour), 1, 0, 3},
+		{"next period", last.Add(time.Hour), last.Add(3 * time.Hour), 1, 0, 3},
+		{"aligned", last.Add(9 * time.Hour), last.Add(17 * time.Hour), 1, 8, 9},
+		{"unaligned", last.Add(8*time.Hour + time.Minute), last.Add(17*time.Hour + time.Minute), 1, 8, 9},
+		{"windows", last.Add(9 * time.Hour), last.Add(17 * time.Hour), 4, 8, 2},
+		{"end before start", last.Add(9 * time.Hour), last.Add(5 * time.Hour), 1, 8, 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			skip := s.Skip(tc.start)
+			if skip != tc.skip {
+				t.Errorf("expected skip %v, but got %v", tc.skip, skip)
+			}
+			n := s.Windows(skip, tc.window, tc.end)
+			if n != tc.n {
+				t.Errorf("expected %v windows, but got %v", tc.n, n)
+			}
+		})
+	}
+}
+
+func TestValidateForecast(t *testing.T) {
+	tt := []struct {
+		name   string
+		n      int
+		agg    stream.Aggregation
+		window int
+		probs  []float64
+		valid  bool
+	}{
+		{"forecast", 10, stream.None, 1, []float64{0.9}, true},
+		{"sum", 10, stream.Sum, 24, nil, true},
+		{"zero length", 0, stream.None, 1, nil, false},
+		{"bad probs", 10, stream.Mean, 4, []float64{-0.1}, false},
+		{"window without aggregation", 10, stream.None, 4, nil, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := stream.ValidateForecast(tc.n, tc.agg, tc.window, tc.probs)
+			if (err == nil) != tc.valid {
+				t.Errorf("expected valid %v, but got error %v", tc.valid, err)
+			}
+		})
+	}
+}
+
 func TestStreamForecastErrs(t *testing.T) {
 	tt := []struct {
 		name  string
@@ -177,3 +368,261 @@ func TestStreamForecastErrs(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamReplay(t *testing.T) {
+	n := 25
+	vals := make([]float64, n)
+	times := make([]time.Time, n)
+	for i := range vals {
+		vals[i] = float64(i % 7)
+		times[i] = time.Date(2016, 1, 1+i, 0, 0, 0, 0, time.UTC)
+	}
+	events, _ := stream.Events(vals, times)
+
+	s, _ := stream.New("streamy", 86400, 0, 0, 0)
+	var done []int
+	err := s.Replay(events, 10, func(n int) error {
+		done = append(done, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("unexpected error in Replay:", err)
+	}
+	if len(done) != 3 || done[2] != n {
+		t.Errorf("expected progress of %v, but got %v", []int{10, 20, 25}, done)
+	}
+
+	// Replaying in batches matches a single update.
+	u, _ := stream.New("streamy", 86400, 0, 0, 0)
+	u.Update(vals, times)
+	_, want, _, _ := u.Forecast(3, nil)
+	_, have, _, _ := s.Forecast(3, nil)
+	for i := range want {
+		if want[i] != have[i] {
+			t.Errorf("expected forecast %v, but got %v", want, have)
+		}
+	}
+}
+
+func TestStreamReplayErrs(t *testing.T) {
+	t0 := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	seq, _ := stream.Events([]float64{1, 2}, []time.Time{t0, t0.Add(24 * time.Hour)})
+	gap, _ := stream.Events([]float64{1, 2}, []time.Time{t0, t0.Add(48 * time.Hour)})
+	stop := errors.New("stop")
+
+	tt := []struct {
+		name     string
+		events   []*stream.Event
+		size     int
+		progress error
+	}{
+		{"zero size", seq, 0, nil},
+		{"out of sequence", gap, 1, nil},
+		{"progress error", seq, 1, stop},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("streamy", 86400, 0, 0, 0)
+			err := s.Replay(tc.events, tc.size, func(n int) error { return tc.progress })
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestStreamRefit(t *testing.T) {
+	s, err := stream.New("streamy", 86400, 0, 0, 0)
+	if err != nil {
+		t.Fatal("unexpected error in New:", err)
+	}
+
+	n := 20
+	vals := make([]float64, n)
+	times := make([]time.Time, n)
+	for i := range vals {
+		vals[i] = float64(i % 7)
+		times[i] = time.Date(2016, 1, 1+i, 0, 0, 0, 0, time.UTC)
+	}
+
+	err = s.Refit(vals, times)
+	if err != nil {
+		t.Fatal("unexpected error in Refit:", err)
+	}
+	if s.Model.Deterministic.Params == nil {
+		t.Error("expected fitted params, but they were nil")
+	}
+}
+
+func TestStreamRefitErrs(t *testing.T) {
+	tt := []struct {
+		name   string
+		times  []time.Time
+		values []float64
+	}{
+		{"mismatched lengths", []time.Time{time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)}, []float64{1, 2}},
+		{
+			"wrong intermediate time",
+			[]time.Time{
+				time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2016, 1, 4, 0, 0, 0, 0, time.UTC),
+			},
+			[]float64{2, 1},
+		},
+		{"too few values", []time.Time{time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)}, []float64{1}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := stream.New("streamy", 86400, 0, 0, 0)
+			if err != nil {
+				t.Fatal("unexpected error in New:", err)
+			}
+			err = s.Refit(tc.values, tc.times)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestStreamSample(t *testing.T) {
+	tt := []struct {
+		name   string
+		n      int
+		paths  int
+		domain int
+	}{
+		{"single step, single path", 1, 1, 0},
+		{"multiple steps, multiple paths", 10, 50, 0},
+		{"multiple steps, multiple paths, right continuous", 10, 50, 1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("stream", 3600, 0, 0, tc.domain)
+			s.Update([]float64{1}, []time.Time{time.Now()})
+
+			tm, p, err := s.Sample(tc.n, tc.paths, 1)
+			if err != nil {
+				t.Fatal("unexpected error in Sample,", err)
+			}
+			if len(tm) != tc.n {
+				t.Errorf("expected %v times, but there were %v", tc.n, len(tm))
+			}
+			if len(p) != tc.paths {
+				t.Fatalf("expected %v paths, but there were %v", tc.paths, len(p))
+			}
+			for i := range p {
+				if len(p[i]) != tc.n {
+					t.Fatalf("expected %v values in path %v, but there were %v", tc.n, i, len(p[i]))
+				}
+				for j := range p[i] {
+					if tc.domain == 1 && p[i][j] < 0 {
+						t.Errorf("expected positive values, but got %v", p[i][j])
+					}
+				}
+			}
+
+			_, again, _ := s.Sample(tc.n, tc.paths, 1)
+			if again[0][0] != p[0][0] {
+				t.Error("expected the same paths from the same seed")
+			}
+		})
+	}
+}
+
+func TestStreamSampleErrs(t *testing.T) {
+	tt := []struct {
+		name  string
+		n     int
+		paths int
+	}{
+		{"zero length", 0, 10},
+		{"negative length", -3, 10},
+		{"zero paths", 10, 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("stream", 3600, 0, 0, 0)
+			s.Update([]float64{1}, []time.Time{time.Now()})
+
+			_, _, err := s.Sample(tc.n, tc.paths, 1)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+		})
+	}
+}
+
+func TestStreamReconfigure(t *testing.T) {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name      string
+		events    bool
+		period    float64
+		migration stream.Migration
+	}{
+		{"same period", true, 3600, stream.InPlace},
+		{"new period", true, 86400, stream.Transformed},
+		{"new period without events", false, 86400, stream.Reinitialised},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("sales", 3600, 0, 0, 0)
+			if tc.events {
+				err := s.Update([]float64{1, 2}, []time.Time{start, start.Add(time.Hour)})
+				if err != nil {
+					t.Fatal("unexpected error in Update:", err)
+				}
+			}
+			level := s.Model.Deterministic.Location[0]
+
+			conf, _ := stream.NewConfig("sales", tc.period, 0, 10, 1)
+			m := s.Reconfigure(conf)
+			if m != tc.migration {
+				t.Errorf("expected migration %v, but it was %v", tc.migration, m)
+			}
+			if s.Config != conf {
+				t.Error("expected config to be replaced, but it was", s.Config)
+			}
+			if s.Model.Deterministic.Location[0] != level {
+				t.Errorf("expected level %v, but it was %v", level, s.Model.Deterministic.Location[0])
+			}
+
+			// The next event is expected a new period after the last.
+			if tc.events {
+				next := s.Time.Add(conf.Duration())
+				err := s.Update([]float64{3}, []time.Time{next})
+				if err != nil {
+					t.Error("unexpected error in Update:", err)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamExpired(t *testing.T) {
+	now := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		name    string
+		ttl     time.Duration
+		last    time.Time
+		expired bool
+	}{
+		{"idle", time.Hour, now.Add(-2 * time.Hour), true},
+		{"active", time.Hour, now.Add(-time.Minute), false},
+		{"no ttl", 0, now.Add(-48 * time.Hour), false},
+		{"no events", time.Hour, time.Time{}, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := stream.New("sales", 3600, 0, 0, 0)
+			s.Config.TTL = tc.ttl
+			s.Time = tc.last
+			if s.Expired(now) != tc.expired {
+				t.Errorf("expected expired %v, but it was %v", tc.expired, s.Expired(now))
+			}
+		})
+	}
+}
@@ -37,3 +37,18 @@ func ToLogNormal(n *uv.Normal) (ln *uv.LogNormal, err error) {
 	ln, _ = uv.NewLogNormal(loc, scale)
 	return ln, nil
 }
+
+// ToStudentT returns a Student's t distribution with the given degrees of
+// freedom and the same first and second moments as the input normal
+// distribution.
+func ToStudentT(n *uv.Normal, dof float64) (st *uv.StudentT, err error) {
+	if dof <= 2 {
+		err := errors.New("Must have more than 2 degrees of freedom to transform to student t")
+		return nil, err
+	}
+	st, err = uv.NewStudentT(n.Location, n.Scale*math.Sqrt((dof-2)/dof), dof)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
@@ -19,8 +19,11 @@ package stream_test
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 
+	"github.com/cshenton/seer/model"
+
 	"github.com/cshenton/seer/stream"
 
 	"github.com/cshenton/seer/dist/uv"
@@ -79,3 +82,108 @@ func TestToLogNormalErrs(t *testing.T) {
 		})
 	}
 }
+
+func TestToStudentT(t *testing.T) {
+	tt := []struct {
+		name  string
+		loc   float64
+		scale float64
+		dof   float64
+	}{
+		{"few dof", 1, 2, 3},
+		{"many dof", -5, 0.5, 1000},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			n, err := uv.NewNormal(tc.loc, tc.scale)
+			if err != nil {
+				t.Fatal("unexpected error while creating Normal,", err)
+			}
+			st, err := stream.ToStudentT(n, tc.dof)
+			if err != nil {
+				t.Fatal("unexpected error in ToStudentT,", err)
+			}
+			if st.Mean() != n.Mean() {
+				t.Errorf("expected mean %v, but got %v", n.Mean(), st.Mean())
+			}
+			if math.Abs(st.Variance()-n.Variance()) > 1e-8 {
+				t.Errorf("expected variance %v, but got %v", n.Variance(), st.Variance())
+			}
+		})
+	}
+}
+
+func TestToStudentTErrs(t *testing.T) {
+	tt := []struct {
+		name string
+		dof  float64
+	}{
+		{"zero dof", 0},
+		{"two dof", 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			n, _ := uv.NewNormal(0, 1)
+			st, err := stream.ToStudentT(n, tc.dof)
+			if err == nil {
+				t.Error("expected error, but it was nil")
+			}
+			if st != nil {
+				t.Error("expected nil pointer, but got", st)
+			}
+		})
+	}
+}
+
+// coverage returns whether the 95% one step predictive interval, after the
+// first n points of a local level series, contains the n+1th point.
+func coverage(rnd *rand.Rand, s *model.Stochastic, r *model.RCE, level *float64) bool {
+	f := s.Forecast(r.Noise(), r.Walk(), 1)
+	st, _ := stream.ToStudentT(f[0], r.Dof())
+	l, u, _ := uv.ConfidenceInterval(st, 0.95)
+
+	*level += rnd.NormFloat64()
+	v := *level + 2*rnd.NormFloat64()
+	r.Update(v)
+	s.Update(r.Noise(), r.Walk(), v)
+	return l <= v && v <= u
+}
+
+func TestStudentTCoverage(t *testing.T) {
+	tt := []struct {
+		name  string
+		burn  int
+		reps  int
+		evals int
+	}{
+		{"ten points", 10, 2000, 1},
+		{"ten thousand points", 10000, 1, 2000},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			hits := 0
+			for i := 0; i < tc.reps; i++ {
+				s := model.NewStochastic()
+				r := model.NewRCE()
+				level := 0.0
+				for j := 0; j < tc.burn; j++ {
+					level += rnd.NormFloat64()
+					v := level + 2*rnd.NormFloat64()
+					r.Update(v)
+					s.Update(r.Noise(), r.Walk(), v)
+				}
+				for j := 0; j < tc.evals; j++ {
+					if coverage(rnd, s, r, &level) {
+						hits++
+					}
+				}
+			}
+
+			cov := float64(hits) / float64(tc.reps*tc.evals)
+			if math.Abs(cov-0.95) > 0.02 {
+				t.Errorf("expected coverage near %v, but it was %v", 0.95, cov)
+			}
+		})
+	}
+}